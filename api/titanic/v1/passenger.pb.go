@@ -0,0 +1,833 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.31.0
+// 	protoc        (unknown)
+// source: titanic/v1/passenger.proto
+
+package titanicv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// FareBasis selects the fare used for fare statistics.
+type FareBasis int32
+
+const (
+	// The fare recorded per ticket.
+	FareBasis_FARE_BASIS_UNSPECIFIED FareBasis = 0
+	// The fare split between the passengers sharing the ticket.
+	FareBasis_FARE_BASIS_PER_PERSON FareBasis = 1
+)
+
+// Enum value maps for FareBasis.
+var (
+	FareBasis_name = map[int32]string{
+		0: "FARE_BASIS_UNSPECIFIED",
+		1: "FARE_BASIS_PER_PERSON",
+	}
+	FareBasis_value = map[string]int32{
+		"FARE_BASIS_UNSPECIFIED": 0,
+		"FARE_BASIS_PER_PERSON":  1,
+	}
+)
+
+func (x FareBasis) Enum() *FareBasis {
+	p := new(FareBasis)
+	*p = x
+	return p
+}
+
+func (x FareBasis) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (FareBasis) Descriptor() protoreflect.EnumDescriptor {
+	return file_titanic_v1_passenger_proto_enumTypes[0].Descriptor()
+}
+
+func (FareBasis) Type() protoreflect.EnumType {
+	return &file_titanic_v1_passenger_proto_enumTypes[0]
+}
+
+func (x FareBasis) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use FareBasis.Descriptor instead.
+func (FareBasis) EnumDescriptor() ([]byte, []int) {
+	return file_titanic_v1_passenger_proto_rawDescGZIP(), []int{0}
+}
+
+// Passenger is a row of titanic.csv.
+type Passenger struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	PassengerId int32  `protobuf:"varint,1,opt,name=passenger_id,json=passengerId,proto3" json:"passenger_id,omitempty"`
+	Survived    int32  `protobuf:"varint,2,opt,name=survived,proto3" json:"survived,omitempty"`
+	Pclass      int32  `protobuf:"varint,3,opt,name=pclass,proto3" json:"pclass,omitempty"`
+	Name        string `protobuf:"bytes,4,opt,name=name,proto3" json:"name,omitempty"`
+	Sex         string `protobuf:"bytes,5,opt,name=sex,proto3" json:"sex,omitempty"`
+	// Unset when the age is unknown.
+	Age    *float64 `protobuf:"fixed64,6,opt,name=age,proto3,oneof" json:"age,omitempty"`
+	SibSp  int32    `protobuf:"varint,7,opt,name=sib_sp,json=sibSp,proto3" json:"sib_sp,omitempty"`
+	Parch  int32    `protobuf:"varint,8,opt,name=parch,proto3" json:"parch,omitempty"`
+	Ticket string   `protobuf:"bytes,9,opt,name=ticket,proto3" json:"ticket,omitempty"`
+	Fare   float64  `protobuf:"fixed64,10,opt,name=fare,proto3" json:"fare,omitempty"`
+	// Empty when unknown.
+	Cabin string `protobuf:"bytes,11,opt,name=cabin,proto3" json:"cabin,omitempty"`
+	// C, Q or S; empty when unknown.
+	Embarked string `protobuf:"bytes,12,opt,name=embarked,proto3" json:"embarked,omitempty"`
+}
+
+func (x *Passenger) Reset() {
+	*x = Passenger{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_titanic_v1_passenger_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Passenger) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Passenger) ProtoMessage() {}
+
+func (x *Passenger) ProtoReflect() protoreflect.Message {
+	mi := &file_titanic_v1_passenger_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Passenger.ProtoReflect.Descriptor instead.
+func (*Passenger) Descriptor() ([]byte, []int) {
+	return file_titanic_v1_passenger_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Passenger) GetPassengerId() int32 {
+	if x != nil {
+		return x.PassengerId
+	}
+	return 0
+}
+
+func (x *Passenger) GetSurvived() int32 {
+	if x != nil {
+		return x.Survived
+	}
+	return 0
+}
+
+func (x *Passenger) GetPclass() int32 {
+	if x != nil {
+		return x.Pclass
+	}
+	return 0
+}
+
+func (x *Passenger) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Passenger) GetSex() string {
+	if x != nil {
+		return x.Sex
+	}
+	return ""
+}
+
+func (x *Passenger) GetAge() float64 {
+	if x != nil && x.Age != nil {
+		return *x.Age
+	}
+	return 0
+}
+
+func (x *Passenger) GetSibSp() int32 {
+	if x != nil {
+		return x.SibSp
+	}
+	return 0
+}
+
+func (x *Passenger) GetParch() int32 {
+	if x != nil {
+		return x.Parch
+	}
+	return 0
+}
+
+func (x *Passenger) GetTicket() string {
+	if x != nil {
+		return x.Ticket
+	}
+	return ""
+}
+
+func (x *Passenger) GetFare() float64 {
+	if x != nil {
+		return x.Fare
+	}
+	return 0
+}
+
+func (x *Passenger) GetCabin() string {
+	if x != nil {
+		return x.Cabin
+	}
+	return ""
+}
+
+func (x *Passenger) GetEmbarked() string {
+	if x != nil {
+		return x.Embarked
+	}
+	return ""
+}
+
+type GetPassengerRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	PassengerId int32 `protobuf:"varint,1,opt,name=passenger_id,json=passengerId,proto3" json:"passenger_id,omitempty"`
+}
+
+func (x *GetPassengerRequest) Reset() {
+	*x = GetPassengerRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_titanic_v1_passenger_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetPassengerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPassengerRequest) ProtoMessage() {}
+
+func (x *GetPassengerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_titanic_v1_passenger_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPassengerRequest.ProtoReflect.Descriptor instead.
+func (*GetPassengerRequest) Descriptor() ([]byte, []int) {
+	return file_titanic_v1_passenger_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *GetPassengerRequest) GetPassengerId() int32 {
+	if x != nil {
+		return x.PassengerId
+	}
+	return 0
+}
+
+type ListPassengersRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Filter expression, e.g. "Age < 12 OR (Sex = 'female' AND Pclass IN (1, 2))".
+	// Empty lists every passenger.
+	Filter string `protobuf:"bytes,1,opt,name=filter,proto3" json:"filter,omitempty"`
+}
+
+func (x *ListPassengersRequest) Reset() {
+	*x = ListPassengersRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_titanic_v1_passenger_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListPassengersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPassengersRequest) ProtoMessage() {}
+
+func (x *ListPassengersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_titanic_v1_passenger_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPassengersRequest.ProtoReflect.Descriptor instead.
+func (*ListPassengersRequest) Descriptor() ([]byte, []int) {
+	return file_titanic_v1_passenger_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ListPassengersRequest) GetFilter() string {
+	if x != nil {
+		return x.Filter
+	}
+	return ""
+}
+
+type GetPassengerAttributesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	PassengerId int32 `protobuf:"varint,1,opt,name=passenger_id,json=passengerId,proto3" json:"passenger_id,omitempty"`
+	// Attribute names of titanic.csv, e.g. Name, Age.
+	Attributes []string `protobuf:"bytes,2,rep,name=attributes,proto3" json:"attributes,omitempty"`
+}
+
+func (x *GetPassengerAttributesRequest) Reset() {
+	*x = GetPassengerAttributesRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_titanic_v1_passenger_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetPassengerAttributesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPassengerAttributesRequest) ProtoMessage() {}
+
+func (x *GetPassengerAttributesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_titanic_v1_passenger_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPassengerAttributesRequest.ProtoReflect.Descriptor instead.
+func (*GetPassengerAttributesRequest) Descriptor() ([]byte, []int) {
+	return file_titanic_v1_passenger_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetPassengerAttributesRequest) GetPassengerId() int32 {
+	if x != nil {
+		return x.PassengerId
+	}
+	return 0
+}
+
+func (x *GetPassengerAttributesRequest) GetAttributes() []string {
+	if x != nil {
+		return x.Attributes
+	}
+	return nil
+}
+
+type GetPassengerAttributesResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Attribute values formatted as in titanic.csv, keyed by attribute name.
+	Attributes map[string]string `protobuf:"bytes,1,rep,name=attributes,proto3" json:"attributes,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (x *GetPassengerAttributesResponse) Reset() {
+	*x = GetPassengerAttributesResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_titanic_v1_passenger_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetPassengerAttributesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPassengerAttributesResponse) ProtoMessage() {}
+
+func (x *GetPassengerAttributesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_titanic_v1_passenger_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPassengerAttributesResponse.ProtoReflect.Descriptor instead.
+func (*GetPassengerAttributesResponse) Descriptor() ([]byte, []int) {
+	return file_titanic_v1_passenger_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetPassengerAttributesResponse) GetAttributes() map[string]string {
+	if x != nil {
+		return x.Attributes
+	}
+	return nil
+}
+
+type GetFareHistogramRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	FareBasis FareBasis `protobuf:"varint,1,opt,name=fare_basis,json=fareBasis,proto3,enum=titanic.v1.FareBasis" json:"fare_basis,omitempty"`
+}
+
+func (x *GetFareHistogramRequest) Reset() {
+	*x = GetFareHistogramRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_titanic_v1_passenger_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetFareHistogramRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetFareHistogramRequest) ProtoMessage() {}
+
+func (x *GetFareHistogramRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_titanic_v1_passenger_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetFareHistogramRequest.ProtoReflect.Descriptor instead.
+func (*GetFareHistogramRequest) Descriptor() ([]byte, []int) {
+	return file_titanic_v1_passenger_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *GetFareHistogramRequest) GetFareBasis() FareBasis {
+	if x != nil {
+		return x.FareBasis
+	}
+	return FareBasis_FARE_BASIS_UNSPECIFIED
+}
+
+type GetFareHistogramResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Buckets in ascending percentile order.
+	Buckets []*GetFareHistogramResponse_Bucket `protobuf:"bytes,1,rep,name=buckets,proto3" json:"buckets,omitempty"`
+}
+
+func (x *GetFareHistogramResponse) Reset() {
+	*x = GetFareHistogramResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_titanic_v1_passenger_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetFareHistogramResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetFareHistogramResponse) ProtoMessage() {}
+
+func (x *GetFareHistogramResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_titanic_v1_passenger_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetFareHistogramResponse.ProtoReflect.Descriptor instead.
+func (*GetFareHistogramResponse) Descriptor() ([]byte, []int) {
+	return file_titanic_v1_passenger_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetFareHistogramResponse) GetBuckets() []*GetFareHistogramResponse_Bucket {
+	if x != nil {
+		return x.Buckets
+	}
+	return nil
+}
+
+type GetFareHistogramResponse_Bucket struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Upper percentile of the bucket, e.g. "25.00%".
+	Percentile string `protobuf:"bytes,1,opt,name=percentile,proto3" json:"percentile,omitempty"`
+	Count      int32  `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+}
+
+func (x *GetFareHistogramResponse_Bucket) Reset() {
+	*x = GetFareHistogramResponse_Bucket{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_titanic_v1_passenger_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetFareHistogramResponse_Bucket) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetFareHistogramResponse_Bucket) ProtoMessage() {}
+
+func (x *GetFareHistogramResponse_Bucket) ProtoReflect() protoreflect.Message {
+	mi := &file_titanic_v1_passenger_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetFareHistogramResponse_Bucket.ProtoReflect.Descriptor instead.
+func (*GetFareHistogramResponse_Bucket) Descriptor() ([]byte, []int) {
+	return file_titanic_v1_passenger_proto_rawDescGZIP(), []int{6, 0}
+}
+
+func (x *GetFareHistogramResponse_Bucket) GetPercentile() string {
+	if x != nil {
+		return x.Percentile
+	}
+	return ""
+}
+
+func (x *GetFareHistogramResponse_Bucket) GetCount() int32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+var File_titanic_v1_passenger_proto protoreflect.FileDescriptor
+
+var file_titanic_v1_passenger_proto_rawDesc = []byte{
+	0x0a, 0x1a, 0x74, 0x69, 0x74, 0x61, 0x6e, 0x69, 0x63, 0x2f, 0x76, 0x31, 0x2f, 0x70, 0x61, 0x73,
+	0x73, 0x65, 0x6e, 0x67, 0x65, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0a, 0x74, 0x69,
+	0x74, 0x61, 0x6e, 0x69, 0x63, 0x2e, 0x76, 0x31, 0x22, 0xb2, 0x02, 0x0a, 0x09, 0x50, 0x61, 0x73,
+	0x73, 0x65, 0x6e, 0x67, 0x65, 0x72, 0x12, 0x21, 0x0a, 0x0c, 0x70, 0x61, 0x73, 0x73, 0x65, 0x6e,
+	0x67, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0b, 0x70, 0x61,
+	0x73, 0x73, 0x65, 0x6e, 0x67, 0x65, 0x72, 0x49, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x73, 0x75, 0x72,
+	0x76, 0x69, 0x76, 0x65, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x73, 0x75, 0x72,
+	0x76, 0x69, 0x76, 0x65, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x70, 0x63, 0x6c, 0x61, 0x73, 0x73, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x70, 0x63, 0x6c, 0x61, 0x73, 0x73, 0x12, 0x12, 0x0a,
+	0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d,
+	0x65, 0x12, 0x10, 0x0a, 0x03, 0x73, 0x65, 0x78, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03,
+	0x73, 0x65, 0x78, 0x12, 0x15, 0x0a, 0x03, 0x61, 0x67, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x01,
+	0x48, 0x00, 0x52, 0x03, 0x61, 0x67, 0x65, 0x88, 0x01, 0x01, 0x12, 0x15, 0x0a, 0x06, 0x73, 0x69,
+	0x62, 0x5f, 0x73, 0x70, 0x18, 0x07, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x73, 0x69, 0x62, 0x53,
+	0x70, 0x12, 0x14, 0x0a, 0x05, 0x70, 0x61, 0x72, 0x63, 0x68, 0x18, 0x08, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x05, 0x70, 0x61, 0x72, 0x63, 0x68, 0x12, 0x16, 0x0a, 0x06, 0x74, 0x69, 0x63, 0x6b, 0x65,
+	0x74, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x74, 0x69, 0x63, 0x6b, 0x65, 0x74, 0x12,
+	0x12, 0x0a, 0x04, 0x66, 0x61, 0x72, 0x65, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x01, 0x52, 0x04, 0x66,
+	0x61, 0x72, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x63, 0x61, 0x62, 0x69, 0x6e, 0x18, 0x0b, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x05, 0x63, 0x61, 0x62, 0x69, 0x6e, 0x12, 0x1a, 0x0a, 0x08, 0x65, 0x6d, 0x62,
+	0x61, 0x72, 0x6b, 0x65, 0x64, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x65, 0x6d, 0x62,
+	0x61, 0x72, 0x6b, 0x65, 0x64, 0x42, 0x06, 0x0a, 0x04, 0x5f, 0x61, 0x67, 0x65, 0x22, 0x38, 0x0a,
+	0x13, 0x47, 0x65, 0x74, 0x50, 0x61, 0x73, 0x73, 0x65, 0x6e, 0x67, 0x65, 0x72, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x70, 0x61, 0x73, 0x73, 0x65, 0x6e, 0x67, 0x65,
+	0x72, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0b, 0x70, 0x61, 0x73, 0x73,
+	0x65, 0x6e, 0x67, 0x65, 0x72, 0x49, 0x64, 0x22, 0x2f, 0x0a, 0x15, 0x4c, 0x69, 0x73, 0x74, 0x50,
+	0x61, 0x73, 0x73, 0x65, 0x6e, 0x67, 0x65, 0x72, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x16, 0x0a, 0x06, 0x66, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x06, 0x66, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x22, 0x62, 0x0a, 0x1d, 0x47, 0x65, 0x74, 0x50,
+	0x61, 0x73, 0x73, 0x65, 0x6e, 0x67, 0x65, 0x72, 0x41, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74,
+	0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x70, 0x61, 0x73,
+	0x73, 0x65, 0x6e, 0x67, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x0b, 0x70, 0x61, 0x73, 0x73, 0x65, 0x6e, 0x67, 0x65, 0x72, 0x49, 0x64, 0x12, 0x1e, 0x0a, 0x0a,
+	0x61, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09,
+	0x52, 0x0a, 0x61, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x73, 0x22, 0xbb, 0x01, 0x0a,
+	0x1e, 0x47, 0x65, 0x74, 0x50, 0x61, 0x73, 0x73, 0x65, 0x6e, 0x67, 0x65, 0x72, 0x41, 0x74, 0x74,
+	0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x5a, 0x0a, 0x0a, 0x61, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x73, 0x18, 0x01, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x3a, 0x2e, 0x74, 0x69, 0x74, 0x61, 0x6e, 0x69, 0x63, 0x2e, 0x76, 0x31,
+	0x2e, 0x47, 0x65, 0x74, 0x50, 0x61, 0x73, 0x73, 0x65, 0x6e, 0x67, 0x65, 0x72, 0x41, 0x74, 0x74,
+	0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x2e,
+	0x41, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52,
+	0x0a, 0x61, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x73, 0x1a, 0x3d, 0x0a, 0x0f, 0x41,
+	0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10,
+	0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79,
+	0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x4f, 0x0a, 0x17, 0x47, 0x65,
+	0x74, 0x46, 0x61, 0x72, 0x65, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x67, 0x72, 0x61, 0x6d, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x34, 0x0a, 0x0a, 0x66, 0x61, 0x72, 0x65, 0x5f, 0x62, 0x61,
+	0x73, 0x69, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x15, 0x2e, 0x74, 0x69, 0x74, 0x61,
+	0x6e, 0x69, 0x63, 0x2e, 0x76, 0x31, 0x2e, 0x46, 0x61, 0x72, 0x65, 0x42, 0x61, 0x73, 0x69, 0x73,
+	0x52, 0x09, 0x66, 0x61, 0x72, 0x65, 0x42, 0x61, 0x73, 0x69, 0x73, 0x22, 0xa1, 0x01, 0x0a, 0x18,
+	0x47, 0x65, 0x74, 0x46, 0x61, 0x72, 0x65, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x67, 0x72, 0x61, 0x6d,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x45, 0x0a, 0x07, 0x62, 0x75, 0x63, 0x6b,
+	0x65, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2b, 0x2e, 0x74, 0x69, 0x74, 0x61,
+	0x6e, 0x69, 0x63, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x46, 0x61, 0x72, 0x65, 0x48, 0x69,
+	0x73, 0x74, 0x6f, 0x67, 0x72, 0x61, 0x6d, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x2e,
+	0x42, 0x75, 0x63, 0x6b, 0x65, 0x74, 0x52, 0x07, 0x62, 0x75, 0x63, 0x6b, 0x65, 0x74, 0x73, 0x1a,
+	0x3e, 0x0a, 0x06, 0x42, 0x75, 0x63, 0x6b, 0x65, 0x74, 0x12, 0x1e, 0x0a, 0x0a, 0x70, 0x65, 0x72,
+	0x63, 0x65, 0x6e, 0x74, 0x69, 0x6c, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x70,
+	0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x69, 0x6c, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x63, 0x6f, 0x75,
+	0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x2a,
+	0x42, 0x0a, 0x09, 0x46, 0x61, 0x72, 0x65, 0x42, 0x61, 0x73, 0x69, 0x73, 0x12, 0x1a, 0x0a, 0x16,
+	0x46, 0x41, 0x52, 0x45, 0x5f, 0x42, 0x41, 0x53, 0x49, 0x53, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45,
+	0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x19, 0x0a, 0x15, 0x46, 0x41, 0x52, 0x45,
+	0x5f, 0x42, 0x41, 0x53, 0x49, 0x53, 0x5f, 0x50, 0x45, 0x52, 0x5f, 0x50, 0x45, 0x52, 0x53, 0x4f,
+	0x4e, 0x10, 0x01, 0x32, 0xf8, 0x02, 0x0a, 0x10, 0x50, 0x61, 0x73, 0x73, 0x65, 0x6e, 0x67, 0x65,
+	0x72, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x46, 0x0a, 0x0c, 0x47, 0x65, 0x74, 0x50,
+	0x61, 0x73, 0x73, 0x65, 0x6e, 0x67, 0x65, 0x72, 0x12, 0x1f, 0x2e, 0x74, 0x69, 0x74, 0x61, 0x6e,
+	0x69, 0x63, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x50, 0x61, 0x73, 0x73, 0x65, 0x6e, 0x67,
+	0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x15, 0x2e, 0x74, 0x69, 0x74, 0x61,
+	0x6e, 0x69, 0x63, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x73, 0x73, 0x65, 0x6e, 0x67, 0x65, 0x72,
+	0x12, 0x4c, 0x0a, 0x0e, 0x4c, 0x69, 0x73, 0x74, 0x50, 0x61, 0x73, 0x73, 0x65, 0x6e, 0x67, 0x65,
+	0x72, 0x73, 0x12, 0x21, 0x2e, 0x74, 0x69, 0x74, 0x61, 0x6e, 0x69, 0x63, 0x2e, 0x76, 0x31, 0x2e,
+	0x4c, 0x69, 0x73, 0x74, 0x50, 0x61, 0x73, 0x73, 0x65, 0x6e, 0x67, 0x65, 0x72, 0x73, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x15, 0x2e, 0x74, 0x69, 0x74, 0x61, 0x6e, 0x69, 0x63, 0x2e,
+	0x76, 0x31, 0x2e, 0x50, 0x61, 0x73, 0x73, 0x65, 0x6e, 0x67, 0x65, 0x72, 0x30, 0x01, 0x12, 0x6f,
+	0x0a, 0x16, 0x47, 0x65, 0x74, 0x50, 0x61, 0x73, 0x73, 0x65, 0x6e, 0x67, 0x65, 0x72, 0x41, 0x74,
+	0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x73, 0x12, 0x29, 0x2e, 0x74, 0x69, 0x74, 0x61, 0x6e,
+	0x69, 0x63, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x50, 0x61, 0x73, 0x73, 0x65, 0x6e, 0x67,
+	0x65, 0x72, 0x41, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x2a, 0x2e, 0x74, 0x69, 0x74, 0x61, 0x6e, 0x69, 0x63, 0x2e, 0x76, 0x31,
+	0x2e, 0x47, 0x65, 0x74, 0x50, 0x61, 0x73, 0x73, 0x65, 0x6e, 0x67, 0x65, 0x72, 0x41, 0x74, 0x74,
+	0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x5d, 0x0a, 0x10, 0x47, 0x65, 0x74, 0x46, 0x61, 0x72, 0x65, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x67,
+	0x72, 0x61, 0x6d, 0x12, 0x23, 0x2e, 0x74, 0x69, 0x74, 0x61, 0x6e, 0x69, 0x63, 0x2e, 0x76, 0x31,
+	0x2e, 0x47, 0x65, 0x74, 0x46, 0x61, 0x72, 0x65, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x67, 0x72, 0x61,
+	0x6d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x24, 0x2e, 0x74, 0x69, 0x74, 0x61, 0x6e,
+	0x69, 0x63, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x46, 0x61, 0x72, 0x65, 0x48, 0x69, 0x73,
+	0x74, 0x6f, 0x67, 0x72, 0x61, 0x6d, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x71,
+	0x0a, 0x1b, 0x63, 0x6f, 0x6d, 0x2e, 0x73, 0x68, 0x69, 0x6e, 0x64, 0x65, 0x73, 0x61, 0x74, 0x69,
+	0x73, 0x68, 0x2e, 0x74, 0x69, 0x74, 0x61, 0x6e, 0x69, 0x63, 0x2e, 0x76, 0x31, 0x42, 0x0e, 0x50,
+	0x61, 0x73, 0x73, 0x65, 0x6e, 0x67, 0x65, 0x72, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x50, 0x01, 0x5a,
+	0x40, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x73, 0x68, 0x69, 0x6e,
+	0x64, 0x65, 0x73, 0x61, 0x74, 0x69, 0x73, 0x68, 0x2f, 0x74, 0x69, 0x74, 0x61, 0x6e, 0x69, 0x63,
+	0x2d, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x74, 0x69, 0x74,
+	0x61, 0x6e, 0x69, 0x63, 0x2f, 0x76, 0x31, 0x3b, 0x74, 0x69, 0x74, 0x61, 0x6e, 0x69, 0x63, 0x76,
+	0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_titanic_v1_passenger_proto_rawDescOnce sync.Once
+	file_titanic_v1_passenger_proto_rawDescData = file_titanic_v1_passenger_proto_rawDesc
+)
+
+func file_titanic_v1_passenger_proto_rawDescGZIP() []byte {
+	file_titanic_v1_passenger_proto_rawDescOnce.Do(func() {
+		file_titanic_v1_passenger_proto_rawDescData = protoimpl.X.CompressGZIP(file_titanic_v1_passenger_proto_rawDescData)
+	})
+	return file_titanic_v1_passenger_proto_rawDescData
+}
+
+var file_titanic_v1_passenger_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_titanic_v1_passenger_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
+var file_titanic_v1_passenger_proto_goTypes = []interface{}{
+	(FareBasis)(0),                          // 0: titanic.v1.FareBasis
+	(*Passenger)(nil),                       // 1: titanic.v1.Passenger
+	(*GetPassengerRequest)(nil),             // 2: titanic.v1.GetPassengerRequest
+	(*ListPassengersRequest)(nil),           // 3: titanic.v1.ListPassengersRequest
+	(*GetPassengerAttributesRequest)(nil),   // 4: titanic.v1.GetPassengerAttributesRequest
+	(*GetPassengerAttributesResponse)(nil),  // 5: titanic.v1.GetPassengerAttributesResponse
+	(*GetFareHistogramRequest)(nil),         // 6: titanic.v1.GetFareHistogramRequest
+	(*GetFareHistogramResponse)(nil),        // 7: titanic.v1.GetFareHistogramResponse
+	nil,                                     // 8: titanic.v1.GetPassengerAttributesResponse.AttributesEntry
+	(*GetFareHistogramResponse_Bucket)(nil), // 9: titanic.v1.GetFareHistogramResponse.Bucket
+}
+var file_titanic_v1_passenger_proto_depIdxs = []int32{
+	8, // 0: titanic.v1.GetPassengerAttributesResponse.attributes:type_name -> titanic.v1.GetPassengerAttributesResponse.AttributesEntry
+	0, // 1: titanic.v1.GetFareHistogramRequest.fare_basis:type_name -> titanic.v1.FareBasis
+	9, // 2: titanic.v1.GetFareHistogramResponse.buckets:type_name -> titanic.v1.GetFareHistogramResponse.Bucket
+	2, // 3: titanic.v1.PassengerService.GetPassenger:input_type -> titanic.v1.GetPassengerRequest
+	3, // 4: titanic.v1.PassengerService.ListPassengers:input_type -> titanic.v1.ListPassengersRequest
+	4, // 5: titanic.v1.PassengerService.GetPassengerAttributes:input_type -> titanic.v1.GetPassengerAttributesRequest
+	6, // 6: titanic.v1.PassengerService.GetFareHistogram:input_type -> titanic.v1.GetFareHistogramRequest
+	1, // 7: titanic.v1.PassengerService.GetPassenger:output_type -> titanic.v1.Passenger
+	1, // 8: titanic.v1.PassengerService.ListPassengers:output_type -> titanic.v1.Passenger
+	5, // 9: titanic.v1.PassengerService.GetPassengerAttributes:output_type -> titanic.v1.GetPassengerAttributesResponse
+	7, // 10: titanic.v1.PassengerService.GetFareHistogram:output_type -> titanic.v1.GetFareHistogramResponse
+	7, // [7:11] is the sub-list for method output_type
+	3, // [3:7] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
+}
+
+func init() { file_titanic_v1_passenger_proto_init() }
+func file_titanic_v1_passenger_proto_init() {
+	if File_titanic_v1_passenger_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_titanic_v1_passenger_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Passenger); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_titanic_v1_passenger_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetPassengerRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_titanic_v1_passenger_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListPassengersRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_titanic_v1_passenger_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetPassengerAttributesRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_titanic_v1_passenger_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetPassengerAttributesResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_titanic_v1_passenger_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetFareHistogramRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_titanic_v1_passenger_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetFareHistogramResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_titanic_v1_passenger_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetFareHistogramResponse_Bucket); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_titanic_v1_passenger_proto_msgTypes[0].OneofWrappers = []interface{}{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_titanic_v1_passenger_proto_rawDesc,
+			NumEnums:      1,
+			NumMessages:   9,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_titanic_v1_passenger_proto_goTypes,
+		DependencyIndexes: file_titanic_v1_passenger_proto_depIdxs,
+		EnumInfos:         file_titanic_v1_passenger_proto_enumTypes,
+		MessageInfos:      file_titanic_v1_passenger_proto_msgTypes,
+	}.Build()
+	File_titanic_v1_passenger_proto = out.File
+	file_titanic_v1_passenger_proto_rawDesc = nil
+	file_titanic_v1_passenger_proto_goTypes = nil
+	file_titanic_v1_passenger_proto_depIdxs = nil
+}
@@ -0,0 +1,258 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: titanic/v1/passenger.proto
+
+package titanicv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	PassengerService_GetPassenger_FullMethodName           = "/titanic.v1.PassengerService/GetPassenger"
+	PassengerService_ListPassengers_FullMethodName         = "/titanic.v1.PassengerService/ListPassengers"
+	PassengerService_GetPassengerAttributes_FullMethodName = "/titanic.v1.PassengerService/GetPassengerAttributes"
+	PassengerService_GetFareHistogram_FullMethodName       = "/titanic.v1.PassengerService/GetFareHistogram"
+)
+
+// PassengerServiceClient is the client API for PassengerService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type PassengerServiceClient interface {
+	// GetPassenger looks up a passenger by PassengerId.
+	GetPassenger(ctx context.Context, in *GetPassengerRequest, opts ...grpc.CallOption) (*Passenger, error)
+	// ListPassengers streams the passengers matching a filter, in dataset
+	// order.
+	ListPassengers(ctx context.Context, in *ListPassengersRequest, opts ...grpc.CallOption) (PassengerService_ListPassengersClient, error)
+	// GetPassengerAttributes returns selected attributes of a passenger.
+	GetPassengerAttributes(ctx context.Context, in *GetPassengerAttributesRequest, opts ...grpc.CallOption) (*GetPassengerAttributesResponse, error)
+	// GetFareHistogram counts passengers per fare percentile bucket.
+	GetFareHistogram(ctx context.Context, in *GetFareHistogramRequest, opts ...grpc.CallOption) (*GetFareHistogramResponse, error)
+}
+
+type passengerServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewPassengerServiceClient(cc grpc.ClientConnInterface) PassengerServiceClient {
+	return &passengerServiceClient{cc}
+}
+
+func (c *passengerServiceClient) GetPassenger(ctx context.Context, in *GetPassengerRequest, opts ...grpc.CallOption) (*Passenger, error) {
+	out := new(Passenger)
+	err := c.cc.Invoke(ctx, PassengerService_GetPassenger_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *passengerServiceClient) ListPassengers(ctx context.Context, in *ListPassengersRequest, opts ...grpc.CallOption) (PassengerService_ListPassengersClient, error) {
+	stream, err := c.cc.NewStream(ctx, &PassengerService_ServiceDesc.Streams[0], PassengerService_ListPassengers_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &passengerServiceListPassengersClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type PassengerService_ListPassengersClient interface {
+	Recv() (*Passenger, error)
+	grpc.ClientStream
+}
+
+type passengerServiceListPassengersClient struct {
+	grpc.ClientStream
+}
+
+func (x *passengerServiceListPassengersClient) Recv() (*Passenger, error) {
+	m := new(Passenger)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *passengerServiceClient) GetPassengerAttributes(ctx context.Context, in *GetPassengerAttributesRequest, opts ...grpc.CallOption) (*GetPassengerAttributesResponse, error) {
+	out := new(GetPassengerAttributesResponse)
+	err := c.cc.Invoke(ctx, PassengerService_GetPassengerAttributes_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *passengerServiceClient) GetFareHistogram(ctx context.Context, in *GetFareHistogramRequest, opts ...grpc.CallOption) (*GetFareHistogramResponse, error) {
+	out := new(GetFareHistogramResponse)
+	err := c.cc.Invoke(ctx, PassengerService_GetFareHistogram_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// PassengerServiceServer is the server API for PassengerService service.
+// All implementations must embed UnimplementedPassengerServiceServer
+// for forward compatibility
+type PassengerServiceServer interface {
+	// GetPassenger looks up a passenger by PassengerId.
+	GetPassenger(context.Context, *GetPassengerRequest) (*Passenger, error)
+	// ListPassengers streams the passengers matching a filter, in dataset
+	// order.
+	ListPassengers(*ListPassengersRequest, PassengerService_ListPassengersServer) error
+	// GetPassengerAttributes returns selected attributes of a passenger.
+	GetPassengerAttributes(context.Context, *GetPassengerAttributesRequest) (*GetPassengerAttributesResponse, error)
+	// GetFareHistogram counts passengers per fare percentile bucket.
+	GetFareHistogram(context.Context, *GetFareHistogramRequest) (*GetFareHistogramResponse, error)
+	mustEmbedUnimplementedPassengerServiceServer()
+}
+
+// UnimplementedPassengerServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedPassengerServiceServer struct {
+}
+
+func (UnimplementedPassengerServiceServer) GetPassenger(context.Context, *GetPassengerRequest) (*Passenger, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPassenger not implemented")
+}
+func (UnimplementedPassengerServiceServer) ListPassengers(*ListPassengersRequest, PassengerService_ListPassengersServer) error {
+	return status.Errorf(codes.Unimplemented, "method ListPassengers not implemented")
+}
+func (UnimplementedPassengerServiceServer) GetPassengerAttributes(context.Context, *GetPassengerAttributesRequest) (*GetPassengerAttributesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPassengerAttributes not implemented")
+}
+func (UnimplementedPassengerServiceServer) GetFareHistogram(context.Context, *GetFareHistogramRequest) (*GetFareHistogramResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetFareHistogram not implemented")
+}
+func (UnimplementedPassengerServiceServer) mustEmbedUnimplementedPassengerServiceServer() {}
+
+// UnsafePassengerServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to PassengerServiceServer will
+// result in compilation errors.
+type UnsafePassengerServiceServer interface {
+	mustEmbedUnimplementedPassengerServiceServer()
+}
+
+func RegisterPassengerServiceServer(s grpc.ServiceRegistrar, srv PassengerServiceServer) {
+	s.RegisterService(&PassengerService_ServiceDesc, srv)
+}
+
+func _PassengerService_GetPassenger_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPassengerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PassengerServiceServer).GetPassenger(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PassengerService_GetPassenger_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PassengerServiceServer).GetPassenger(ctx, req.(*GetPassengerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PassengerService_ListPassengers_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListPassengersRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PassengerServiceServer).ListPassengers(m, &passengerServiceListPassengersServer{stream})
+}
+
+type PassengerService_ListPassengersServer interface {
+	Send(*Passenger) error
+	grpc.ServerStream
+}
+
+type passengerServiceListPassengersServer struct {
+	grpc.ServerStream
+}
+
+func (x *passengerServiceListPassengersServer) Send(m *Passenger) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _PassengerService_GetPassengerAttributes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPassengerAttributesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PassengerServiceServer).GetPassengerAttributes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PassengerService_GetPassengerAttributes_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PassengerServiceServer).GetPassengerAttributes(ctx, req.(*GetPassengerAttributesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PassengerService_GetFareHistogram_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetFareHistogramRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PassengerServiceServer).GetFareHistogram(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PassengerService_GetFareHistogram_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PassengerServiceServer).GetFareHistogram(ctx, req.(*GetFareHistogramRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// PassengerService_ServiceDesc is the grpc.ServiceDesc for PassengerService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var PassengerService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "titanic.v1.PassengerService",
+	HandlerType: (*PassengerServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetPassenger",
+			Handler:    _PassengerService_GetPassenger_Handler,
+		},
+		{
+			MethodName: "GetPassengerAttributes",
+			Handler:    _PassengerService_GetPassengerAttributes_Handler,
+		},
+		{
+			MethodName: "GetFareHistogram",
+			Handler:    _PassengerService_GetFareHistogram_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ListPassengers",
+			Handler:       _PassengerService_ListPassengers_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "titanic/v1/passenger.proto",
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/shindesatish/titanic-service/internal/app/export"
+	"github.com/shindesatish/titanic-service/internal/app/query"
+	"github.com/shindesatish/titanic-service/pkg/model"
+)
+
+func runExport(args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	repoFlags := addRepoFlags(fs)
+	format := fs.String("format", export.FormatCSV, "output format: "+strings.Join(export.Formats, ", "))
+	output := fs.String("o", "", "write to this file instead of stdout")
+	where := fs.String("filter", "", "filter expression, e.g. \"Age < 12 OR Sex = 'female'\"")
+	impute := addImputeFlags(fs)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: titanic export [flags] [Attribute=value[,value...] ...]")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	filter := model.PassengerFilter{}
+	for _, arg := range fs.Args() {
+		attribute, values, ok := strings.Cut(arg, "=")
+		if !ok {
+			return fmt.Errorf("invalid filter %q, expected Attribute=value", arg)
+		}
+		filter[attribute] = append(filter[attribute], strings.Split(values, ",")...)
+	}
+	var expression *query.Expr
+	if strings.TrimSpace(*where) != "" {
+		var err error
+		if expression, err = query.Parse(*where); err != nil {
+			return fmt.Errorf("invalid filter: %v", err)
+		}
+	}
+
+	out := os.Stdout
+	if *output != "" {
+		file, err := os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		out = file
+	}
+	writer := bufio.NewWriter(out)
+
+	encoder, err := export.NewEncoder(writer, *format, export.Options{})
+	if err != nil {
+		return err
+	}
+
+	svc, closeRepo, err := openService(repoFlags)
+	if err != nil {
+		return err
+	}
+	defer closeRepo()
+
+	ctx := context.Background()
+	imputer, err := impute.imputer(ctx, svc)
+	if err != nil {
+		return err
+	}
+	encode := encoder.Encode
+	if imputer != nil {
+		encode = func(passenger *model.Passenger) error {
+			imputed := *passenger
+			imputer.Apply(&imputed)
+			return encoder.Encode(&imputed)
+		}
+	}
+
+	if err := svc.StreamFilteredPassengers(ctx, filter, expression, encode); err != nil {
+		return err
+	}
+	if err := encoder.Close(); err != nil {
+		return err
+	}
+	return writer.Flush()
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/shindesatish/titanic-service/internal/app/repository"
+	"github.com/shindesatish/titanic-service/internal/app/service"
+	"github.com/shindesatish/titanic-service/internal/app/validation"
+)
+
+func runImport(args []string) error {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	dbPath := fs.String("db", "./datastore/titanic.db", "path of the SQLite database to import into")
+	mode := fs.String("mode", string(repository.ImportUpsert), "how to merge the import: upsert or replace")
+	dryRun := fs.Bool("dry-run", false, "validate and report the changes without applying them")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: titanic import [flags] file.csv")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return fmt.Errorf("expected exactly one CSV file")
+	}
+
+	file, err := os.Open(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	passengers, rowErrors, err := validation.ParseCSV(file)
+	if err != nil {
+		return err
+	}
+	if len(rowErrors) > 0 {
+		for _, rowErr := range rowErrors {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", fs.Arg(0), rowErr)
+		}
+		return fmt.Errorf("%d invalid rows, nothing imported", len(rowErrors))
+	}
+
+	repo, closeRepo, err := repository.Open(repository.Options{UseSQLite: true, SQLitePath: *dbPath})
+	if err != nil {
+		return err
+	}
+	defer closeRepo()
+
+	result, err := service.NewPassengerService(repo).ImportPassengers(context.Background(), passengers, repository.ImportMode(*mode), *dryRun, nil)
+	if err != nil {
+		return err
+	}
+	return printJSON(result)
+}
@@ -0,0 +1,138 @@
+// Command titanic works with the Titanic passenger dataset from the command
+// line. It reuses the service and repository packages, so everything the API
+// offers can be scripted without a running server.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/joho/godotenv"
+	"github.com/shindesatish/titanic-service/internal/app/imputation"
+	"github.com/shindesatish/titanic-service/internal/app/repository"
+	"github.com/shindesatish/titanic-service/internal/app/service"
+)
+
+const usage = `Usage: titanic <command> [flags] [args]
+
+Commands:
+  serve      Start the HTTP API server
+  import     Import a CSV file into the SQLite database
+  export     Write the passenger dataset as CSV, JSON, NDJSON, Parquet or Arrow
+  get        Print one passenger by PassengerId
+  stats      Print summary, survival or fare histogram statistics
+  validate   Print a data-quality report for a CSV file or the active dataset
+  train      Train the survival model and store it for the predict endpoint
+  evaluate   Cross-validate a stored model and print its metrics
+
+Run "titanic <command> -h" for the flags of a command.
+`
+
+type command struct {
+	name string
+	run  func(args []string) error
+}
+
+var commands = []command{
+	{"serve", runServe},
+	{"import", runImport},
+	{"export", runExport},
+	{"get", runGet},
+	{"stats", runStats},
+	{"validate", runValidate},
+	{"train", runTrain},
+	{"evaluate", runEvaluate},
+}
+
+func main() {
+	// The .env file is optional for the CLI; flags and the environment win
+	_ = godotenv.Load()
+
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	for _, cmd := range commands {
+		if cmd.name == os.Args[1] {
+			if err := cmd.run(os.Args[2:]); err != nil {
+				fmt.Fprintf(os.Stderr, "titanic %s: %v\n", cmd.name, err)
+				os.Exit(1)
+			}
+			return
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "titanic: unknown command %q\n\n%s", os.Args[1], usage)
+	os.Exit(2)
+}
+
+// repoFlags are the backend selection flags shared by every command.
+type repoFlags struct {
+	sqlite  *bool
+	csvPath *string
+	dbPath  *string
+	lenient *bool
+}
+
+func addRepoFlags(fs *flag.FlagSet) *repoFlags {
+	return &repoFlags{
+		sqlite:  fs.Bool("sqlite", os.Getenv("USE_SQLITE") == "true", "use the SQLite backend instead of CSV; defaults to USE_SQLITE"),
+		csvPath: fs.String("csv", "./datastore/titanic.csv", "path of the CSV dataset"),
+		dbPath:  fs.String("db", "./datastore/titanic.db", "path of the SQLite database"),
+		lenient: fs.Bool("lenient", os.Getenv("LENIENT_LOAD") == "true", "skip invalid rows instead of failing; defaults to LENIENT_LOAD"),
+	}
+}
+
+func (f *repoFlags) options() repository.Options {
+	return repository.Options{
+		UseSQLite:  *f.sqlite,
+		CSVPath:    *f.csvPath,
+		SQLitePath: *f.dbPath,
+		Lenient:    *f.lenient,
+	}
+}
+
+// openService opens the configured repository and wraps it in a
+// PassengerService. The returned function closes the repository.
+func openService(f *repoFlags) (*service.PassengerService, func() error, error) {
+	repo, closeRepo, err := repository.Open(f.options())
+	if err != nil {
+		return nil, nil, err
+	}
+	return service.NewPassengerService(repo), closeRepo, nil
+}
+
+// imputeFlags select an imputation strategy for commands that read
+// passengers.
+type imputeFlags struct {
+	strategy *string
+	by       *string
+}
+
+func addImputeFlags(fs *flag.FlagSet) *imputeFlags {
+	return &imputeFlags{
+		strategy: fs.String("impute", imputation.None, "fill missing Age and Embarked: "+strings.Join(imputation.Strategies(), ", ")),
+		by:       fs.String("impute-by", "", "comma separated attributes to impute within, e.g. Pclass,Sex"),
+	}
+}
+
+// imputer fits the selected strategy, returning nil for none.
+func (f *imputeFlags) imputer(ctx context.Context, svc *service.PassengerService) (*imputation.Imputer, error) {
+	var by []string
+	if *f.by != "" {
+		by = strings.Split(*f.by, ",")
+	}
+	return svc.FitImputer(ctx, *f.strategy, by)
+}
+
+// printJSON writes v to stdout as indented JSON.
+func printJSON(v interface{}) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
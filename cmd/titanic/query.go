@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strconv"
+
+	"github.com/shindesatish/titanic-service/internal/app/service"
+)
+
+func runGet(args []string) error {
+	fs := flag.NewFlagSet("get", flag.ExitOnError)
+	repoFlags := addRepoFlags(fs)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: titanic get [flags] <id>")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return fmt.Errorf("expected exactly one passenger ID")
+	}
+
+	passengerID, err := strconv.Atoi(fs.Arg(0))
+	if err != nil || passengerID <= 0 {
+		return fmt.Errorf("invalid passenger ID %q", fs.Arg(0))
+	}
+
+	svc, closeRepo, err := openService(repoFlags)
+	if err != nil {
+		return err
+	}
+	defer closeRepo()
+
+	passenger, err := svc.GetPassengerByID(context.Background(), uint(passengerID))
+	if err != nil {
+		return err
+	}
+	return printJSON(passenger)
+}
+
+func runStats(args []string) error {
+	fs := flag.NewFlagSet("stats", flag.ExitOnError)
+	repoFlags := addRepoFlags(fs)
+	by := fs.String("by", "Sex", "attribute to group survival by")
+	fare := fs.String("fare", string(service.FareRaw), "fare basis for summary and histogram: raw or per_person")
+	impute := addImputeFlags(fs)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: titanic stats [flags] summary|survival|histogram")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return fmt.Errorf("expected one of summary, survival or histogram")
+	}
+	if basis := service.FareBasis(*fare); basis != service.FareRaw && basis != service.FarePerPerson {
+		return fmt.Errorf("invalid fare basis %q", *fare)
+	}
+
+	svc, closeRepo, err := openService(repoFlags)
+	if err != nil {
+		return err
+	}
+	defer closeRepo()
+
+	ctx := context.Background()
+	switch fs.Arg(0) {
+	case "summary":
+		imputer, err := impute.imputer(ctx, svc)
+		if err != nil {
+			return err
+		}
+		stats, err := svc.GetSummaryStats(ctx, service.FareBasis(*fare), imputer)
+		if err != nil {
+			return err
+		}
+		return printJSON(stats)
+	case "survival":
+		groups, err := svc.GetSurvivalStats(ctx, *by)
+		if err != nil {
+			return err
+		}
+		return printJSON(groups)
+	case "histogram":
+		histogram, err := svc.GetFareHistogramBy(ctx, service.FareBasis(*fare))
+		if err != nil {
+			return err
+		}
+		return printJSON(histogram)
+	default:
+		fs.Usage()
+		return fmt.Errorf("unknown statistic %q", fs.Arg(0))
+	}
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"flag"
+
+	"github.com/shindesatish/titanic-service/internal/app/server"
+)
+
+// runServe serves the API like the titanic-service binary. Flags default to
+// the same environment variables and override them.
+func runServe(args []string) error {
+	env, err := server.OptionsFromEnv()
+	if err != nil {
+		return err
+	}
+
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	repoFlags := addRepoFlags(fs)
+	port := fs.String("port", env.Port, "port to listen on; defaults to PORT")
+	grpcPort := fs.String("grpc-port", env.GRPCPort, "port to serve gRPC on; defaults to GRPC_PORT")
+	cacheSize := fs.Int("cache-size", env.Repository.CacheSize, "enable the response cache with this many entries; defaults to CACHE_SIZE when USE_CACHE is set")
+	cacheTTL := fs.Duration("cache-ttl", env.Repository.CacheTTL, "lifetime of response cache entries; defaults to CACHE_TTL")
+	modelDir := fs.String("model-dir", env.ModelDir, "directory of the stored models; defaults to MODEL_DIR")
+	fs.Parse(args)
+
+	opts := server.Options{
+		Repository: repoFlags.options(),
+		ModelDir:   *modelDir,
+		Port:       *port,
+		GRPCPort:   *grpcPort,
+	}
+	opts.Repository.CacheSize = *cacheSize
+	opts.Repository.CacheTTL = *cacheTTL
+	return server.Run(opts)
+}
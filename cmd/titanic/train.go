@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/shindesatish/titanic-service/internal/app/prediction"
+	"github.com/shindesatish/titanic-service/internal/app/server"
+	"github.com/shindesatish/titanic-service/internal/app/service"
+)
+
+func runTrain(args []string) error {
+	fs := flag.NewFlagSet("train", flag.ExitOnError)
+	repoFlags := addRepoFlags(fs)
+	modelDir := fs.String("model-dir", server.Getenv("MODEL_DIR", "./datastore/models"), "directory of the stored models; defaults to MODEL_DIR")
+	name := fs.String("name", service.DefaultModel, "name to store the model under")
+	learningRate := fs.Float64("learning-rate", prediction.DefaultTrainOptions.LearningRate, "gradient descent step size")
+	epochs := fs.Int("epochs", prediction.DefaultTrainOptions.Epochs, "number of gradient descent passes over the dataset")
+	l2 := fs.Float64("l2", prediction.DefaultTrainOptions.L2, "L2 regularization strength")
+	fs.Parse(args)
+
+	if !prediction.ValidName(*name) {
+		return fmt.Errorf("invalid model name %q", *name)
+	}
+
+	svc, closeRepo, err := openService(repoFlags)
+	if err != nil {
+		return err
+	}
+	defer closeRepo()
+	svc.Models = prediction.NewStore(*modelDir)
+
+	m, err := svc.TrainModel(context.Background(), *name, prediction.TrainOptions{
+		LearningRate: *learningRate,
+		Epochs:       *epochs,
+		L2:           *l2,
+	})
+	if err != nil {
+		return err
+	}
+	return printJSON(m)
+}
+
+func runEvaluate(args []string) error {
+	fs := flag.NewFlagSet("evaluate", flag.ExitOnError)
+	repoFlags := addRepoFlags(fs)
+	modelDir := fs.String("model-dir", server.Getenv("MODEL_DIR", "./datastore/models"), "directory of the stored models; defaults to MODEL_DIR")
+	name := fs.String("name", service.DefaultModel, "name of the stored model to evaluate")
+	folds := fs.Int("folds", 5, "number of cross-validation folds")
+	seed := fs.Int64("seed", 1, "seed of the fold shuffle")
+	fs.Parse(args)
+
+	svc, closeRepo, err := openService(repoFlags)
+	if err != nil {
+		return err
+	}
+	defer closeRepo()
+	svc.Models = prediction.NewStore(*modelDir)
+
+	evaluation, err := svc.EvaluateModel(context.Background(), *name, *folds, *seed)
+	if err != nil {
+		return err
+	}
+	return printJSON(evaluation)
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/shindesatish/titanic-service/internal/app/validation"
+)
+
+func runValidate(args []string) error {
+	fs := flag.NewFlagSet("validate", flag.ExitOnError)
+	repoFlags := addRepoFlags(fs)
+	asJSON := fs.Bool("json", false, "print the report as JSON")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: titanic validate [flags] [file.csv]")
+		fmt.Fprintln(fs.Output(), "Validates the configured backend when no file is given.")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	var report *validation.Report
+	if fs.NArg() > 0 {
+		file, err := os.Open(fs.Arg(0))
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		if report, err = validation.ValidateCSV(fs.Arg(0), file); err != nil {
+			return err
+		}
+	} else {
+		svc, closeRepo, err := openService(repoFlags)
+		if err != nil {
+			return err
+		}
+		defer closeRepo()
+
+		if report, err = svc.ValidateDataset(context.Background()); err != nil {
+			return err
+		}
+	}
+
+	if *asJSON {
+		if err := printJSON(report); err != nil {
+			return err
+		}
+	} else if err := report.WriteText(os.Stdout); err != nil {
+		return err
+	}
+	if !report.Valid() {
+		return fmt.Errorf("%d of %d rows are invalid", report.InvalidRows, report.Rows)
+	}
+	return nil
+}
@@ -1,261 +0,0 @@
-// GENERATED BY THE COMMAND ABOVE; DO NOT EDIT
-// This file was generated by swaggo/swag
-
-package docs
-
-import (
-	"bytes"
-	"encoding/json"
-	"strings"
-
-	"github.com/alecthomas/template"
-	"github.com/swaggo/swag"
-)
-
-var doc = `{
-    "schemes": {{ marshal .Schemes }},
-    "swagger": "2.0",
-    "info": {
-        "description": "{{.Description}}",
-        "title": "{{.Title}}",
-        "termsOfService": "http://swagger.io/terms/",
-        "contact": {
-            "name": "API Support",
-            "url": "http://www.swagger.io/support"
-        },
-        "version": "{{.Version}}"
-    },
-    "host": "{{.Host}}",
-    "basePath": "{{.BasePath}}",
-    "paths": {
-        "/fare-histogram": {
-            "get": {
-                "description": "Get a histogram of fare prices in percentiles",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "passengers"
-                ],
-                "summary": "Get fare histogram",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "integer"
-                            }
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
-                    }
-                }
-            }
-        },
-        "/passenger-attributes/{id}": {
-            "get": {
-                "description": "Get selected attributes of passenger by PassengerId in JSON format",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "passengers"
-                ],
-                "summary": "Get selected attributes of passenger by ID",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Passenger ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "array",
-                        "description": "List of attributes to retrieve",
-                        "name": "attributes",
-                        "in": "query",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
-                    }
-                }
-            }
-        },
-        "/passengers": {
-            "get": {
-                "description": "Get a list of all passengers in JSON format",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "passengers"
-                ],
-                "summary": "Get all passengers",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/model.Passenger"
-                            }
-                        }
-                    }
-                }
-            }
-        },
-        "/passengers/{id}": {
-            "get": {
-                "description": "Get passenger data by PassengerId in JSON format",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "passengers"
-                ],
-                "summary": "Get passenger by ID",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Passenger ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/model.Passenger"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
-                    }
-                }
-            }
-        }
-    },
-    "definitions": {
-        "model.Passenger": {
-            "type": "object",
-            "properties": {
-                "Age": {
-                    "type": "number"
-                },
-                "Cabin": {
-                    "type": "string"
-                },
-                "Embarked": {
-                    "type": "string"
-                },
-                "Fare": {
-                    "type": "number"
-                },
-                "Name": {
-                    "type": "string"
-                },
-                "Parch": {
-                    "type": "integer"
-                },
-                "PassengerId": {
-                    "type": "integer"
-                },
-                "Pclass": {
-                    "type": "integer"
-                },
-                "Sex": {
-                    "type": "string"
-                },
-                "SibSp": {
-                    "type": "integer"
-                },
-                "Survived": {
-                    "type": "integer"
-                },
-                "Ticket": {
-                    "type": "string"
-                }
-            }
-        }
-    }
-}`
-
-type swaggerInfo struct {
-	Version     string
-	Host        string
-	BasePath    string
-	Schemes     []string
-	Title       string
-	Description string
-}
-
-// SwaggerInfo holds exported Swagger Info so clients can modify it
-var SwaggerInfo = swaggerInfo{
-	Version:     "1.0",
-	Host:        "localhost:8080",
-	BasePath:    "/v1",
-	Schemes:     []string{},
-	Title:       "Titanic Service API",
-	Description: "API for accessing Titanic passenger data",
-}
-
-type s struct{}
-
-func (s *s) ReadDoc() string {
-	sInfo := SwaggerInfo
-	sInfo.Description = strings.Replace(sInfo.Description, "\n", "\\n", -1)
-
-	t, err := template.New("swagger_info").Funcs(template.FuncMap{
-		"marshal": func(v interface{}) string {
-			a, _ := json.Marshal(v)
-			return string(a)
-		},
-	}).Parse(doc)
-	if err != nil {
-		return doc
-	}
-
-	var tpl bytes.Buffer
-	if err := t.Execute(&tpl, sInfo); err != nil {
-		return doc
-	}
-
-	return tpl.String()
-}
-
-func init() {
-	swag.Register(swag.Name, &s{})
-}
@@ -1,5 +1,7 @@
 package dto
 
+import "github.com/shindesatish/titanic-service/pkg/model"
+
 // Validate the accepted attributes
 var AllowedAttributes = []string{
 	"PassengerID",
@@ -29,3 +31,78 @@ func Contains(s []string, e string) bool {
 	}
 	return false
 }
+
+// PassengerResponse is a passenger together with the attributes derived
+// from it, as returned by the passenger endpoints.
+type PassengerResponse struct {
+	model.Passenger
+	model.ParsedName
+	model.ParsedCabin
+	model.ParsedTicket
+	TicketPartySize int     `json:"TicketPartySize,omitempty"`
+	FarePerPerson   float64 `json:"FarePerPerson,omitempty"`
+	// Imputed names the fields filled in by imputation rather than read
+	// from the dataset.
+	Imputed []string `json:"Imputed,omitempty"`
+}
+
+func NewPassengerResponse(passenger *model.Passenger) PassengerResponse {
+	return PassengerResponse{
+		Passenger:    *passenger,
+		ParsedName:   model.ParseName(passenger.Name),
+		ParsedCabin:  model.ParseCabin(passenger.Cabin),
+		ParsedTicket: model.ParseTicket(passenger.Ticket),
+	}
+}
+
+// WithTicketParty sets the number of passengers sharing the ticket and the
+// Fare split between them.
+func (r PassengerResponse) WithTicketParty(size int) PassengerResponse {
+	if size < 1 {
+		return r
+	}
+	r.TicketPartySize = size
+	r.FarePerPerson = model.FarePerPerson(r.Fare, size)
+	return r
+}
+
+// BatchGetRequest lists the PassengerIds to look up in one call.
+type BatchGetRequest struct {
+	IDs []uint `json:"ids" binding:"required"`
+}
+
+// BatchGetResponse holds the passengers found by a batch lookup, in the
+// order requested, and the requested IDs without a passenger.
+type BatchGetResponse struct {
+	Passengers []PassengerResponse `json:"passengers"`
+	MissingIDs []uint              `json:"missing_ids"`
+}
+
+// PredictRequest describes the passenger to score. It uses the field names
+// of the passenger responses; Age may be omitted when unknown.
+type PredictRequest struct {
+	Sex      string   `json:"Sex" binding:"required,oneof=male female"`
+	Pclass   int      `json:"Pclass" binding:"required,min=1,max=3"`
+	Age      *float64 `json:"Age" binding:"omitempty,gt=0"`
+	Fare     float64  `json:"Fare" binding:"min=0"`
+	Embarked string   `json:"Embarked" binding:"omitempty,oneof=C Q S"`
+	SibSp    int      `json:"SibSp" binding:"min=0"`
+	Parch    int      `json:"Parch" binding:"min=0"`
+}
+
+// Passenger returns the request as a passenger with the remaining fields
+// left blank.
+func (r PredictRequest) Passenger() *model.Passenger {
+	passenger := &model.Passenger{
+		Pclass:   r.Pclass,
+		Sex:      r.Sex,
+		SibSp:    r.SibSp,
+		Parch:    r.Parch,
+		Fare:     r.Fare,
+		Embarked: r.Embarked,
+	}
+	if r.Age != nil {
+		passenger.Age = model.NewNullFloat64(*r.Age)
+	}
+	return passenger
+}
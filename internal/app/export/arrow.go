@@ -0,0 +1,122 @@
+// internal/app/export/arrow.go
+package export
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/apache/arrow/go/v14/arrow"
+	"github.com/apache/arrow/go/v14/arrow/array"
+	"github.com/apache/arrow/go/v14/arrow/ipc"
+	"github.com/apache/arrow/go/v14/arrow/memory"
+	"github.com/shindesatish/titanic-service/pkg/model"
+)
+
+// BatchSize is the number of passengers buffered into each Arrow record
+// batch (and each Parquet row group) before it is written out.
+const BatchSize = 8192
+
+// Schema is the typed Arrow schema of the passenger dataset. Age, Cabin and
+// Embarked are nullable because they are blank for some passengers.
+var Schema = arrow.NewSchema([]arrow.Field{
+	{Name: "PassengerId", Type: arrow.PrimitiveTypes.Int32},
+	{Name: "Survived", Type: arrow.PrimitiveTypes.Int32},
+	{Name: "Pclass", Type: arrow.PrimitiveTypes.Int32},
+	{Name: "Name", Type: arrow.BinaryTypes.String},
+	{Name: "Sex", Type: arrow.BinaryTypes.String},
+	{Name: "Age", Type: arrow.PrimitiveTypes.Float64, Nullable: true},
+	{Name: "SibSp", Type: arrow.PrimitiveTypes.Int32},
+	{Name: "Parch", Type: arrow.PrimitiveTypes.Int32},
+	{Name: "Ticket", Type: arrow.BinaryTypes.String},
+	{Name: "Fare", Type: arrow.PrimitiveTypes.Float64},
+	{Name: "Cabin", Type: arrow.BinaryTypes.String, Nullable: true},
+	{Name: "Embarked", Type: arrow.BinaryTypes.String, Nullable: true},
+}, nil)
+
+// recordBuilder accumulates passengers into Arrow record batches.
+type recordBuilder struct {
+	builder *array.RecordBuilder
+	rows    int
+}
+
+func newRecordBuilder() *recordBuilder {
+	return &recordBuilder{builder: array.NewRecordBuilder(memory.NewGoAllocator(), Schema)}
+}
+
+func (b *recordBuilder) append(p *model.Passenger) {
+	fields := b.builder.Fields()
+	fields[0].(*array.Int32Builder).Append(int32(p.PassengerID))
+	fields[1].(*array.Int32Builder).Append(int32(p.Survived))
+	fields[2].(*array.Int32Builder).Append(int32(p.Pclass))
+	fields[3].(*array.StringBuilder).Append(p.Name)
+	fields[4].(*array.StringBuilder).Append(p.Sex)
+	if p.Age.Valid {
+		fields[5].(*array.Float64Builder).Append(p.Age.Float64)
+	} else {
+		fields[5].AppendNull()
+	}
+	fields[6].(*array.Int32Builder).Append(int32(p.SibSp))
+	fields[7].(*array.Int32Builder).Append(int32(p.Parch))
+	fields[8].(*array.StringBuilder).Append(p.Ticket)
+	fields[9].(*array.Float64Builder).Append(p.Fare)
+	appendNullableString(fields[10].(*array.StringBuilder), p.Cabin)
+	appendNullableString(fields[11].(*array.StringBuilder), p.Embarked)
+	b.rows++
+}
+
+// flush hands the buffered rows to write as one record and resets the
+// builder. It is a no-op when nothing is buffered.
+func (b *recordBuilder) flush(write func(arrow.Record) error) error {
+	if b.rows == 0 {
+		return nil
+	}
+	record := b.builder.NewRecord()
+	defer record.Release()
+	b.rows = 0
+	return write(record)
+}
+
+func (b *recordBuilder) release() {
+	b.builder.Release()
+}
+
+func appendNullableString(builder *array.StringBuilder, value string) {
+	if value == "" {
+		builder.AppendNull()
+		return
+	}
+	builder.Append(value)
+}
+
+// ArrowEncoder writes passengers as an Arrow IPC stream.
+type ArrowEncoder struct {
+	writer *ipc.Writer
+	batch  *recordBuilder
+}
+
+func NewArrowEncoder(w io.Writer) *ArrowEncoder {
+	return &ArrowEncoder{
+		writer: ipc.NewWriter(w, ipc.WithSchema(Schema)),
+		batch:  newRecordBuilder(),
+	}
+}
+
+func (e *ArrowEncoder) Encode(passenger *model.Passenger) error {
+	e.batch.append(passenger)
+	if e.batch.rows < BatchSize {
+		return nil
+	}
+	return e.batch.flush(e.writer.Write)
+}
+
+// Close writes any buffered rows and the end-of-stream marker.
+func (e *ArrowEncoder) Close() error {
+	defer e.batch.release()
+	if err := e.batch.flush(e.writer.Write); err != nil {
+		return fmt.Errorf("failed to write Arrow record batch: %v", err)
+	}
+	if err := e.writer.Close(); err != nil {
+		return fmt.Errorf("failed to close Arrow stream: %v", err)
+	}
+	return nil
+}
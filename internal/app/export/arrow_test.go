@@ -0,0 +1,55 @@
+package export
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/apache/arrow/go/v14/arrow/array"
+	"github.com/apache/arrow/go/v14/arrow/ipc"
+	"github.com/shindesatish/titanic-service/pkg/model"
+)
+
+func TestArrowEncoderAge(t *testing.T) {
+	passengers := []model.Passenger{
+		{PassengerID: 1, Age: model.NewNullFloat64(22)},
+		{PassengerID: 2, Age: model.NewNullFloat64(0)},
+		{PassengerID: 3},
+	}
+
+	var buf bytes.Buffer
+	encoder := NewArrowEncoder(&buf)
+	for i := range passengers {
+		if err := encoder.Encode(&passengers[i]); err != nil {
+			t.Fatalf("Encode: %v", err)
+		}
+	}
+	if err := encoder.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	reader, err := ipc.NewReader(&buf)
+	if err != nil {
+		t.Fatalf("NewReader: %v", err)
+	}
+	defer reader.Release()
+	if !reader.Next() {
+		t.Fatalf("no record batch: %v", reader.Err())
+	}
+	ages := reader.Record().Column(5).(*array.Float64)
+
+	tests := []struct {
+		valid bool
+		age   float64
+	}{
+		{true, 22},
+		{true, 0},
+		{false, 0},
+	}
+	for i, tt := range tests {
+		if ages.IsValid(i) != tt.valid {
+			t.Errorf("row %d: got valid %v, want %v", i, ages.IsValid(i), tt.valid)
+		} else if tt.valid && ages.Value(i) != tt.age {
+			t.Errorf("row %d: got Age %v, want %v", i, ages.Value(i), tt.age)
+		}
+	}
+}
@@ -0,0 +1,160 @@
+// internal/app/export/encoder.go
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/shindesatish/titanic-service/pkg/model"
+)
+
+// Formats passengers can be encoded in
+const (
+	FormatJSON    = "json"
+	FormatCSV     = "csv"
+	FormatNDJSON  = "ndjson"
+	FormatParquet = "parquet"
+	FormatArrow   = "arrow"
+)
+
+// Formats lists every supported format.
+var Formats = []string{FormatJSON, FormatCSV, FormatNDJSON, FormatParquet, FormatArrow}
+
+// Encoder writes a sequence of passengers one at a time, so large results
+// never need to be buffered in full. Close must be called to finish the
+// output.
+type Encoder interface {
+	Encode(passenger *model.Passenger) error
+	Close() error
+}
+
+// Options tune the output of an Encoder.
+type Options struct {
+	// Columns restricts CSV output to the given attributes (in titanic.csv
+	// order); nil means all. Parquet and Arrow always carry the full typed
+	// Schema.
+	Columns []string
+	// View maps each passenger to the value JSON and NDJSON encode, for
+	// example to add derived attributes. nil encodes the passenger as is.
+	View func(*model.Passenger) interface{}
+}
+
+// NewEncoder returns an encoder for format.
+func NewEncoder(w io.Writer, format string, opts Options) (Encoder, error) {
+	view := opts.View
+	if view == nil {
+		view = func(passenger *model.Passenger) interface{} { return passenger }
+	}
+
+	switch format {
+	case FormatParquet:
+		return NewParquetEncoder(w)
+	case FormatArrow:
+		return NewArrowEncoder(w), nil
+	case FormatCSV:
+		return newCSVEncoder(w, opts.Columns)
+	case FormatNDJSON:
+		return &ndjsonEncoder{encoder: json.NewEncoder(w), view: view}, nil
+	case FormatJSON:
+		return &jsonArrayEncoder{w: w, view: view}, nil
+	default:
+		return nil, fmt.Errorf("unsupported format %q", format)
+	}
+}
+
+type jsonArrayEncoder struct {
+	w     io.Writer
+	view  func(*model.Passenger) interface{}
+	count int
+}
+
+func (e *jsonArrayEncoder) Encode(passenger *model.Passenger) error {
+	data, err := json.Marshal(e.view(passenger))
+	if err != nil {
+		return err
+	}
+	sep := ","
+	if e.count == 0 {
+		sep = "["
+	}
+	if _, err := io.WriteString(e.w, sep); err != nil {
+		return err
+	}
+	e.count++
+	_, err = e.w.Write(data)
+	return err
+}
+
+func (e *jsonArrayEncoder) Close() error {
+	closing := "]\n"
+	if e.count == 0 {
+		closing = "[]\n"
+	}
+	_, err := io.WriteString(e.w, closing)
+	return err
+}
+
+type ndjsonEncoder struct {
+	encoder *json.Encoder
+	view    func(*model.Passenger) interface{}
+}
+
+func (e *ndjsonEncoder) Encode(passenger *model.Passenger) error {
+	return e.encoder.Encode(e.view(passenger))
+}
+
+func (e *ndjsonEncoder) Close() error {
+	return nil
+}
+
+type csvEncoder struct {
+	writer  *csv.Writer
+	indexes []int
+}
+
+func newCSVEncoder(w io.Writer, columns []string) (*csvEncoder, error) {
+	var indexes []int
+	for i, name := range model.CSVHeader {
+		if columns == nil || containsFold(columns, name) {
+			indexes = append(indexes, i)
+		}
+	}
+
+	e := &csvEncoder{writer: csv.NewWriter(w), indexes: indexes}
+	if err := e.writer.Write(e.pick(model.CSVHeader)); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+func (e *csvEncoder) Encode(passenger *model.Passenger) error {
+	return e.writer.Write(e.pick(passenger.CSVRecord()))
+}
+
+func (e *csvEncoder) Close() error {
+	e.writer.Flush()
+	return e.writer.Error()
+}
+
+func (e *csvEncoder) pick(record []string) []string {
+	picked := make([]string, len(e.indexes))
+	for i, idx := range e.indexes {
+		picked[i] = record[idx]
+	}
+	return picked
+}
+
+// containsFold reports whether s contains e, ignoring case. Attribute names
+// are matched this way because the API spells PassengerID while the CSV
+// header spells PassengerId.
+func containsFold(s []string, e string) bool {
+	for _, a := range s {
+		if strings.EqualFold(a, e) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,48 @@
+// internal/app/export/parquet.go
+package export
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/apache/arrow/go/v14/parquet"
+	"github.com/apache/arrow/go/v14/parquet/compress"
+	"github.com/apache/arrow/go/v14/parquet/pqarrow"
+	"github.com/shindesatish/titanic-service/pkg/model"
+)
+
+// ParquetEncoder writes passengers as a Snappy-compressed Parquet file with
+// one row group per BatchSize passengers.
+type ParquetEncoder struct {
+	writer *pqarrow.FileWriter
+	batch  *recordBuilder
+}
+
+func NewParquetEncoder(w io.Writer) (*ParquetEncoder, error) {
+	props := parquet.NewWriterProperties(parquet.WithCompression(compress.Codecs.Snappy))
+	writer, err := pqarrow.NewFileWriter(Schema, w, props, pqarrow.DefaultWriterProps())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Parquet writer: %v", err)
+	}
+	return &ParquetEncoder{writer: writer, batch: newRecordBuilder()}, nil
+}
+
+func (e *ParquetEncoder) Encode(passenger *model.Passenger) error {
+	e.batch.append(passenger)
+	if e.batch.rows < BatchSize {
+		return nil
+	}
+	return e.batch.flush(e.writer.Write)
+}
+
+// Close writes any buffered rows and the Parquet footer.
+func (e *ParquetEncoder) Close() error {
+	defer e.batch.release()
+	if err := e.batch.flush(e.writer.Write); err != nil {
+		return fmt.Errorf("failed to write Parquet row group: %v", err)
+	}
+	if err := e.writer.Close(); err != nil {
+		return fmt.Errorf("failed to close Parquet file: %v", err)
+	}
+	return nil
+}
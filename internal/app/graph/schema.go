@@ -0,0 +1,420 @@
+// internal/app/graph/schema.go
+
+// Package graph serves the passenger data over GraphQL. Passenger fields
+// carry the attribute names used everywhere else in the API (PassengerId,
+// Pclass, TitleGroup...), the other types use camelCase.
+package graph
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+	"github.com/shindesatish/titanic-service/internal/app/dto"
+	"github.com/shindesatish/titanic-service/internal/app/query"
+	"github.com/shindesatish/titanic-service/internal/app/repository"
+	"github.com/shindesatish/titanic-service/internal/app/service"
+	"github.com/shindesatish/titanic-service/pkg/model"
+)
+
+// Page sizes of the passengers query
+const (
+	defaultPageSize = 50
+	maxPageSize     = 500
+)
+
+// Schema is the GraphQL schema bound to a PassengerService.
+type Schema struct {
+	schema  graphql.Schema
+	service *service.PassengerService
+}
+
+// Request is a GraphQL request as sent by clients over HTTP.
+type Request struct {
+	Query         string                 `json:"query" form:"query"`
+	OperationName string                 `json:"operationName" form:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// NewSchema builds the schema, resolving every field through svc.
+func NewSchema(svc *service.PassengerService) (*Schema, error) {
+	fareBasis := graphql.NewEnum(graphql.EnumConfig{
+		Name:        "FareBasis",
+		Description: "Fare recorded per ticket, or split between the passengers sharing it",
+		Values: graphql.EnumValueConfigMap{
+			"RAW":        &graphql.EnumValueConfig{Value: string(service.FareRaw)},
+			"PER_PERSON": &graphql.EnumValueConfig{Value: string(service.FarePerPerson)},
+		},
+	})
+
+	var passengerType *graphql.Object
+	travelGroupType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "TravelGroup",
+		Description: "A family or travel party reconstructed from shared tickets and surnames",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"id":           &graphql.Field{Type: graphql.NewNonNull(graphql.Int), Resolve: groupField(func(g *model.TravelGroup) interface{} { return g.ID })},
+				"kind":         &graphql.Field{Type: graphql.NewNonNull(graphql.String), Resolve: groupField(func(g *model.TravelGroup) interface{} { return g.Kind })},
+				"size":         &graphql.Field{Type: graphql.NewNonNull(graphql.Int), Resolve: groupField(func(g *model.TravelGroup) interface{} { return g.Size })},
+				"surnames":     &graphql.Field{Type: graphql.NewList(graphql.NewNonNull(graphql.String)), Resolve: groupField(func(g *model.TravelGroup) interface{} { return g.Surnames })},
+				"tickets":      &graphql.Field{Type: graphql.NewList(graphql.NewNonNull(graphql.String)), Resolve: groupField(func(g *model.TravelGroup) interface{} { return g.Tickets })},
+				"survivors":    &graphql.Field{Type: graphql.NewNonNull(graphql.Int), Resolve: groupField(func(g *model.TravelGroup) interface{} { return g.Survivors })},
+				"survivalRate": &graphql.Field{Type: graphql.NewNonNull(graphql.Float), Resolve: groupField(func(g *model.TravelGroup) interface{} { return g.SurvivalRate })},
+				"outcome":      &graphql.Field{Type: graphql.NewNonNull(graphql.String), Resolve: groupField(func(g *model.TravelGroup) interface{} { return g.Outcome })},
+				"consistent":   &graphql.Field{Type: graphql.NewNonNull(graphql.Boolean), Resolve: groupField(func(g *model.TravelGroup) interface{} { return g.Consistent })},
+				"issues":       &graphql.Field{Type: graphql.NewList(graphql.NewNonNull(graphql.String)), Resolve: groupField(func(g *model.TravelGroup) interface{} { return g.Issues })},
+				"members": &graphql.Field{
+					Type: graphql.NewList(graphql.NewNonNull(passengerType)),
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return sessionOf(p).view(p.Source.(*model.TravelGroup).Members...)
+					},
+				},
+			}
+		}),
+	})
+
+	passengerType = graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Passenger",
+		Description: "A passenger with the attributes of titanic.csv and those derived from them",
+		Fields: graphql.Fields{
+			"PassengerId":     passengerField(graphql.NewNonNull(graphql.Int), func(r *dto.PassengerResponse) interface{} { return r.PassengerID }),
+			"Survived":        passengerField(graphql.NewNonNull(graphql.Int), func(r *dto.PassengerResponse) interface{} { return r.Survived }),
+			"Pclass":          passengerField(graphql.NewNonNull(graphql.Int), func(r *dto.PassengerResponse) interface{} { return r.Pclass }),
+			"Name":            passengerField(graphql.NewNonNull(graphql.String), func(r *dto.PassengerResponse) interface{} { return r.Name }),
+			"Sex":             passengerField(graphql.NewNonNull(graphql.String), func(r *dto.PassengerResponse) interface{} { return r.Sex }),
+			"Age":             passengerField(graphql.Float, func(r *dto.PassengerResponse) interface{} { return optionalFloat(r.Age) }),
+			"SibSp":           passengerField(graphql.NewNonNull(graphql.Int), func(r *dto.PassengerResponse) interface{} { return r.SibSp }),
+			"Parch":           passengerField(graphql.NewNonNull(graphql.Int), func(r *dto.PassengerResponse) interface{} { return r.Parch }),
+			"Ticket":          passengerField(graphql.NewNonNull(graphql.String), func(r *dto.PassengerResponse) interface{} { return r.Ticket }),
+			"Fare":            passengerField(graphql.NewNonNull(graphql.Float), func(r *dto.PassengerResponse) interface{} { return r.Fare }),
+			"Cabin":           passengerField(graphql.String, func(r *dto.PassengerResponse) interface{} { return optionalString(r.Cabin) }),
+			"Embarked":        passengerField(graphql.String, func(r *dto.PassengerResponse) interface{} { return optionalString(r.Embarked) }),
+			"Surname":         passengerField(graphql.NewNonNull(graphql.String), func(r *dto.PassengerResponse) interface{} { return r.Surname }),
+			"Title":           passengerField(graphql.String, func(r *dto.PassengerResponse) interface{} { return optionalString(r.Title) }),
+			"TitleGroup":      passengerField(graphql.NewNonNull(graphql.String), func(r *dto.PassengerResponse) interface{} { return r.TitleGroup }),
+			"GivenNames":      passengerField(graphql.String, func(r *dto.PassengerResponse) interface{} { return optionalString(r.GivenNames) }),
+			"AlternateName":   passengerField(graphql.String, func(r *dto.PassengerResponse) interface{} { return optionalString(r.AlternateName) }),
+			"Nickname":        passengerField(graphql.String, func(r *dto.PassengerResponse) interface{} { return optionalString(r.Nickname) }),
+			"Deck":            passengerField(graphql.String, func(r *dto.PassengerResponse) interface{} { return optionalString(r.Deck) }),
+			"Decks":           passengerField(graphql.NewList(graphql.NewNonNull(graphql.String)), func(r *dto.PassengerResponse) interface{} { return r.Decks }),
+			"CabinNumbers":    passengerField(graphql.NewList(graphql.NewNonNull(graphql.Int)), func(r *dto.PassengerResponse) interface{} { return r.CabinNumbers }),
+			"CabinCount":      passengerField(graphql.NewNonNull(graphql.Int), func(r *dto.PassengerResponse) interface{} { return r.CabinCount }),
+			"TicketPrefix":    passengerField(graphql.String, func(r *dto.PassengerResponse) interface{} { return optionalString(r.TicketPrefix) }),
+			"TicketNumber":    passengerField(graphql.NewNonNull(graphql.Int), func(r *dto.PassengerResponse) interface{} { return r.TicketNumber }),
+			"TicketPartySize": passengerField(graphql.NewNonNull(graphql.Int), func(r *dto.PassengerResponse) interface{} { return r.TicketPartySize }),
+			"FarePerPerson":   passengerField(graphql.NewNonNull(graphql.Float), func(r *dto.PassengerResponse) interface{} { return r.FarePerPerson }),
+			"TravelGroup": &graphql.Field{
+				Type:        travelGroupType,
+				Description: "The family or travel party of the passenger; passengers travelling alone form a group of one",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return sessionOf(p).group(p.Source.(*dto.PassengerResponse).PassengerID)
+				},
+			},
+		},
+	})
+
+	passengerPageType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "PassengerPage",
+		Fields: graphql.Fields{
+			"totalCount": &graphql.Field{Type: graphql.NewNonNull(graphql.Int), Description: "Passengers matching the filter, on every page"},
+			"offset":     &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"limit":      &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"items":      &graphql.Field{Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(passengerType)))},
+		},
+	})
+
+	histogramBucketType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "HistogramBucket",
+		Fields: graphql.Fields{
+			"percentile": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"count":      &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+		},
+	})
+
+	survivalGroupType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "SurvivalGroup",
+		Fields: graphql.Fields{
+			"group":        &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"passengers":   &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"survivors":    &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"survivalRate": &graphql.Field{Type: graphql.NewNonNull(graphql.Float)},
+		},
+	})
+
+	numericSummaryType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "NumericSummary",
+		Description: "Distribution of a numeric attribute over the passengers that have a value",
+		Fields: graphql.Fields{
+			"count":   &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"missing": &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"mean":    &graphql.Field{Type: graphql.NewNonNull(graphql.Float)},
+			"stdDev":  &graphql.Field{Type: graphql.NewNonNull(graphql.Float)},
+			"min":     &graphql.Field{Type: graphql.NewNonNull(graphql.Float)},
+			"median":  &graphql.Field{Type: graphql.NewNonNull(graphql.Float)},
+			"max":     &graphql.Field{Type: graphql.NewNonNull(graphql.Float)},
+		},
+	})
+
+	valueCountType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "ValueCount",
+		Fields: graphql.Fields{
+			"value": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"count": &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+		},
+	})
+
+	summaryType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Summary",
+		Fields: graphql.Fields{
+			"passengers":   &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"survivors":    &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"survivalRate": &graphql.Field{Type: graphql.NewNonNull(graphql.Float)},
+			"fareBasis":    &graphql.Field{Type: graphql.NewNonNull(fareBasis)},
+			"age":          &graphql.Field{Type: graphql.NewNonNull(numericSummaryType)},
+			"fare":         &graphql.Field{Type: graphql.NewNonNull(numericSummaryType)},
+			"sibSp":        &graphql.Field{Type: graphql.NewNonNull(numericSummaryType)},
+			"parch":        &graphql.Field{Type: graphql.NewNonNull(numericSummaryType)},
+			"pclass":       &graphql.Field{Type: graphql.NewList(graphql.NewNonNull(valueCountType))},
+			"sex":          &graphql.Field{Type: graphql.NewList(graphql.NewNonNull(valueCountType))},
+			"embarked":     &graphql.Field{Type: graphql.NewList(graphql.NewNonNull(valueCountType))},
+		},
+	})
+
+	queryType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"passenger": &graphql.Field{
+				Type:        passengerType,
+				Description: "Look up a passenger by PassengerId",
+				Args: graphql.FieldConfigArgument{
+					"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					s := sessionOf(p)
+					id := p.Args["id"].(int)
+					if id <= 0 {
+						return nil, fmt.Errorf("invalid passenger ID %d", id)
+					}
+					passenger, err := s.service.GetPassengerByID(s.ctx, uint(id))
+					if err != nil {
+						return nil, err
+					}
+					views, err := s.view(*passenger)
+					if err != nil {
+						return nil, err
+					}
+					return views[0], nil
+				},
+			},
+			"passengers": &graphql.Field{
+				Type:        graphql.NewNonNull(passengerPageType),
+				Description: "A page of the passengers matching a filter expression, in dataset order",
+				Args: graphql.FieldConfigArgument{
+					"filter": &graphql.ArgumentConfig{Type: graphql.String, Description: "Filter expression, e.g. Age < 12 OR (Sex = 'female' AND Pclass IN (1, 2))"},
+					"offset": &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: 0},
+					"limit":  &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: defaultPageSize, Description: fmt.Sprintf("At most %d", maxPageSize)},
+				},
+				Resolve: resolvePassengers,
+			},
+			"fareHistogram": &graphql.Field{
+				Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(histogramBucketType))),
+				Description: "Passenger counts per fare percentile bucket",
+				Args: graphql.FieldConfigArgument{
+					"fare": &graphql.ArgumentConfig{Type: fareBasis, DefaultValue: string(service.FareRaw)},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					s := sessionOf(p)
+					histogram, err := s.service.GetFareHistogramBy(s.ctx, service.FareBasis(p.Args["fare"].(string)))
+					if err != nil {
+						return nil, err
+					}
+					return histogramBuckets(histogram), nil
+				},
+			},
+			"survival": &graphql.Field{
+				Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(survivalGroupType))),
+				Description: "Survival rates grouped by an attribute, e.g. Sex, Pclass or Title",
+				Args: graphql.FieldConfigArgument{
+					"by": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					s := sessionOf(p)
+					groups, err := s.service.GetSurvivalStats(s.ctx, p.Args["by"].(string))
+					if err != nil {
+						return nil, err
+					}
+					results := make([]map[string]interface{}, len(groups))
+					for i, group := range groups {
+						results[i] = map[string]interface{}{
+							"group":        group.Group,
+							"passengers":   group.Passengers,
+							"survivors":    group.Survivors,
+							"survivalRate": group.SurvivalRate,
+						}
+					}
+					return results, nil
+				},
+			},
+			"summary": &graphql.Field{
+				Type:        graphql.NewNonNull(summaryType),
+				Description: "Passenger counts, survival rate and Age/Fare/SibSp/Parch distributions",
+				Args: graphql.FieldConfigArgument{
+					"fare": &graphql.ArgumentConfig{Type: fareBasis, DefaultValue: string(service.FareRaw)},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					s := sessionOf(p)
+					stats, err := s.service.GetSummaryStats(s.ctx, service.FareBasis(p.Args["fare"].(string)), nil)
+					if err != nil {
+						return nil, err
+					}
+					return summary(stats), nil
+				},
+			},
+		},
+	})
+
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{Query: queryType})
+	if err != nil {
+		return nil, err
+	}
+	return &Schema{schema: schema, service: svc}, nil
+}
+
+// Execute runs a GraphQL request. Errors in the request end up in the
+// result, next to whatever data could be resolved.
+func (s *Schema) Execute(ctx context.Context, request Request) *graphql.Result {
+	return graphql.Do(graphql.Params{
+		Schema:         s.schema,
+		RequestString:  request.Query,
+		OperationName:  request.OperationName,
+		VariableValues: request.Variables,
+		Context:        ctx,
+		RootObject: map[string]interface{}{
+			"session": &session{ctx: ctx, service: s.service},
+		},
+	})
+}
+
+func resolvePassengers(p graphql.ResolveParams) (interface{}, error) {
+	s := sessionOf(p)
+	offset, limit := p.Args["offset"].(int), p.Args["limit"].(int)
+	if offset < 0 {
+		return nil, fmt.Errorf("offset must not be negative")
+	}
+	if limit < 1 || limit > maxPageSize {
+		return nil, fmt.Errorf("limit must be between 1 and %d", maxPageSize)
+	}
+
+	var where *query.Expr
+	if input, ok := p.Args["filter"].(string); ok && strings.TrimSpace(input) != "" {
+		var err error
+		if where, err = query.Parse(input); err != nil {
+			return nil, fmt.Errorf("invalid filter: %v", err)
+		}
+	}
+
+	total := 0
+	var passengers []model.Passenger
+	err := s.service.StreamFilteredPassengers(s.ctx, model.PassengerFilter{}, where, func(passenger *model.Passenger) error {
+		if total >= offset && total < offset+limit {
+			passengers = append(passengers, *passenger)
+		}
+		total++
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := s.view(passengers...)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"totalCount": total,
+		"offset":     offset,
+		"limit":      limit,
+		"items":      items,
+	}, nil
+}
+
+func passengerField(t graphql.Output, get func(*dto.PassengerResponse) interface{}) *graphql.Field {
+	return &graphql.Field{
+		Type: t,
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return get(p.Source.(*dto.PassengerResponse)), nil
+		},
+	}
+}
+
+func groupField(get func(*model.TravelGroup) interface{}) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		return get(p.Source.(*model.TravelGroup)), nil
+	}
+}
+
+// optionalFloat maps a null NullFloat64, such as a missing Age, to null.
+func optionalFloat(value model.NullFloat64) interface{} {
+	if !value.Valid {
+		return nil
+	}
+	return value.Float64
+}
+
+// optionalString maps an empty string to null.
+func optionalString(value string) interface{} {
+	if value == "" {
+		return nil
+	}
+	return value
+}
+
+// histogramBuckets orders the fare histogram by percentile.
+func histogramBuckets(histogram map[string]int) []map[string]interface{} {
+	var buckets []map[string]interface{}
+	for _, percentile := range repository.HistogramBuckets() {
+		buckets = append(buckets, map[string]interface{}{"percentile": percentile, "count": histogram[percentile]})
+	}
+	return buckets
+}
+
+func summary(stats *model.SummaryStats) map[string]interface{} {
+	numeric := func(s model.NumericSummary) map[string]interface{} {
+		return map[string]interface{}{
+			"count":   s.Count,
+			"missing": s.Missing,
+			"mean":    s.Mean,
+			"stdDev":  s.StdDev,
+			"min":     s.Min,
+			"median":  s.Median,
+			"max":     s.Max,
+		}
+	}
+	counts := func(values map[string]int) []map[string]interface{} {
+		keys := make([]string, 0, len(values))
+		for key := range values {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		results := make([]map[string]interface{}, len(keys))
+		for i, key := range keys {
+			results[i] = map[string]interface{}{"value": key, "count": values[key]}
+		}
+		return results
+	}
+	return map[string]interface{}{
+		"passengers":   stats.Passengers,
+		"survivors":    stats.Survivors,
+		"survivalRate": stats.SurvivalRate,
+		"fareBasis":    stats.FareBasis,
+		"age":          numeric(stats.Age),
+		"fare":         numeric(stats.Fare),
+		"sibSp":        numeric(stats.SibSp),
+		"parch":        numeric(stats.Parch),
+		"pclass":       counts(stats.Pclass),
+		"sex":          counts(stats.Sex),
+		"embarked":     counts(stats.Embarked),
+	}
+}
@@ -0,0 +1,254 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/shindesatish/titanic-service/internal/app/repository"
+	"github.com/shindesatish/titanic-service/internal/app/service"
+)
+
+// newTestSchema builds the schema over a temporary copy of titanic.csv.
+func newTestSchema(t *testing.T) (*Schema, *service.PassengerService) {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("..", "..", "..", "datastore", "titanic.csv"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "titanic.csv")
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	svc := service.NewPassengerService(repository.NewCSVRepository(path))
+	schema, err := NewSchema(svc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return schema, svc
+}
+
+// execute runs query and decodes its data into data. It returns the
+// messages of the errors in the result.
+func execute(t *testing.T, schema *Schema, query string, data interface{}) []string {
+	t.Helper()
+	result := schema.Execute(context.Background(), Request{Query: query})
+	var messages []string
+	for _, err := range result.Errors {
+		messages = append(messages, err.Message)
+	}
+	encoded, err := json.Marshal(result.Data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(encoded, data); err != nil {
+		t.Fatalf("%s: %v", encoded, err)
+	}
+	return messages
+}
+
+type passengerPage struct {
+	Passengers struct {
+		TotalCount, Offset, Limit int
+		Items                     []struct {
+			PassengerId int
+			Age         *float64
+		}
+	}
+}
+
+func TestPassengersFilterAndPaging(t *testing.T) {
+	schema, _ := newTestSchema(t)
+
+	var all passengerPage
+	if errs := execute(t, schema, `{ passengers(filter: "Age < 12", limit: 500) { totalCount offset limit items { PassengerId Age } } }`, &all); errs != nil {
+		t.Fatal(errs)
+	}
+	if all.Passengers.TotalCount != 68 || len(all.Passengers.Items) != 68 || all.Passengers.Limit != 500 {
+		t.Fatalf("got %d passengers of %d, limit %d", len(all.Passengers.Items), all.Passengers.TotalCount, all.Passengers.Limit)
+	}
+	var ids []int
+	for _, item := range all.Passengers.Items {
+		if item.Age == nil || *item.Age >= 12 {
+			t.Errorf("passenger %d has age %v", item.PassengerId, item.Age)
+		}
+		ids = append(ids, item.PassengerId)
+	}
+
+	// Pages cover the filtered passengers in order, and count all of them
+	var paged []int
+	for offset := 0; offset < 80; offset += 30 {
+		var page passengerPage
+		query := `{ passengers(filter: "Age < 12", offset: ` + strconv.Itoa(offset) + `, limit: 30) { totalCount offset limit items { PassengerId } } }`
+		if errs := execute(t, schema, query, &page); errs != nil {
+			t.Fatal(errs)
+		}
+		if page.Passengers.TotalCount != 68 || page.Passengers.Offset != offset || page.Passengers.Limit != 30 {
+			t.Errorf("offset %d: got %+v", offset, page.Passengers)
+		}
+		for _, item := range page.Passengers.Items {
+			paged = append(paged, item.PassengerId)
+		}
+	}
+	if !reflect.DeepEqual(paged, ids) {
+		t.Errorf("pages hold %v, want %v", paged, ids)
+	}
+
+	// Without a filter, the default page holds the first 50 of everyone
+	var first passengerPage
+	if errs := execute(t, schema, `{ passengers { totalCount limit items { PassengerId } } }`, &first); errs != nil {
+		t.Fatal(errs)
+	}
+	if first.Passengers.TotalCount != 891 || len(first.Passengers.Items) != defaultPageSize || first.Passengers.Items[0].PassengerId != 1 {
+		t.Errorf("got %d passengers of %d", len(first.Passengers.Items), first.Passengers.TotalCount)
+	}
+}
+
+func TestPassengersErrors(t *testing.T) {
+	schema, _ := newTestSchema(t)
+	tests := map[string]string{
+		`{ passengers(filter: "Age <") { totalCount } }`:                    "invalid filter",
+		`{ passengers(filter: "Height > 2") { totalCount } }`:               "invalid filter",
+		`{ passengers(offset: -1) { totalCount } }`:                         "offset must not be negative",
+		`{ passengers(limit: 0) { totalCount } }`:                           "limit must be between 1 and 500",
+		`{ passengers(limit: 501) { totalCount } }`:                         "limit must be between 1 and 500",
+		`{ passengers { items { Height } } }`:                               `Cannot query field "Height"`,
+		`{ passenger(id: 0) { Name } }`:                                     "invalid passenger ID 0",
+		`{ passenger(id: 9999) { Name } }`:                                  "not found",
+		`{ survival(by: "Height") { group } }`:                              "Height",
+		`{ fareHistogram(fare: PER_TICKET) { count } }`:                     "PER_TICKET",
+		`{ passenger(id: 1) { Name } passengers(limit: 0) { totalCount } }`: "limit must be between 1 and 500",
+	}
+	for query, want := range tests {
+		var data map[string]interface{}
+		errs := execute(t, schema, query, &data)
+		if len(errs) != 1 || !strings.Contains(errs[0], want) {
+			t.Errorf("%s: got errors %q, want one containing %q", query, errs, want)
+		}
+	}
+}
+
+func TestPassengerByID(t *testing.T) {
+	schema, _ := newTestSchema(t)
+	var data struct {
+		Passenger struct {
+			Name, Sex, Ticket, TitleGroup string
+			Age                           *float64
+			Cabin                         *string
+			TicketPartySize               int
+			TravelGroup                   struct {
+				Size    int
+				Members []struct{ PassengerId int }
+			}
+		}
+		Missing *struct{ Age *float64 }
+	}
+	query := `{
+		passenger(id: 2) { Name Sex Age Ticket Cabin TitleGroup TicketPartySize TravelGroup { size members { PassengerId } } }
+		missing: passenger(id: 6) { Age }
+	}`
+	if errs := execute(t, schema, query, &data); errs != nil {
+		t.Fatal(errs)
+	}
+	p := data.Passenger
+	if p.Name != "Cumings, Mrs. John Bradley (Florence Briggs Thayer)" || p.Sex != "female" || p.Ticket != "PC 17599" || p.TitleGroup != "Mrs" {
+		t.Errorf("got %+v", p)
+	}
+	if p.Age == nil || *p.Age != 38 || p.Cabin == nil || *p.Cabin != "C85" || p.TicketPartySize != 1 {
+		t.Errorf("got age %v, cabin %v, party of %d", p.Age, p.Cabin, p.TicketPartySize)
+	}
+	if p.TravelGroup.Size != len(p.TravelGroup.Members) || p.TravelGroup.Size < 1 {
+		t.Errorf("got travel group %+v", p.TravelGroup)
+	}
+	found := false
+	for _, member := range p.TravelGroup.Members {
+		found = found || member.PassengerId == 2
+	}
+	if !found {
+		t.Errorf("passenger 2 is not a member of their own travel group %+v", p.TravelGroup)
+	}
+	// Passenger 6 has no recorded age
+	if data.Missing == nil || data.Missing.Age != nil {
+		t.Errorf("got %+v for passenger 6", data.Missing)
+	}
+}
+
+func TestAggregates(t *testing.T) {
+	schema, svc := newTestSchema(t)
+	var data struct {
+		FareHistogram []struct {
+			Percentile string
+			Count      int
+		}
+		Survival []struct {
+			Group                 string
+			Passengers, Survivors int
+			SurvivalRate          float64
+		}
+		Summary struct {
+			Passengers, Survivors int
+			FareBasis             string
+			Age                   struct{ Count, Missing int }
+			Sex                   []struct {
+				Value string
+				Count int
+			}
+		}
+	}
+	query := `{
+		fareHistogram { percentile count }
+		survival(by: "Sex") { group passengers survivors survivalRate }
+		summary(fare: PER_PERSON) { passengers survivors fareBasis age { count missing } sex { value count } }
+	}`
+	if errs := execute(t, schema, query, &data); errs != nil {
+		t.Fatal(errs)
+	}
+
+	histogram, err := svc.GetFareHistogramBy(context.Background(), service.FareRaw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Buckets come in percentile order, each with the service's count
+	buckets := repository.HistogramBuckets()
+	if len(data.FareHistogram) != len(buckets) {
+		t.Fatalf("got %d buckets, want %d", len(data.FareHistogram), len(buckets))
+	}
+	total := 0
+	for i, bucket := range data.FareHistogram {
+		if bucket.Percentile != buckets[i] || bucket.Count != histogram[buckets[i]] {
+			t.Errorf("bucket %d is %+v, want %s with %d", i, bucket, buckets[i], histogram[buckets[i]])
+		}
+		total += bucket.Count
+	}
+	if total == 0 {
+		t.Errorf("histogram counts nobody")
+	}
+
+	survival := map[string][2]int{}
+	for _, group := range data.Survival {
+		survival[group.Group] = [2]int{group.Passengers, group.Survivors}
+		if group.SurvivalRate != float64(group.Survivors)/float64(group.Passengers) {
+			t.Errorf("%s: survival rate %v", group.Group, group.SurvivalRate)
+		}
+	}
+	if want := map[string][2]int{"female": {314, 233}, "male": {577, 109}}; !reflect.DeepEqual(survival, want) {
+		t.Errorf("got survival %v, want %v", survival, want)
+	}
+
+	s := data.Summary
+	if s.Passengers != 891 || s.Survivors != 342 || s.FareBasis != "PER_PERSON" || s.Age.Count != 714 || s.Age.Missing != 177 {
+		t.Errorf("got summary %+v", s)
+	}
+	sexes := 0
+	for _, count := range s.Sex {
+		sexes += count.Count
+	}
+	if sexes != 891 {
+		t.Errorf("sex counts cover %d passengers", sexes)
+	}
+}
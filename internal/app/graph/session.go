@@ -0,0 +1,64 @@
+// internal/app/graph/session.go
+package graph
+
+import (
+	"context"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+	"github.com/shindesatish/titanic-service/internal/app/dto"
+	"github.com/shindesatish/titanic-service/internal/app/service"
+	"github.com/shindesatish/titanic-service/pkg/model"
+)
+
+// session holds what the resolvers of one request share, so that asking
+// for TicketPartySize or group on every passenger of a page reads the
+// dataset once rather than once per passenger.
+type session struct {
+	ctx        context.Context
+	service    *service.PassengerService
+	partySizes map[string]int
+	groups     map[int]*model.TravelGroup
+}
+
+func sessionOf(p graphql.ResolveParams) *session {
+	return p.Info.RootValue.(map[string]interface{})["session"].(*session)
+}
+
+// view adds the derived attributes to passengers.
+func (s *session) view(passengers ...model.Passenger) ([]*dto.PassengerResponse, error) {
+	if s.partySizes == nil {
+		sizes, err := s.service.GetTicketPartySizes(s.ctx)
+		if err != nil {
+			return nil, err
+		}
+		s.partySizes = sizes
+	}
+	views := make([]*dto.PassengerResponse, len(passengers))
+	for i := range passengers {
+		response := dto.NewPassengerResponse(&passengers[i]).WithTicketParty(s.partySizes[strings.TrimSpace(passengers[i].Ticket)])
+		views[i] = &response
+	}
+	return views, nil
+}
+
+// group returns the travel group of a passenger.
+func (s *session) group(passengerID int) (*model.TravelGroup, error) {
+	if s.groups == nil {
+		groups, err := s.service.GetTravelGroups(s.ctx, 1)
+		if err != nil {
+			return nil, err
+		}
+		s.groups = make(map[int]*model.TravelGroup)
+		for i := range groups {
+			for _, member := range groups[i].Members {
+				s.groups[member.PassengerID] = &groups[i]
+			}
+		}
+	}
+	group, ok := s.groups[passengerID]
+	if !ok {
+		return nil, service.ErrGroupNotFound
+	}
+	return group, nil
+}
@@ -0,0 +1,152 @@
+// internal/app/grouping/grouping.go
+package grouping
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/shindesatish/titanic-service/pkg/model"
+)
+
+// Build clusters passengers into travel groups. Two passengers end up in
+// the same group when they share a Ticket, or when they share a surname,
+// class and family size (SibSp + Parch + 1) greater than one, which links
+// relatives who travelled on separate tickets. Groups are ordered by ID.
+func Build(passengers []model.Passenger) []model.TravelGroup {
+	sets := newDisjointSet(len(passengers))
+	byTicket := make(map[string]int)
+	byFamily := make(map[string]int)
+	surnames := make([]string, len(passengers))
+
+	for i := range passengers {
+		p := &passengers[i]
+		surnames[i] = strings.ToLower(model.ParseName(p.Name).Surname)
+
+		if ticket := strings.TrimSpace(p.Ticket); ticket != "" {
+			if first, ok := byTicket[ticket]; ok {
+				sets.union(first, i)
+			} else {
+				byTicket[ticket] = i
+			}
+		}
+		if size := familySize(p); size > 1 && surnames[i] != "" {
+			key := fmt.Sprintf("%s|%d|%d", surnames[i], p.Pclass, size)
+			if first, ok := byFamily[key]; ok {
+				sets.union(first, i)
+			} else {
+				byFamily[key] = i
+			}
+		}
+	}
+
+	members := make(map[int][]int)
+	for i := range passengers {
+		root := sets.find(i)
+		members[root] = append(members[root], i)
+	}
+
+	groups := make([]model.TravelGroup, 0, len(members))
+	for _, indexes := range members {
+		groups = append(groups, newGroup(passengers, surnames, indexes))
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].ID < groups[j].ID })
+	return groups
+}
+
+// newGroup summarizes the passengers at indexes and checks that the
+// relatives found by surname agree with what each member reported in SibSp
+// and Parch. The dataset is a sample of the passenger list, so fewer
+// relatives than reported is expected; more is flagged as an issue.
+func newGroup(passengers []model.Passenger, surnames []string, indexes []int) model.TravelGroup {
+	sort.Slice(indexes, func(i, j int) bool { return passengers[indexes[i]].PassengerID < passengers[indexes[j]].PassengerID })
+
+	group := model.TravelGroup{
+		ID:         passengers[indexes[0]].PassengerID,
+		Size:       len(indexes),
+		Consistent: true,
+		Members:    make([]model.Passenger, 0, len(indexes)),
+	}
+	surnameCounts := make(map[string]int)
+	for _, i := range indexes {
+		surnameCounts[surnames[i]]++
+	}
+
+	seenSurnames := make(map[string]bool)
+	seenTickets := make(map[string]bool)
+	for _, i := range indexes {
+		p := passengers[i]
+		group.Members = append(group.Members, p)
+		group.Survivors += p.Survived
+
+		if surname := model.ParseName(p.Name).Surname; surname != "" && !seenSurnames[surname] {
+			seenSurnames[surname] = true
+			group.Surnames = append(group.Surnames, surname)
+		}
+		if !seenTickets[p.Ticket] {
+			seenTickets[p.Ticket] = true
+			group.Tickets = append(group.Tickets, p.Ticket)
+		}
+
+		relatives := surnameCounts[surnames[i]] - 1
+		if reported := p.SibSp + p.Parch; relatives > reported {
+			group.Consistent = false
+			group.Issues = append(group.Issues, fmt.Sprintf("passenger %d reports %d relatives aboard but the group has %d others named %s", p.PassengerID, reported, relatives, model.ParseName(p.Name).Surname))
+		}
+	}
+
+	group.SurvivalRate = float64(group.Survivors) / float64(group.Size)
+	switch group.Survivors {
+	case 0:
+		group.Outcome = model.OutcomeNoneSurvived
+	case group.Size:
+		group.Outcome = model.OutcomeAllSurvived
+	default:
+		group.Outcome = model.OutcomeSomeSurvived
+	}
+
+	switch {
+	case group.Size == 1:
+		group.Kind = model.GroupSolo
+	case len(group.Surnames) < group.Size:
+		group.Kind = model.GroupFamily
+	default:
+		group.Kind = model.GroupParty
+	}
+	return group
+}
+
+func familySize(p *model.Passenger) int {
+	return p.SibSp + p.Parch + 1
+}
+
+// disjointSet is a union-find over passenger indexes.
+type disjointSet []int
+
+func newDisjointSet(n int) disjointSet {
+	set := make(disjointSet, n)
+	for i := range set {
+		set[i] = i
+	}
+	return set
+}
+
+func (s disjointSet) find(i int) int {
+	for s[i] != i {
+		s[i] = s[s[i]]
+		i = s[i]
+	}
+	return i
+}
+
+func (s disjointSet) union(a, b int) {
+	ra, rb := s.find(a), s.find(b)
+	if ra == rb {
+		return
+	}
+	if ra < rb {
+		s[rb] = ra
+	} else {
+		s[ra] = rb
+	}
+}
@@ -0,0 +1,129 @@
+package grouping
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/shindesatish/titanic-service/pkg/model"
+)
+
+// traveller is a passenger with just the fields Build looks at.
+func traveller(id int, name, ticket string, pclass, sibSp, parch, survived int) model.Passenger {
+	return model.Passenger{PassengerID: id, Name: name, Ticket: ticket, Pclass: pclass, SibSp: sibSp, Parch: parch, Survived: survived}
+}
+
+// groupSummary is the part of a TravelGroup the tests compare.
+type groupSummary struct {
+	ID         int
+	Kind       string
+	Members    []int
+	Outcome    string
+	Consistent bool
+	Issues     int
+}
+
+func summarize(groups []model.TravelGroup) []groupSummary {
+	summaries := make([]groupSummary, len(groups))
+	for i, group := range groups {
+		members := make([]int, len(group.Members))
+		for j, member := range group.Members {
+			members[j] = member.PassengerID
+		}
+		summaries[i] = groupSummary{group.ID, group.Kind, members, group.Outcome, group.Consistent, len(group.Issues)}
+	}
+	return summaries
+}
+
+func TestBuild(t *testing.T) {
+	tests := []struct {
+		name       string
+		passengers []model.Passenger
+		want       []groupSummary
+	}{
+		{
+			name: "shared ticket",
+			passengers: []model.Passenger{
+				traveller(5, "Ward, Miss. Anna", "PC 17755", 1, 0, 0, 1),
+				traveller(3, "Cardeza, Mr. Thomas Drake Martinez", "PC 17755", 1, 0, 1, 1),
+				traveller(4, "Lesurer, Mr. Gustave J", " PC 17755 ", 1, 0, 0, 1),
+			},
+			want: []groupSummary{{3, model.GroupParty, []int{3, 4, 5}, model.OutcomeAllSurvived, true, 0}},
+		},
+		{
+			name: "relatives on separate tickets",
+			passengers: []model.Passenger{
+				traveller(1, "Andersson, Mr. Anders Johan", "347082", 3, 1, 1, 0),
+				traveller(2, "Andersson, Mrs. Anders Johan (Alfrida)", "347083", 3, 1, 1, 1),
+				traveller(3, "Andersson, Miss. Ebba", "347084", 3, 0, 2, 0),
+			},
+			want: []groupSummary{{1, model.GroupFamily, []int{1, 2, 3}, model.OutcomeSomeSurvived, true, 0}},
+		},
+		{
+			name: "same surname but alone, or in another class",
+			passengers: []model.Passenger{
+				traveller(1, "Smith, Mr. John", "1", 3, 0, 0, 0),
+				traveller(2, "Smith, Mr. James", "2", 3, 0, 0, 0),
+				traveller(3, "Smith, Mrs. Mary", "3", 1, 1, 0, 1),
+				traveller(4, "Smith, Mr. Joe", "4", 3, 1, 0, 0),
+			},
+			want: []groupSummary{
+				{1, model.GroupSolo, []int{1}, model.OutcomeNoneSurvived, true, 0},
+				{2, model.GroupSolo, []int{2}, model.OutcomeNoneSurvived, true, 0},
+				{3, model.GroupSolo, []int{3}, model.OutcomeAllSurvived, true, 0},
+				{4, model.GroupSolo, []int{4}, model.OutcomeNoneSurvived, true, 0},
+			},
+		},
+		{
+			name: "ticket and family links chain",
+			passengers: []model.Passenger{
+				traveller(7, "Brown, Mrs. Edith", "A", 2, 0, 1, 1),
+				traveller(8, "Brown, Miss. Edith", "B", 2, 0, 1, 1),
+				traveller(9, "Hocking, Mrs. Eliza", "B", 2, 0, 0, 1),
+			},
+			want: []groupSummary{{7, model.GroupFamily, []int{7, 8, 9}, model.OutcomeAllSurvived, true, 0}},
+		},
+		{
+			name: "more relatives than reported",
+			passengers: []model.Passenger{
+				traveller(1, "Kelly, Mr. James", "330911", 3, 0, 0, 0),
+				traveller(2, "Kelly, Mrs. Anna", "330911", 3, 0, 0, 1),
+			},
+			want: []groupSummary{{1, model.GroupFamily, []int{1, 2}, model.OutcomeSomeSurvived, false, 2}},
+		},
+		{
+			name:       "no passengers",
+			passengers: nil,
+			want:       []groupSummary{},
+		},
+	}
+	for _, tt := range tests {
+		if got := summarize(Build(tt.passengers)); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestBuildGroupDetails(t *testing.T) {
+	groups := Build([]model.Passenger{
+		traveller(2, "Kelly, Mrs. Anna", "330911", 3, 0, 0, 1),
+		traveller(1, "Kelly, Mr. James", "330911", 3, 0, 0, 0),
+		traveller(3, "Kelly, Miss. Mary", "330912", 3, 0, 0, 1),
+	})
+	if len(groups) != 2 {
+		t.Fatalf("got %d groups, want 2", len(groups))
+	}
+	group := groups[0]
+	if !reflect.DeepEqual(group.Surnames, []string{"Kelly"}) || !reflect.DeepEqual(group.Tickets, []string{"330911"}) {
+		t.Errorf("got surnames %v and tickets %v", group.Surnames, group.Tickets)
+	}
+	if group.Size != 2 || group.Survivors != 1 || group.SurvivalRate != 0.5 {
+		t.Errorf("got size %d, %d survivors and rate %v", group.Size, group.Survivors, group.SurvivalRate)
+	}
+	want := []string{
+		"passenger 1 reports 0 relatives aboard but the group has 1 others named Kelly",
+		"passenger 2 reports 0 relatives aboard but the group has 1 others named Kelly",
+	}
+	if !reflect.DeepEqual(group.Issues, want) {
+		t.Errorf("got issues %q", group.Issues)
+	}
+}
@@ -0,0 +1,115 @@
+// internal/app/handler/batch.go
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shindesatish/titanic-service/internal/app/dto"
+)
+
+// maxBatchIDs caps the IDs of one batch lookup, well below SQLite's limit
+// on bound parameters.
+const maxBatchIDs = 500
+
+// passengerActions are the custom methods on the passenger collection.
+var passengerActions = []string{"batchGet"}
+
+// PassengerActionHandler dispatches the custom methods on the passenger
+// collection, POST /passengers:<action>. Gin cannot route a literal colon,
+// so the action arrives as the action path parameter, colon included.
+func (h *PassengerHandler) PassengerActionHandler(c *gin.Context) {
+	switch action := strings.TrimPrefix(c.Param("action"), ":"); action {
+	case "batchGet":
+		h.BatchGetPassengersHandler(c)
+	default:
+		c.JSON(http.StatusNotFound, gin.H{"error": "Unknown passenger action - " + action, "allowed_action": passengerActions})
+	}
+}
+
+// BatchGetPassengersHandler handles POST /passengers:batchGet.
+func (h *PassengerHandler) BatchGetPassengersHandler(c *gin.Context) {
+	var request dto.BatchGetRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	h.writeBatch(c, request.IDs)
+}
+
+// getPassengersByIDsQuery answers GET /passengers?ids=1,5,9. The IDs may be
+// comma separated or repeated, and cannot be combined with filters.
+func (h *PassengerHandler) getPassengersByIDsQuery(c *gin.Context) {
+	for key := range c.Request.URL.Query() {
+		if !dto.Contains(append([]string{"ids", "format"}, imputationParams...), key) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ids cannot be combined with " + key})
+			return
+		}
+	}
+	format, ok := h.negotiate(c)
+	if !ok {
+		return
+	}
+	if format != formatJSON {
+		c.JSON(http.StatusNotAcceptable, gin.H{"error": "ids lookups are only available as JSON", "supported_formats": []string{formatJSON}})
+		return
+	}
+
+	var ids []uint
+	for _, value := range c.QueryArray("ids") {
+		for _, field := range strings.Split(value, ",") {
+			id, err := strconv.ParseUint(strings.TrimSpace(field), 10, 32)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid passenger ID - " + field})
+				return
+			}
+			ids = append(ids, uint(id))
+		}
+	}
+	h.writeBatch(c, ids)
+}
+
+// writeBatch looks up the passengers and answers with a
+// dto.BatchGetResponse, imputing missing values as requested.
+func (h *PassengerHandler) writeBatch(c *gin.Context, ids []uint) {
+	if len(ids) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No passenger IDs specified"})
+		return
+	}
+	if len(ids) > maxBatchIDs {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Too many passenger IDs - at most %d per call", maxBatchIDs)})
+		return
+	}
+
+	imputer, ok := h.imputer(c)
+	if !ok {
+		return
+	}
+	batch, err := h.PassengerService.GetPassengersByIDs(c.Request.Context(), ids)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	// Party sizes are kept per data version, so this only reads the dataset
+	// on the first call after it changes
+	partySizes, err := h.PassengerService.GetTicketPartySizes(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	response := dto.BatchGetResponse{Passengers: make([]dto.PassengerResponse, len(batch.Passengers)), MissingIDs: batch.MissingIDs}
+	for i := range batch.Passengers {
+		passenger := &batch.Passengers[i]
+		var imputed []string
+		if imputer != nil {
+			imputed = imputer.Apply(passenger)
+		}
+		response.Passengers[i] = dto.NewPassengerResponse(passenger).WithTicketParty(partySizes[strings.TrimSpace(passenger.Ticket)])
+		response.Passengers[i].Imputed = imputed
+	}
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shindesatish/titanic-service/internal/app/service"
+	"github.com/shindesatish/titanic-service/pkg/model"
+)
+
+// countingRepository serves a fixed dataset and counts the full passes over
+// it.
+type countingRepository struct {
+	service.Repository
+	passengers []model.Passenger
+	passes     int
+}
+
+func (r *countingRepository) ForEachPassenger(fn func(*model.Passenger) error) error {
+	r.passes++
+	for i := range r.passengers {
+		if err := fn(&r.passengers[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *countingRepository) GetPassengersByIDs(ids []uint) ([]model.Passenger, error) {
+	var found []model.Passenger
+	for _, id := range ids {
+		for _, p := range r.passengers {
+			if uint(p.PassengerID) == id {
+				found = append(found, p)
+			}
+		}
+	}
+	return found, nil
+}
+
+func (r *countingRepository) DataVersion() (string, error) {
+	return "1", nil
+}
+
+func TestBatchGetReusesPartySizes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repo := &countingRepository{passengers: []model.Passenger{
+		{PassengerID: 1, Ticket: "A", Fare: 10},
+		{PassengerID: 2, Ticket: "A", Fare: 10},
+		{PassengerID: 3, Ticket: "B", Fare: 5},
+	}}
+	router := gin.New()
+	if err := NewPassengerHandler(service.NewPassengerService(repo)).RegisterRoutes(router.Group("/v1")); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 3; i++ {
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodPost, "/v1/passengers:batchGet", strings.NewReader(`{"ids": [1, 3]}`))
+		request.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(recorder, request)
+
+		if recorder.Code != http.StatusOK {
+			t.Fatalf("got status %d: %s", recorder.Code, recorder.Body)
+		}
+		if !strings.Contains(recorder.Body.String(), `"TicketPartySize":2`) {
+			t.Fatalf("response lacks the party size of ticket A: %s", recorder.Body)
+		}
+	}
+	if repo.passes != 1 {
+		t.Errorf("got %d passes over the dataset for 3 batches, want 1", repo.passes)
+	}
+}
@@ -0,0 +1,86 @@
+// internal/app/handler/etag.go
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ConditionalRequestMiddleware tags read responses with a strong ETag derived
+// from the dataset version and answers If-None-Match with 304 Not Modified.
+// Mutating requests carrying If-Match are rejected with 412 Precondition
+// Failed when the dataset has changed since the client last read it. That
+// check only saves reading a doomed request; the write itself must check
+// If-Match again, as ImportPassengersHandler does.
+func (h *PassengerHandler) ConditionalRequestMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		version, err := h.PassengerService.GetDataVersion(context.Background())
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("failed to get data version: %v", err)})
+			return
+		}
+		etag := entityTag(version, c)
+
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead:
+			c.Header("ETag", etag)
+			c.Header("Cache-Control", "public, no-cache")
+			c.Header("Vary", "Accept")
+			if header := c.GetHeader("If-None-Match"); header != "" && etagListMatches(header, etag, true) {
+				c.AbortWithStatus(http.StatusNotModified)
+				return
+			}
+		default:
+			if header := c.GetHeader("If-Match"); header != "" && !etagListMatches(header, `"`+version+`"`, false) {
+				c.AbortWithStatusJSON(http.StatusPreconditionFailed, gin.H{"error": "dataset has been modified", "etag": etag})
+				return
+			}
+		}
+
+		c.Next()
+	}
+}
+
+// entityTag builds the ETag for the representation being requested. Each
+// output format gets its own tag so that caches never serve CSV in place of
+// JSON; the dataset version always comes first, before any ".format" suffix.
+func entityTag(version string, c *gin.Context) string {
+	format, err := negotiateFormat(c)
+	if err != nil || format == formatJSON {
+		return `"` + version + `"`
+	}
+	return `"` + version + "." + format + `"`
+}
+
+// etagListMatches reports whether the comma-separated entity tags in header
+// match etag. Weak comparison ignores the W/ prefix, as If-None-Match
+// requires; strong comparison (If-Match) never matches a weak tag.
+func etagListMatches(header, etag string, weak bool) bool {
+	if strings.TrimSpace(header) == "*" {
+		return true
+	}
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if strings.HasPrefix(tag, "W/") {
+			if !weak {
+				continue
+			}
+			tag = strings.TrimPrefix(tag, "W/")
+		}
+		if !weak {
+			// Preconditions on writes only care about the dataset version,
+			// whichever representation the client read it from.
+			if dot := strings.IndexByte(tag, '.'); dot >= 0 {
+				tag = tag[:dot] + `"`
+			}
+		}
+		if tag == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,55 @@
+// internal/app/handler/export.go
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shindesatish/titanic-service/pkg/model"
+)
+
+// exportFileExtensions names the download for each export format.
+var exportFileExtensions = map[string]string{
+	formatJSON:    "json",
+	formatCSV:     "csv",
+	formatNDJSON:  "ndjson",
+	formatParquet: "parquet",
+	formatArrow:   "arrows",
+}
+
+// ExportPassengersHandler handles GET /export.
+func (h *PassengerHandler) ExportPassengersHandler(c *gin.Context) {
+	format := formatParquet
+	if c.Query("format") != "" || c.GetHeader("Accept") != "" {
+		var ok bool
+		if format, ok = h.negotiate(c); !ok {
+			return
+		}
+	}
+
+	filter, err := queryFilter(c, append([]string{"format", "filter"}, imputationParams...)...)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "allowed_attribute": model.AttributeNames()})
+		return
+	}
+	where, ok := filterExpression(c)
+	if !ok {
+		return
+	}
+
+	imputer, ok := h.imputer(c)
+	if !ok {
+		return
+	}
+	stream := &imputedStream{imputer: imputer}
+
+	opts, ok := h.streamOptions(c, format, stream)
+	if !ok {
+		return
+	}
+
+	c.Header("Content-Disposition", `attachment; filename="titanic.`+exportFileExtensions[format]+`"`)
+	streamPassengers(c, format, opts, func(fn func(*model.Passenger) error) error {
+		return h.PassengerService.StreamFilteredPassengers(c.Request.Context(), filter, where, stream.wrap(fn))
+	})
+}
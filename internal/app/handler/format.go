@@ -0,0 +1,240 @@
+// internal/app/handler/format.go
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shindesatish/titanic-service/internal/app/dto"
+	"github.com/shindesatish/titanic-service/internal/app/export"
+	"github.com/shindesatish/titanic-service/internal/app/query"
+	"github.com/shindesatish/titanic-service/pkg/model"
+)
+
+// Short names for the export formats used throughout the handlers
+const (
+	formatJSON    = export.FormatJSON
+	formatCSV     = export.FormatCSV
+	formatNDJSON  = export.FormatNDJSON
+	formatParquet = export.FormatParquet
+	formatArrow   = export.FormatArrow
+)
+
+var formatContentTypes = map[string]string{
+	formatJSON:    "application/json; charset=utf-8",
+	formatCSV:     "text/csv; charset=utf-8",
+	formatNDJSON:  "application/x-ndjson",
+	formatParquet: "application/vnd.apache.parquet",
+	formatArrow:   "application/vnd.apache.arrow.stream",
+}
+
+var mediaTypeFormats = map[string]string{
+	"application/json":                    formatJSON,
+	"text/csv":                            formatCSV,
+	"application/x-ndjson":                formatNDJSON,
+	"application/vnd.apache.parquet":      formatParquet,
+	"application/vnd.apache.arrow.stream": formatArrow,
+	"application/*":                       formatJSON,
+	"*/*":                                 formatJSON,
+}
+
+// negotiateFormat picks the response format from the ?format= query
+// parameter, falling back to the Accept header and then to JSON.
+func negotiateFormat(c *gin.Context) (string, error) {
+	if format := c.Query("format"); format != "" {
+		if _, ok := formatContentTypes[format]; !ok {
+			return "", fmt.Errorf("unsupported format %q", format)
+		}
+		return format, nil
+	}
+
+	accept := c.GetHeader("Accept")
+	if accept == "" {
+		return formatJSON, nil
+	}
+
+	type candidate struct {
+		format string
+		q      float64
+	}
+	var candidates []candidate
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		format, ok := mediaTypeFormats[mediaType]
+		if !ok {
+			continue
+		}
+		q := 1.0
+		if value, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(value, 64); err != nil {
+				continue
+			}
+		}
+		if q > 0 {
+			candidates = append(candidates, candidate{format: format, q: q})
+		}
+	}
+	if len(candidates) == 0 {
+		return "", fmt.Errorf("none of the accepted media types are supported: %s", accept)
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].q > candidates[j].q })
+	return candidates[0].format, nil
+}
+
+// negotiate resolves the response format, answering 400 for an unknown
+// ?format= and 406 for an unsatisfiable Accept header.
+func (h *PassengerHandler) negotiate(c *gin.Context) (string, bool) {
+	format, err := negotiateFormat(c)
+	if err == nil {
+		return format, true
+	}
+	status := http.StatusNotAcceptable
+	if c.Query("format") != "" {
+		status = http.StatusBadRequest
+	}
+	c.JSON(status, gin.H{"error": err.Error(), "supported_formats": export.Formats})
+	return "", false
+}
+
+// flushEvery is how many rows are written between explicit flushes while
+// streaming a list response.
+const flushEvery = 100
+
+// writePassengers streams passengers to the client in the given format.
+func writePassengers(c *gin.Context, format string, opts export.Options, passengers []model.Passenger) {
+	streamPassengers(c, format, opts, func(fn func(*model.Passenger) error) error {
+		for i := range passengers {
+			if err := fn(&passengers[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// streamPassengers writes every passenger produced by produce as it arrives.
+// The status line is only committed once the first passenger is available,
+// so a producer that fails up front still gets a JSON error response; later
+// failures can only be logged and end the response early.
+func streamPassengers(c *gin.Context, format string, opts export.Options, produce func(func(*model.Passenger) error) error) {
+	var encoder export.Encoder
+	count := 0
+	start := func() error {
+		c.Header("Content-Type", formatContentTypes[format])
+		c.Status(http.StatusOK)
+		var err error
+		encoder, err = export.NewEncoder(c.Writer, format, opts)
+		return err
+	}
+
+	err := produce(func(passenger *model.Passenger) error {
+		if encoder == nil {
+			if err := start(); err != nil {
+				return err
+			}
+		}
+		if err := encoder.Encode(passenger); err != nil {
+			return err
+		}
+		count++
+		if count%flushEvery == 0 {
+			c.Writer.Flush()
+		}
+		return nil
+	})
+	if err != nil {
+		if encoder == nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.Error(err)
+		return
+	}
+
+	if encoder == nil {
+		if err := start(); err != nil {
+			c.Error(err)
+			return
+		}
+	}
+	if err := encoder.Close(); err != nil {
+		c.Error(err)
+	}
+}
+
+// streamOptions returns the options GET /passengers and GET /export encode
+// passengers with in format. JSON and NDJSON use passengerView, which needs
+// the ticket party sizes; it answers 500 and returns false when they cannot
+// be read.
+func (h *PassengerHandler) streamOptions(c *gin.Context, format string, stream *imputedStream) (export.Options, bool) {
+	if format != formatJSON && format != formatNDJSON {
+		return export.Options{}, true
+	}
+	partySizes, err := h.PassengerService.GetTicketPartySizes(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return export.Options{}, false
+	}
+	return export.Options{View: passengerView(partySizes, stream.imputed)}, true
+}
+
+// passengerView adds the derived attributes to passengers in JSON
+// responses. partySizes holds the number of passengers on each ticket, for
+// the per-person fare, and imputed, when not nil, returns the fields of the
+// passenger filled in by imputation.
+func passengerView(partySizes map[string]int, imputed func() []string) func(*model.Passenger) interface{} {
+	return func(passenger *model.Passenger) interface{} {
+		response := dto.NewPassengerResponse(passenger).WithTicketParty(partySizes[strings.TrimSpace(passenger.Ticket)])
+		if imputed != nil {
+			response.Imputed = imputed()
+		}
+		return response
+	}
+}
+
+// queryFilter builds an attribute filter from every query parameter except
+// those listed in reserved. Repeat a parameter or separate values with
+// commas to allow several values.
+func queryFilter(c *gin.Context, reserved ...string) (model.PassengerFilter, error) {
+	filter := model.PassengerFilter{}
+	for key, values := range c.Request.URL.Query() {
+		if dto.Contains(reserved, key) {
+			continue
+		}
+		for _, value := range values {
+			filter[key] = append(filter[key], strings.Split(value, ",")...)
+		}
+	}
+	return filter, filter.Validate()
+}
+
+// filterExpression parses the filter query parameter, answering 400 with the
+// position of the problem when it does not parse. It returns nil when the
+// parameter is absent.
+func filterExpression(c *gin.Context) (*query.Expr, bool) {
+	input := c.Query("filter")
+	if strings.TrimSpace(input) == "" {
+		return nil, true
+	}
+	where, err := query.Parse(input)
+	if err != nil {
+		response := gin.H{"error": "Invalid filter - " + err.Error(), "allowed_attribute": model.AttributeNames()}
+		var syntaxErr *query.Error
+		if errors.As(err, &syntaxErr) {
+			response["position"] = syntaxErr.Pos
+		}
+		c.JSON(http.StatusBadRequest, response)
+		return nil, false
+	}
+	return where, true
+}
@@ -0,0 +1,73 @@
+// internal/app/handler/graphql.go
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shindesatish/titanic-service/internal/app/graph"
+)
+
+// GraphQLHandler serves GraphQL queries over passengers and statistics.
+// Queries come as a JSON body on POST, or in the query, operationName and
+// variables parameters on GET. Outside release mode, a browser visiting
+// the endpoint gets GraphiQL. It fails if the schema does not build.
+func (h *PassengerHandler) GraphQLHandler() (gin.HandlerFunc, error) {
+	schema, err := graph.NewSchema(h.PassengerService)
+	if err != nil {
+		return nil, fmt.Errorf("invalid GraphQL schema: %v", err)
+	}
+
+	return func(c *gin.Context) {
+		var request graph.Request
+		if c.Request.Method == http.MethodGet {
+			if c.Query("query") == "" && gin.IsDebugging() && strings.Contains(c.GetHeader("Accept"), "text/html") {
+				c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(graphiQLPage))
+				return
+			}
+			request.Query = c.Query("query")
+			request.OperationName = c.Query("operationName")
+			if variables := c.Query("variables"); variables != "" {
+				if err := json.Unmarshal([]byte(variables), &request.Variables); err != nil {
+					c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid variables - " + err.Error()})
+					return
+				}
+			}
+		} else if err := c.ShouldBindJSON(&request); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid GraphQL request - " + err.Error()})
+			return
+		}
+
+		if strings.TrimSpace(request.Query) == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "No query specified"})
+			return
+		}
+		c.JSON(http.StatusOK, schema.Execute(c.Request.Context(), request))
+	}, nil
+}
+
+// graphiQLPage loads GraphiQL from a CDN and points it at the page's own
+// URL.
+const graphiQLPage = `<!DOCTYPE html>
+<html>
+<head>
+  <title>Titanic GraphiQL</title>
+  <link rel="stylesheet" href="https://unpkg.com/graphiql@3/graphiql.min.css" />
+</head>
+<body style="margin: 0;">
+  <div id="graphiql" style="height: 100vh;"></div>
+  <script crossorigin src="https://unpkg.com/react@18/umd/react.production.min.js"></script>
+  <script crossorigin src="https://unpkg.com/react-dom@18/umd/react-dom.production.min.js"></script>
+  <script crossorigin src="https://unpkg.com/graphiql@3/graphiql.min.js"></script>
+  <script>
+    const fetcher = GraphiQL.createFetcher({ url: window.location.pathname });
+    ReactDOM.createRoot(document.getElementById('graphiql')).render(
+      React.createElement(GraphiQL, { fetcher: fetcher, defaultQuery: '{ passengers(filter: "Age < 12", limit: 5) { totalCount items { PassengerId Name Age Pclass TravelGroup { size outcome } } } }' })
+    );
+  </script>
+</body>
+</html>
+`
@@ -0,0 +1,50 @@
+// internal/app/handler/groups.go
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shindesatish/titanic-service/internal/app/service"
+)
+
+// GetTravelGroupsHandler handles GET /groups.
+func (h *PassengerHandler) GetTravelGroupsHandler(c *gin.Context) {
+	minSize, err := strconv.Atoi(c.DefaultQuery("min_size", "2"))
+	if err != nil || minSize < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid min_size value - " + c.Query("min_size")})
+		return
+	}
+
+	groups, err := h.PassengerService.GetTravelGroups(context.Background(), minSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, groups)
+}
+
+// GetPassengerGroupHandler handles GET /passengers/{id}/group.
+func (h *PassengerHandler) GetPassengerGroupHandler(c *gin.Context) {
+	passengerID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid passenger ID"})
+		return
+	}
+
+	group, err := h.PassengerService.GetPassengerGroup(context.Background(), passengerID)
+	if errors.Is(err, service.ErrGroupNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, group)
+}
@@ -3,13 +3,18 @@ package handler
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/shindesatish/titanic-service/internal/app/dto"
+	"github.com/shindesatish/titanic-service/internal/app/export"
+	"github.com/shindesatish/titanic-service/internal/app/repository"
 	"github.com/shindesatish/titanic-service/internal/app/service"
+	"github.com/shindesatish/titanic-service/pkg/model"
 )
 
 type PassengerHandler struct {
@@ -20,30 +25,45 @@ func NewPassengerHandler(passengerService *service.PassengerService) *PassengerH
 	return &PassengerHandler{PassengerService: passengerService}
 }
 
-// @Summary Get all passengers
-// @Description Get a list of all passengers in JSON format
-// @Tags passengers
-// @Produce json
-// @Success 200 {array} model.Passenger "OK"
-// @Router /passengers [get]
+// GetAllPassengersHandler handles GET /passengers.
 func (h *PassengerHandler) GetAllPassengersHandler(c *gin.Context) {
-	passengers, err := h.PassengerService.GetAllPassengers(context.Background())
+	if _, ok := c.GetQuery("ids"); ok {
+		h.getPassengersByIDsQuery(c)
+		return
+	}
+
+	format, ok := h.negotiate(c)
+	if !ok {
+		return
+	}
+
+	filter, err := queryFilter(c, append([]string{"format", "filter"}, imputationParams...)...)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "allowed_attribute": model.AttributeNames()})
+		return
+	}
+	where, ok := filterExpression(c)
+	if !ok {
+		return
+	}
+
+	imputer, ok := h.imputer(c)
+	if !ok {
 		return
 	}
+	stream := &imputedStream{imputer: imputer}
 
-	c.JSON(http.StatusOK, passengers)
+	opts, ok := h.streamOptions(c, format, stream)
+	if !ok {
+		return
+	}
+
+	streamPassengers(c, format, opts, func(fn func(*model.Passenger) error) error {
+		return h.PassengerService.StreamFilteredPassengers(c.Request.Context(), filter, where, stream.wrap(fn))
+	})
 }
 
-// @Summary Get passenger by ID
-// @Description Get passenger data by PassengerId in JSON format
-// @Tags passengers
-// @Produce json
-// @Param id path int true "Passenger ID"
-// @Success 200 {object} model.Passenger "OK"
-// @Failure 404 {object} map[string]string "Not Found"
-// @Router /passengers/{id} [get]
+// GetPassengerByIDHandler handles GET /passengers/{id}.
 func (h *PassengerHandler) GetPassengerByIDHandler(c *gin.Context) {
 	passengerID, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
@@ -52,23 +72,38 @@ func (h *PassengerHandler) GetPassengerByIDHandler(c *gin.Context) {
 	}
 
 	passenger, err := h.PassengerService.GetPassengerByID(context.Background(), uint(passengerID))
+	if errors.Is(err, repository.ErrPassengerNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, passenger)
+	partySizes, err := h.PassengerService.GetTicketPartySizes(context.Background())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	imputer, ok := h.imputer(c)
+	if !ok {
+		return
+	}
+	var imputed []string
+	if imputer != nil {
+		copied := *passenger
+		imputed = imputer.Apply(&copied)
+		passenger = &copied
+	}
+
+	response := dto.NewPassengerResponse(passenger).WithTicketParty(partySizes[strings.TrimSpace(passenger.Ticket)])
+	response.Imputed = imputed
+	c.JSON(http.StatusOK, response)
 }
 
-// @Summary Get selected attributes of passenger by ID
-// @Description Get selected attributes of passenger by PassengerId in JSON format
-// @Tags passengers
-// @Produce json
-// @Param id path int true "Passenger ID"
-// @Param attributes query array true "List of attributes to retrieve"
-// @Success 200 {object} map[string]interface{} "OK"
-// @Failure 404 {object} map[string]string "Not Found"
-// @Router /passenger-attributes/{id} [get]
+// GetPassengerAttributesHandler handles GET /passenger-attributes/{id}.
 func (h *PassengerHandler) GetPassengerAttributesHandler(c *gin.Context) {
 	passengerID, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
@@ -89,25 +124,37 @@ func (h *PassengerHandler) GetPassengerAttributesHandler(c *gin.Context) {
 		}
 	}
 
+	format, ok := h.negotiate(c)
+	if !ok {
+		return
+	}
+
 	passenger, err := h.PassengerService.GetPassengerAttributes(context.Background(), uint(passengerID), attributes)
+	if errors.Is(err, repository.ErrPassengerNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, passenger)
+	if format == formatJSON {
+		c.JSON(http.StatusOK, passenger)
+		return
+	}
+	writePassengers(c, format, export.Options{Columns: attributes}, []model.Passenger{*passenger})
 }
 
-// @Summary Get fare histogram
-// @Description Get a histogram of fare prices in percentiles
-// @Tags passengers
-// @Produce json
-// @Success 200 {object} map[string]int "OK"
-// @Failure 500 {object} map[string]string "Internal Server Error"
-// @Router /fare-histogram [get]
+// GetFareHistogramHandler handles GET /fare-histogram.
 func (h *PassengerHandler) GetFareHistogramHandler(c *gin.Context) {
+	basis, ok := fareBasis(c)
+	if !ok {
+		return
+	}
+
 	// Fetch fare data from the service
-	fareData, err := h.PassengerService.GetFareHistogram(c)
+	fareData, err := h.PassengerService.GetFareHistogramBy(c, basis)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("failed to get fare histogram: %v", err)})
 		return
@@ -115,3 +162,18 @@ func (h *PassengerHandler) GetFareHistogramHandler(c *gin.Context) {
 	// Convert result map to JSON and send the response
 	c.JSON(http.StatusOK, fareData)
 }
+
+// GetCacheStatsHandler handles GET /cache-stats.
+func (h *PassengerHandler) GetCacheStatsHandler(c *gin.Context) {
+	stats, err := h.PassengerService.GetCacheStats(context.Background())
+	if errors.Is(err, service.ErrCacheDisabled) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
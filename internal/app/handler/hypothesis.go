@@ -0,0 +1,61 @@
+// internal/app/handler/hypothesis.go
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shindesatish/titanic-service/internal/app/service"
+	"github.com/shindesatish/titanic-service/pkg/model"
+)
+
+// GetChiSquareTestHandler handles GET /stats/tests/chi-square.
+func (h *PassengerHandler) GetChiSquareTestHandler(c *gin.Context) {
+	a, b := c.Query("a"), c.Query("b")
+	for _, attribute := range []string{a, b} {
+		if !model.IsAttribute(attribute) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid attribute specified - " + attribute, "allowed_attribute": model.AttributeNames()})
+			return
+		}
+	}
+
+	test, err := h.PassengerService.ChiSquareTest(context.Background(), a, b)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, test)
+}
+
+// CompareGroupsHandler handles GET /stats/tests/compare.
+func (h *PassengerHandler) CompareGroupsHandler(c *gin.Context) {
+	var groups []string
+	if raw := c.Query("groups"); raw != "" {
+		groups = strings.Split(raw, ",")
+	}
+
+	comparison, err := h.PassengerService.CompareGroups(context.Background(), c.Query("value"), c.Query("by"), groups)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "allowed_value": model.NumericAttributes})
+		return
+	}
+
+	c.JSON(http.StatusOK, comparison)
+}
+
+// GetCorrelationMatrixHandler handles GET /stats/correlations.
+func (h *PassengerHandler) GetCorrelationMatrixHandler(c *gin.Context) {
+	attributes := strings.Split(c.DefaultQuery("attributes", "Survived,Pclass,Age,SibSp,Parch,Fare"), ",")
+	method := c.DefaultQuery("method", service.CorrelationPearson)
+
+	matrix, err := h.PassengerService.GetCorrelationMatrix(context.Background(), attributes, method)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "allowed_attribute": model.NumericAttributes, "allowed_method": []string{service.CorrelationPearson, service.CorrelationSpearman}})
+		return
+	}
+
+	c.JSON(http.StatusOK, matrix)
+}
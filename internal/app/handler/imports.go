@@ -0,0 +1,100 @@
+// internal/app/handler/imports.go
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shindesatish/titanic-service/internal/app/repository"
+	"github.com/shindesatish/titanic-service/internal/app/validation"
+	"github.com/shindesatish/titanic-service/pkg/model"
+)
+
+// maxImportBytes caps the size of an uploaded passenger file.
+const maxImportBytes = 32 << 20
+
+// ImportPassengersHandler handles POST /imports.
+func (h *PassengerHandler) ImportPassengersHandler(c *gin.Context) {
+	mode := repository.ImportMode(c.DefaultQuery("mode", string(repository.ImportUpsert)))
+	if mode != repository.ImportUpsert && mode != repository.ImportReplace {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid import mode - " + string(mode), "allowed_modes": []repository.ImportMode{repository.ImportUpsert, repository.ImportReplace}})
+		return
+	}
+
+	dryRun, err := strconv.ParseBool(c.DefaultQuery("dry_run", "false"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid dry_run value - " + c.Query("dry_run")})
+		return
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(c.GetHeader("Content-Type"))
+	// Bodies over the limit fail to read with *http.MaxBytesError
+	body := http.MaxBytesReader(c.Writer, c.Request.Body, maxImportBytes)
+
+	var passengers []model.Passenger
+	var rowErrors []validation.RowError
+	switch mediaType {
+	case "text/csv":
+		passengers, rowErrors, err = validation.ParseCSV(body)
+	case "application/json":
+		passengers, rowErrors, err = validation.ParseJSON(body)
+	default:
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": "Content-Type must be text/csv or application/json"})
+		return
+	}
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": fmt.Sprintf("Import is larger than %d bytes", tooLarge.Limit)})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if len(rowErrors) > 0 {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "import contains invalid rows", "errors": rowErrors})
+		return
+	}
+	if len(passengers) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "import contains no passengers"})
+		return
+	}
+
+	// The middleware has already checked If-Match, but only the repository
+	// can check it atomically with the write
+	var precondition repository.Precondition
+	if header := c.GetHeader("If-Match"); header != "" {
+		precondition = func(version string) bool {
+			return etagListMatches(header, `"`+version+`"`, false)
+		}
+	}
+
+	result, err := h.PassengerService.ImportPassengers(context.Background(), passengers, mode, dryRun, precondition)
+	if errors.Is(err, repository.ErrVersionMismatch) {
+		response := gin.H{"error": "dataset has been modified"}
+		if version, err := h.PassengerService.GetDataVersion(context.Background()); err == nil {
+			response["etag"] = `"` + version + `"`
+		}
+		c.JSON(http.StatusPreconditionFailed, response)
+		return
+	}
+	if errors.Is(err, repository.ErrRowsSkipped) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Hand back the new version so the client can chain conditional writes
+	if version, err := h.PassengerService.GetDataVersion(context.Background()); err == nil {
+		c.Header("ETag", `"`+version+`"`)
+	}
+	c.JSON(http.StatusOK, result)
+}
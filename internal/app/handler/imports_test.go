@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shindesatish/titanic-service/internal/app/repository"
+	"github.com/shindesatish/titanic-service/internal/app/service"
+	"github.com/shindesatish/titanic-service/pkg/model"
+)
+
+// importRepository is at version "v2" and records the imports it accepts.
+type importRepository struct {
+	service.Repository
+	imported []model.Passenger
+}
+
+func (r *importRepository) DataVersion() (string, error) {
+	return "v2", nil
+}
+
+func (r *importRepository) ImportPassengers(passengers []model.Passenger, mode repository.ImportMode, dryRun bool, precondition repository.Precondition) (*repository.ImportResult, error) {
+	if precondition != nil && !precondition("v2") {
+		return nil, repository.ErrVersionMismatch
+	}
+	r.imported = append(r.imported, passengers...)
+	return &repository.ImportResult{Mode: mode, DryRun: dryRun, Received: len(passengers), Inserted: len(passengers)}, nil
+}
+
+const importBody = `[{"PassengerId": 1000, "Survived": 1, "Pclass": 2, "Name": "Doe, Mr. John", "Sex": "male", "SibSp": 0, "Parch": 0, "Ticket": "X1", "Fare": 13}]`
+
+// TestImportPassengersIfMatch posts straight to the handler, past the
+// middleware, so only the repository sees If-Match.
+func TestImportPassengersIfMatch(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tests := []struct {
+		ifMatch string
+		status  int
+	}{
+		{"", http.StatusOK},
+		{"*", http.StatusOK},
+		{`"v2"`, http.StatusOK},
+		{`"v1", "v2.csv"`, http.StatusOK},
+		{`"v1"`, http.StatusPreconditionFailed},
+		{`W/"v2"`, http.StatusPreconditionFailed},
+	}
+	for _, tt := range tests {
+		repo := &importRepository{}
+		router := gin.New()
+		router.POST("/imports", NewPassengerHandler(service.NewPassengerService(repo)).ImportPassengersHandler)
+
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodPost, "/imports", strings.NewReader(importBody))
+		request.Header.Set("Content-Type", "application/json")
+		if tt.ifMatch != "" {
+			request.Header.Set("If-Match", tt.ifMatch)
+		}
+		router.ServeHTTP(recorder, request)
+
+		if recorder.Code != tt.status {
+			t.Errorf("If-Match %s: got status %d, want %d: %s", tt.ifMatch, recorder.Code, tt.status, recorder.Body)
+			continue
+		}
+		if tt.status == http.StatusOK {
+			if len(repo.imported) != 1 || recorder.Header().Get("ETag") != `"v2"` {
+				t.Errorf("If-Match %s: imported %v, ETag %s", tt.ifMatch, repo.imported, recorder.Header().Get("ETag"))
+			}
+			continue
+		}
+		var response struct{ Error, ETag string }
+		if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil || response.ETag != `"v2"` || len(repo.imported) != 0 {
+			t.Errorf("If-Match %s: got %s, imported %v", tt.ifMatch, recorder.Body, repo.imported)
+		}
+	}
+}
+
+func TestImportPassengersTooLarge(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	huge := strings.Repeat("x", maxImportBytes+1)
+	tests := []struct {
+		contentType string
+		body        string
+		status      int
+	}{
+		{"application/json", `[{"Name": "` + huge + `"}]`, http.StatusRequestEntityTooLarge},
+		{"text/csv", strings.Join(model.CSVHeader, ",") + "\n" + huge + "\n", http.StatusRequestEntityTooLarge},
+		{"text/csv", huge, http.StatusRequestEntityTooLarge},
+		// Malformed bodies within the limit are still a 400
+		{"application/json", `{"Name": "x"}`, http.StatusBadRequest},
+		{"text/csv", "", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		repo := &importRepository{}
+		router := gin.New()
+		router.POST("/imports", NewPassengerHandler(service.NewPassengerService(repo)).ImportPassengersHandler)
+
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodPost, "/imports", strings.NewReader(tt.body))
+		request.Header.Set("Content-Type", tt.contentType)
+		router.ServeHTTP(recorder, request)
+
+		var response struct{ Error string }
+		if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil || recorder.Code != tt.status || response.Error == "" {
+			t.Errorf("%s of %d bytes: got status %d, want %d: %.100s", tt.contentType, len(tt.body), recorder.Code, tt.status, recorder.Body)
+		}
+		if len(repo.imported) != 0 {
+			t.Errorf("%s of %d bytes: imported %d passengers", tt.contentType, len(tt.body), len(repo.imported))
+		}
+	}
+}
@@ -0,0 +1,64 @@
+// internal/app/handler/imputation.go
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shindesatish/titanic-service/internal/app/imputation"
+	"github.com/shindesatish/titanic-service/pkg/model"
+)
+
+// imputationParams are the query parameters read by imputer, which are
+// never attribute filters.
+var imputationParams = []string{"impute", "impute_by"}
+
+// imputer fits the strategy named by ?impute=, grouped by the comma
+// separated attributes of ?impute_by=, and announces it in the
+// X-Imputation header. It returns nil when no imputation was requested and
+// false after answering 400 for an invalid request.
+func (h *PassengerHandler) imputer(c *gin.Context) (*imputation.Imputer, bool) {
+	var by []string
+	for _, value := range c.QueryArray("impute_by") {
+		for _, attribute := range strings.Split(value, ",") {
+			if attribute = strings.TrimSpace(attribute); attribute != "" {
+				by = append(by, attribute)
+			}
+		}
+	}
+
+	imputer, err := h.PassengerService.FitImputer(c.Request.Context(), c.Query("impute"), by)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "allowed_impute": imputation.Strategies(), "allowed_attribute": model.AttributeNames()})
+		return nil, false
+	}
+	if imputer != nil {
+		c.Header("X-Imputation", imputer.String())
+	}
+	return imputer, true
+}
+
+// imputedStream applies an imputer to copies of streamed passengers and
+// remembers which fields of the current one it filled, so the JSON view
+// can report them.
+type imputedStream struct {
+	imputer *imputation.Imputer
+	fields  []string
+}
+
+func (s *imputedStream) wrap(fn func(*model.Passenger) error) func(*model.Passenger) error {
+	if s.imputer == nil {
+		return fn
+	}
+	return func(passenger *model.Passenger) error {
+		imputed := *passenger
+		s.fields = s.imputer.Apply(&imputed)
+		return fn(&imputed)
+	}
+}
+
+// imputed returns the fields filled in the current passenger.
+func (s *imputedStream) imputed() []string {
+	return s.fields
+}
@@ -0,0 +1,310 @@
+// internal/app/handler/openapi.go
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shindesatish/titanic-service/internal/app/dto"
+	"github.com/shindesatish/titanic-service/internal/app/export"
+	"github.com/shindesatish/titanic-service/internal/app/openapi"
+	"github.com/shindesatish/titanic-service/internal/app/repository"
+	"github.com/shindesatish/titanic-service/internal/app/service"
+	"github.com/shindesatish/titanic-service/pkg/model"
+)
+
+// Operations describes the routes of RegisterRoutes for the OpenAPI
+// document, the Swagger UI and request validation, keyed by method and path
+// under /v1. It is the only description of the API, so keep it next to the
+// handler changes: a route missing here is still listed in the document,
+// but without its parameters and unvalidated.
+var Operations = map[string]*openapi.Operation{
+	"GET /passengers": {
+		Summary:     "Get all passengers",
+		Description: "Get a list of all passengers in JSON, CSV or NDJSON format. JSON responses include the attributes derived from Name. Any query parameter named after an attribute (including Title, TitleGroup and Surname) filters on it.",
+		Tags:        []string{"passengers"},
+		Parameters: append([]openapi.Parameter{
+			formatParameter,
+			filterParameter,
+			openapi.Query("ids", "Comma separated PassengerIds to look up instead, as with POST /passengers:batchGet", openapi.String()),
+		}, imputationParameters...),
+		Responses: responses(http.StatusOK, http.StatusBadRequest, http.StatusNotAcceptable),
+	},
+	"POST /passengers:batchGet": {
+		Summary:     "Get passengers by ID",
+		Description: "Look up several passengers in one call. The passengers come back in the order requested, with the IDs no passenger has in missing_ids.",
+		Tags:        []string{"passengers"},
+		Parameters:  imputationParameters,
+		RequestBody: &openapi.RequestBody{
+			Required: true,
+			Content: map[string]openapi.MediaType{
+				"application/json": {Schema: openapi.Object(map[string]*openapi.Schema{
+					"ids": openapi.Array(openapi.Integer().AtLeast(0)),
+				}, "ids")},
+			},
+		},
+		Responses: responses(http.StatusOK, http.StatusBadRequest),
+	},
+	"GET /passengers/search": {
+		Summary:     "Search passengers",
+		Description: "Find passengers by Name, Ticket or Cabin, best matches first. substring matches anywhere in the text, token matches whole words or word prefixes, and fuzzy also tolerates misspellings (edit distance and Soundex).",
+		Tags:        []string{"passengers"},
+		Parameters: []openapi.Parameter{
+			openapi.Query("q", "Search text, e.g. Andersen", openapi.String()).Require(),
+			openapi.Query("mode", "Matching mode", openapi.String(model.SearchModes...).WithDefault(model.SearchToken)),
+			openapi.Query("limit", "Maximum number of results", openapi.Integer().Between(1, maxSearchResults).WithDefault(20)),
+		},
+		Responses: responses(http.StatusOK, http.StatusBadRequest),
+	},
+	"GET /passengers/{id}": {
+		Summary:     "Get passenger by ID",
+		Description: "Get passenger data by PassengerId in JSON format",
+		Tags:        []string{"passengers"},
+		Parameters:  append([]openapi.Parameter{passengerIDParameter}, imputationParameters...),
+		Responses:   responses(http.StatusOK, http.StatusBadRequest, http.StatusNotFound),
+	},
+	"GET /passengers/{id}/group": {
+		Summary:     "Get the travel group of a passenger",
+		Description: "Get the family or travel party the passenger belongs to. Passengers travelling alone form a group of one.",
+		Tags:        []string{"groups"},
+		Parameters:  []openapi.Parameter{passengerIDParameter},
+		Responses:   responses(http.StatusOK, http.StatusBadRequest, http.StatusNotFound),
+	},
+	"GET /groups": {
+		Summary:     "Get travel groups",
+		Description: "Get the families and travel parties reconstructed from shared tickets and surnames, with their members and survival outcome",
+		Tags:        []string{"groups"},
+		Parameters: []openapi.Parameter{
+			openapi.Query("min_size", "Only return groups with at least this many members", openapi.Integer().AtLeast(1).WithDefault(2)),
+		},
+		Responses: responses(http.StatusOK, http.StatusBadRequest),
+	},
+	"GET /tickets/{ticket}": {
+		Summary:     "Get the passengers on a ticket",
+		Description: "Get everyone travelling on a ticket together with the ticket's prefix, number and fare split per person. Tickets may contain slashes and spaces, e.g. /tickets/A/5%2021171.",
+		Tags:        []string{"tickets"},
+		Parameters: []openapi.Parameter{
+			openapi.PathParam("ticket", "Ticket as in titanic.csv; it may contain slashes", openapi.String()),
+		},
+		Responses: responses(http.StatusOK, http.StatusNotFound),
+	},
+	"GET /passenger-attributes/{id}": {
+		Summary:     "Get selected attributes of passenger by ID",
+		Description: "Get selected attributes of passenger by PassengerId in JSON, CSV or NDJSON format",
+		Tags:        []string{"passengers"},
+		Parameters: []openapi.Parameter{
+			passengerIDParameter,
+			openapi.Query("attributes", "Attributes to retrieve, one parameter each", openapi.Array(openapi.String(dto.AllowedAttributes...))).Require(),
+			formatParameter,
+		},
+		Responses: responses(http.StatusOK, http.StatusBadRequest, http.StatusNotFound, http.StatusNotAcceptable),
+	},
+	"GET /fare-histogram": {
+		Summary:     "Get fare histogram",
+		Description: "Get a histogram of fare prices in percentiles",
+		Tags:        []string{"passengers"},
+		Parameters:  []openapi.Parameter{fareParameter},
+		Responses:   responses(http.StatusOK, http.StatusBadRequest),
+	},
+	"GET /export": {
+		Summary:     "Export passengers",
+		Description: "Export the full or filtered passenger dataset as Parquet, an Arrow IPC stream, CSV, JSON or NDJSON. Any query parameter named after a passenger attribute filters on that attribute; repeat it or separate values with commas to allow several.",
+		Tags:        []string{"export"},
+		Parameters: append([]openapi.Parameter{
+			openapi.Query("format", "Export format", openapi.String(export.Formats...).WithDefault(export.FormatParquet)),
+			filterParameter,
+		}, imputationParameters...),
+		Responses: responses(http.StatusOK, http.StatusBadRequest),
+	},
+	"POST /imports": {
+		Summary:     "Import passengers",
+		Description: "Load passengers from a CSV file with the titanic.csv header or a JSON array. Every row is validated first and nothing is applied if any row is invalid.",
+		Tags:        []string{"imports"},
+		Parameters: []openapi.Parameter{
+			openapi.Query("mode", "How to merge the import", openapi.String(string(repository.ImportUpsert), string(repository.ImportReplace)).WithDefault(string(repository.ImportUpsert))),
+			openapi.Query("dry_run", "Validate and report the changes without applying them", openapi.Boolean()),
+			openapi.Header("If-Match", "Only apply the import if the dataset still has this ETag", openapi.String()),
+		},
+		RequestBody: &openapi.RequestBody{
+			Description: "Passengers as CSV with the titanic.csv header, or as a JSON array",
+			Required:    true,
+			Content: map[string]openapi.MediaType{
+				"text/csv":         {},
+				"application/json": {},
+			},
+		},
+		Responses: responses(http.StatusOK, http.StatusBadRequest, http.StatusConflict, http.StatusPreconditionFailed, http.StatusRequestEntityTooLarge, http.StatusUnsupportedMediaType, http.StatusUnprocessableEntity),
+	},
+	"GET /stats/summary": {
+		Summary:     "Get summary statistics",
+		Description: "Get passenger counts, survival rate and the distribution of Age, Fare, SibSp and Parch",
+		Tags:        []string{"stats"},
+		Parameters:  append([]openapi.Parameter{fareParameter}, imputationParameters...),
+		Responses:   responses(http.StatusOK, http.StatusBadRequest),
+	},
+	"GET /stats/survival": {
+		Summary:     "Get survival statistics",
+		Description: "Get the survival rate of each group of passengers sharing a value of the given attribute",
+		Tags:        []string{"stats"},
+		Parameters: []openapi.Parameter{
+			openapi.Query("by", "Attribute to group by, e.g. Sex, Pclass or Title", openapi.String()).Require(),
+		},
+		Responses: responses(http.StatusOK, http.StatusBadRequest),
+	},
+	"GET /stats/crosstab": {
+		Summary:     "Get a cross-tabulation",
+		Description: "Get a contingency table of passenger counts, or of the sum, mean or median of a numeric attribute, for every pair of rows and cols values",
+		Tags:        []string{"stats"},
+		Parameters: []openapi.Parameter{
+			openapi.Query("rows", "Attribute whose values label the rows, e.g. Pclass", openapi.String()).Require(),
+			openapi.Query("cols", "Attribute whose values label the columns, e.g. Sex", openapi.String()).Require(),
+			openapi.Query("value", "Numeric attribute to aggregate, e.g. Survived", openapi.String()),
+			openapi.Query("agg", "Aggregation", openapi.String("count", "sum", "mean", "median").WithDefault("count")),
+			openapi.Query("margins", "Include row and column margins and the grand total", openapi.Boolean()),
+			openapi.Query("normalize", "Divide count and sum cells by the grand, row or column total", openapi.String("all", "rows", "columns")),
+		},
+		Responses: responses(http.StatusOK, http.StatusBadRequest),
+	},
+	"GET /stats/tests/chi-square": {
+		Summary:     "Test independence of two attributes",
+		Description: "Run Pearson's chi-square test of independence (no continuity correction) on the contingency table of two categorical attributes",
+		Tags:        []string{"stats"},
+		Parameters: []openapi.Parameter{
+			openapi.Query("a", "First attribute, e.g. Pclass", openapi.String()).Require(),
+			openapi.Query("b", "Second attribute, e.g. Survived", openapi.String()).Require(),
+		},
+		Responses: responses(http.StatusOK, http.StatusBadRequest),
+	},
+	"GET /stats/tests/compare": {
+		Summary:     "Compare a numeric attribute between two groups",
+		Description: "Run Welch's t-test and the Mann–Whitney U test on a numeric attribute between two groups of passengers, e.g. Fare of survivors and non-survivors",
+		Tags:        []string{"stats"},
+		Parameters: []openapi.Parameter{
+			openapi.Query("value", "Numeric attribute to compare, e.g. Fare", openapi.String()).Require(),
+			openapi.Query("by", "Attribute that splits the passengers, e.g. Survived", openapi.String()).Require(),
+			openapi.Query("groups", "The two values of by to compare, comma separated; required when by has more than two values", openapi.String()),
+		},
+		Responses: responses(http.StatusOK, http.StatusBadRequest),
+	},
+	"GET /stats/correlations": {
+		Summary:     "Get a correlation matrix",
+		Description: "Get the Pearson or Spearman correlation, with p-values, of every pair of numeric attributes. Each pair uses the passengers that have both values.",
+		Tags:        []string{"stats"},
+		Parameters: []openapi.Parameter{
+			openapi.Query("attributes", "Comma separated numeric attributes", openapi.String()),
+			openapi.Query("method", "Correlation method", openapi.String(service.CorrelationPearson, service.CorrelationSpearman).WithDefault(service.CorrelationPearson)),
+		},
+		Responses: responses(http.StatusOK, http.StatusBadRequest),
+	},
+	"GET /validation": {
+		Summary:     "Get data-quality report",
+		Description: "Validate every row of the active dataset for type errors, out-of-range values, missing required fields and duplicate PassengerIds",
+		Tags:        []string{"validation"},
+		Parameters: []openapi.Parameter{
+			openapi.Query("format", "Report format", openapi.String("json", "text").WithDefault("json")),
+		},
+		Responses: responses(http.StatusOK, http.StatusBadRequest),
+	},
+	"GET /cache-stats": {
+		Summary:     "Get cache statistics",
+		Description: "Get hit/miss counters of the repository response cache",
+		Tags:        []string{"cache"},
+		Responses:   responses(http.StatusOK, http.StatusNotFound),
+	},
+	"POST /predict": {
+		Summary:     "Predict survival",
+		Description: "Get the probability that a passenger with the given Sex, Pclass, Age, Fare, Embarked, SibSp and Parch survived, according to a stored model. Retrain the model with \"titanic train\".",
+		Tags:        []string{"models"},
+		Parameters: []openapi.Parameter{
+			openapi.Query("model", "Name of the stored model", openapi.String().WithDefault("survival")),
+		},
+		RequestBody: &openapi.RequestBody{
+			Description: "Passenger to score; Age may be omitted when unknown",
+			Required:    true,
+			Content: map[string]openapi.MediaType{
+				"application/json": {Schema: openapi.Object(map[string]*openapi.Schema{
+					"Sex":      openapi.String("male", "female"),
+					"Pclass":   openapi.Integer().Between(1, 3),
+					"Age":      openapi.Number(),
+					"Fare":     openapi.Number().AtLeast(0),
+					"Embarked": openapi.String("C", "Q", "S"),
+					"SibSp":    openapi.Integer().AtLeast(0),
+					"Parch":    openapi.Integer().AtLeast(0),
+				}, "Sex", "Pclass")},
+			},
+		},
+		Responses: responses(http.StatusOK, http.StatusBadRequest, http.StatusNotFound),
+	},
+	"GET /models/{name}/evaluation": {
+		Summary:     "Evaluate a model",
+		Description: "Cross-validate the training options of a stored model: accuracy, precision, recall, F1, ROC AUC, confusion matrix and calibration buckets over out-of-fold predictions. The folds are drawn from a shuffle seeded with seed, so results are reproducible.",
+		Tags:        []string{"models"},
+		Parameters: []openapi.Parameter{
+			openapi.PathParam("name", "Name of the stored model", openapi.String()),
+			openapi.Query("folds", "Number of folds", openapi.Integer().Between(2, maxFolds).WithDefault(5)),
+			openapi.Query("seed", "Seed of the fold shuffle", openapi.Integer().WithDefault(1)),
+		},
+		Responses: responses(http.StatusOK, http.StatusBadRequest, http.StatusNotFound),
+	},
+	"GET /graphql": {
+		Summary:     "GraphQL endpoint",
+		Description: "Run a GraphQL query given in the query parameters, as with POST /graphql.",
+		Tags:        []string{"graphql"},
+		Parameters: []openapi.Parameter{
+			openapi.Query("query", "GraphQL query", openapi.String()),
+			openapi.Query("operationName", "Operation to run when the query has several", openapi.String()),
+			openapi.Query("variables", "JSON object of variables", openapi.String()),
+		},
+		Responses: responses(http.StatusOK, http.StatusBadRequest),
+	},
+	"POST /graphql": {
+		Summary:     "GraphQL endpoint",
+		Description: "Run a GraphQL query. Passenger fields use the attribute names (PassengerId, Pclass, TitleGroup...); passengers(filter, offset, limit), passenger(id), fareHistogram(fare), survival(by) and summary(fare) are the root fields.",
+		Tags:        []string{"graphql"},
+		RequestBody: &openapi.RequestBody{
+			Required: true,
+			Content: map[string]openapi.MediaType{
+				"application/json": {Schema: openapi.Object(map[string]*openapi.Schema{
+					"query":         openapi.String(),
+					"operationName": openapi.String(),
+					"variables":     openapi.Object(nil),
+				}, "query")},
+			},
+		},
+		Responses: responses(http.StatusOK, http.StatusBadRequest),
+	},
+	"GET /openapi.json": {
+		Summary:     "Get the OpenAPI document",
+		Description: "Get the OpenAPI 3.1 document of the API, generated from the registered routes",
+		Tags:        []string{"meta"},
+		Responses:   responses(http.StatusOK),
+	},
+}
+
+var (
+	passengerIDParameter = openapi.PathParam("id", "Passenger ID", openapi.Integer())
+	formatParameter      = openapi.Query("format", "Response format, overrides the Accept header", openapi.String(export.Formats...))
+	filterParameter      = openapi.Query("filter", "Filter expression, e.g. Age < 12 OR (Sex = 'female' AND Pclass IN (1, 2))", openapi.String())
+	fareParameter        = openapi.Query("fare", "Use the Fare per ticket or split between the passengers sharing it", openapi.String(string(service.FareRaw), string(service.FarePerPerson)).WithDefault(string(service.FareRaw)))
+	imputationParameters = []openapi.Parameter{
+		openapi.Query("impute", "Fill in missing Age and Embarked values, e.g. median", openapi.String()),
+		openapi.Query("impute_by", "Attributes to impute within, comma separated", openapi.Array(openapi.String())),
+	}
+)
+
+// responses documents the statuses an operation answers with.
+func responses(statuses ...int) map[string]openapi.Response {
+	documented := make(map[string]openapi.Response, len(statuses))
+	for _, status := range statuses {
+		documented[strconv.Itoa(status)] = openapi.Response{Description: http.StatusText(status)}
+	}
+	return documented
+}
+
+// OpenAPIHandler serves doc as JSON.
+func OpenAPIHandler(doc *openapi.Document) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, doc)
+	}
+}
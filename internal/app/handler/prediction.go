@@ -0,0 +1,77 @@
+// internal/app/handler/prediction.go
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shindesatish/titanic-service/internal/app/dto"
+	"github.com/shindesatish/titanic-service/internal/app/prediction"
+	"github.com/shindesatish/titanic-service/internal/app/service"
+)
+
+// maxFolds bounds the cost of a cross-validation request; each fold
+// retrains the model.
+const maxFolds = 10
+
+// PredictSurvivalHandler handles POST /predict.
+func (h *PassengerHandler) PredictSurvivalHandler(c *gin.Context) {
+	name := c.DefaultQuery("model", service.DefaultModel)
+	if !prediction.ValidName(name) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid model name - " + name})
+		return
+	}
+
+	var request dto.PredictRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := h.PassengerService.PredictSurvival(context.Background(), name, request.Passenger())
+	if errors.Is(err, prediction.ErrModelNotFound) || errors.Is(err, service.ErrModelsDisabled) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error() + " - " + name})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// GetModelEvaluationHandler handles GET /models/{name}/evaluation.
+func (h *PassengerHandler) GetModelEvaluationHandler(c *gin.Context) {
+	name := c.Param("name")
+	if !prediction.ValidName(name) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid model name - " + name})
+		return
+	}
+	folds, err := strconv.Atoi(c.DefaultQuery("folds", "5"))
+	if err != nil || folds < 2 || folds > maxFolds {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("folds must be between 2 and %d", maxFolds)})
+		return
+	}
+	seed, err := strconv.ParseInt(c.DefaultQuery("seed", "1"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid seed value - " + c.Query("seed")})
+		return
+	}
+
+	evaluation, err := h.PassengerService.EvaluateModel(context.Background(), name, folds, seed)
+	if errors.Is(err, prediction.ErrModelNotFound) || errors.Is(err, service.ErrModelsDisabled) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error() + " - " + name})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, evaluation)
+}
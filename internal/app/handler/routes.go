@@ -0,0 +1,40 @@
+// internal/app/handler/routes.go
+package handler
+
+import "github.com/gin-gonic/gin"
+
+// RegisterRoutes registers the passenger API on the /v1 group. It fails if
+// the GraphQL schema does not build.
+func (h *PassengerHandler) RegisterRoutes(v1 *gin.RouterGroup) error {
+	// Data routes carry ETags derived from the dataset version
+	data := v1.Group("", h.ConditionalRequestMiddleware())
+	data.GET("/passengers", h.GetAllPassengersHandler)
+	data.GET("/passengers/search", h.SearchPassengersHandler)
+	data.GET("/passengers/:id", h.GetPassengerByIDHandler)
+	data.POST("/passengers:action", h.PassengerActionHandler)
+	data.GET("/passengers/:id/group", h.GetPassengerGroupHandler)
+	data.GET("/groups", h.GetTravelGroupsHandler)
+	data.GET("/tickets/*ticket", h.GetTicketGroupHandler)
+	data.GET("/passenger-attributes/:id", h.GetPassengerAttributesHandler)
+	// Add a new route for histogram functionality
+	data.GET("/fare-histogram", h.GetFareHistogramHandler)
+	data.GET("/export", h.ExportPassengersHandler)
+	data.POST("/imports", h.ImportPassengersHandler)
+	data.GET("/stats/summary", h.GetSummaryStatsHandler)
+	data.GET("/stats/survival", h.GetSurvivalStatsHandler)
+	data.GET("/stats/crosstab", h.GetCrossTabHandler)
+	data.GET("/stats/tests/chi-square", h.GetChiSquareTestHandler)
+	data.GET("/stats/tests/compare", h.CompareGroupsHandler)
+	data.GET("/stats/correlations", h.GetCorrelationMatrixHandler)
+	data.GET("/validation", h.GetValidationReportHandler)
+	v1.GET("/cache-stats", h.GetCacheStatsHandler)
+	v1.POST("/predict", h.PredictSurvivalHandler)
+	v1.GET("/models/:name/evaluation", h.GetModelEvaluationHandler)
+	graphQL, err := h.GraphQLHandler()
+	if err != nil {
+		return err
+	}
+	v1.GET("/graphql", graphQL)
+	v1.POST("/graphql", graphQL)
+	return nil
+}
@@ -0,0 +1,58 @@
+// internal/app/handler/search.go
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shindesatish/titanic-service/internal/app/dto"
+	"github.com/shindesatish/titanic-service/pkg/model"
+)
+
+// maxSearchResults caps the limit parameter of a search.
+const maxSearchResults = 100
+
+// SearchResult is one passenger found by a search.
+type SearchResult struct {
+	Passenger dto.PassengerResponse `json:"passenger"`
+	Score     float64               `json:"score"`
+	Matched   []string              `json:"matched,omitempty"`
+}
+
+// SearchPassengersHandler handles GET /passengers/search.
+func (h *PassengerHandler) SearchPassengersHandler(c *gin.Context) {
+	query := c.Query("q")
+	if strings.TrimSpace(query) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No search text specified"})
+		return
+	}
+	mode := c.DefaultQuery("mode", model.SearchToken)
+	if !dto.Contains(model.SearchModes, mode) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid search mode - " + mode, "allowed_mode": model.SearchModes})
+		return
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit < 1 || limit > maxSearchResults {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and " + strconv.Itoa(maxSearchResults)})
+		return
+	}
+
+	hits, err := h.PassengerService.SearchPassengers(context.Background(), query, mode, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	results := make([]SearchResult, len(hits))
+	for i := range hits {
+		results[i] = SearchResult{
+			Passenger: dto.NewPassengerResponse(&hits[i].Passenger),
+			Score:     hits[i].Score,
+			Matched:   hits[i].Matched,
+		}
+	}
+	c.JSON(http.StatusOK, results)
+}
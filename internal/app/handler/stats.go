@@ -0,0 +1,102 @@
+// internal/app/handler/stats.go
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shindesatish/titanic-service/internal/app/service"
+	"github.com/shindesatish/titanic-service/pkg/model"
+)
+
+// GetSummaryStatsHandler handles GET /stats/summary.
+func (h *PassengerHandler) GetSummaryStatsHandler(c *gin.Context) {
+	basis, ok := fareBasis(c)
+	if !ok {
+		return
+	}
+
+	imputer, ok := h.imputer(c)
+	if !ok {
+		return
+	}
+
+	stats, err := h.PassengerService.GetSummaryStats(context.Background(), basis, imputer)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
+
+// GetSurvivalStatsHandler handles GET /stats/survival.
+func (h *PassengerHandler) GetSurvivalStatsHandler(c *gin.Context) {
+	by := c.Query("by")
+	if by == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No grouping attribute specified"})
+		return
+	}
+	if _, err := (&model.Passenger{}).Attribute(by); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid attribute specified - " + by, "allowed_attribute": model.AttributeNames()})
+		return
+	}
+
+	groups, err := h.PassengerService.GetSurvivalStats(context.Background(), by)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, groups)
+}
+
+// fareBasis reads the ?fare= parameter, answering 400 when it is invalid.
+func fareBasis(c *gin.Context) (service.FareBasis, bool) {
+	basis := service.FareBasis(c.DefaultQuery("fare", string(service.FareRaw)))
+	for _, allowed := range service.FareBases {
+		if basis == allowed {
+			return basis, true
+		}
+	}
+	c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid fare basis - " + string(basis), "allowed_fare": service.FareBases})
+	return "", false
+}
+
+// GetCrossTabHandler handles GET /stats/crosstab.
+func (h *PassengerHandler) GetCrossTabHandler(c *gin.Context) {
+	margins, err := strconv.ParseBool(c.DefaultQuery("margins", "false"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid margins value - " + c.Query("margins")})
+		return
+	}
+
+	request := service.CrossTabRequest{
+		Rows:      c.Query("rows"),
+		Cols:      c.Query("cols"),
+		Value:     c.Query("value"),
+		Agg:       c.DefaultQuery("agg", service.AggCount),
+		Normalize: c.Query("normalize"),
+		Margins:   margins,
+	}
+	if err := request.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":             err.Error(),
+			"allowed_attribute": model.AttributeNames(),
+			"allowed_value":     model.NumericAttributes,
+			"allowed_agg":       service.Aggregations,
+			"allowed_normalize": service.Normalizations,
+		})
+		return
+	}
+
+	table, err := h.PassengerService.GetCrossTab(context.Background(), request)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, table)
+}
@@ -0,0 +1,33 @@
+// internal/app/handler/tickets.go
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shindesatish/titanic-service/internal/app/service"
+)
+
+// GetTicketGroupHandler handles GET /tickets/{ticket}.
+func (h *PassengerHandler) GetTicketGroupHandler(c *gin.Context) {
+	ticket := strings.TrimPrefix(c.Param("ticket"), "/")
+	if strings.TrimSpace(ticket) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No ticket specified"})
+		return
+	}
+
+	group, err := h.PassengerService.GetTicketGroup(context.Background(), ticket)
+	if errors.Is(err, service.ErrTicketNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, group)
+}
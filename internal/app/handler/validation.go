@@ -0,0 +1,28 @@
+// internal/app/handler/validation.go
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetValidationReportHandler handles GET /validation.
+func (h *PassengerHandler) GetValidationReportHandler(c *gin.Context) {
+	report, err := h.PassengerService.ValidateDataset(context.Background())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if c.Query("format") == "text" {
+		c.Header("Content-Type", "text/plain; charset=utf-8")
+		c.Status(http.StatusOK)
+		if err := report.WriteText(c.Writer); err != nil {
+			c.Error(err)
+		}
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
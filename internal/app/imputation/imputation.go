@@ -0,0 +1,251 @@
+// internal/app/imputation/imputation.go
+package imputation
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/shindesatish/titanic-service/pkg/model"
+)
+
+// Fields that can be imputed
+const (
+	FieldAge      = "Age"
+	FieldEmbarked = "Embarked"
+)
+
+// None is the strategy name that turns imputation off.
+const None = "none"
+
+// Strategy chooses the value that fills a missing Age or Embarked from the
+// known values of the passenger's group.
+type Strategy interface {
+	Age(known []float64) float64
+	Embarked(known []string) string
+}
+
+var strategies = map[string]Strategy{
+	"mean":   meanStrategy{},
+	"median": medianStrategy{},
+	"mode":   modeStrategy{},
+}
+
+// Strategies lists the accepted strategy names, None included.
+func Strategies() []string {
+	names := []string{None}
+	for name := range strategies {
+		names = append(names, name)
+	}
+	sort.Strings(names[1:])
+	return names
+}
+
+// Imputer fills missing Age and Embarked values with the strategy's pick
+// for the passenger's group, falling back to the whole dataset when the
+// group has no known values. It only ever changes the passengers handed to
+// Apply, never the data source.
+type Imputer struct {
+	Strategy string
+	By       []string
+
+	ages         map[string]float64
+	embarked     map[string]string
+	allAges      float64
+	allEmbarked  string
+	haveAges     bool
+	haveEmbarked bool
+}
+
+// NewFitter starts fitting the named strategy, grouping passengers by the
+// attributes in by. Feed every passenger to Add, then call Imputer.
+func NewFitter(name string, by []string) (*Fitter, error) {
+	strategy, ok := strategies[strings.ToLower(name)]
+	if !ok {
+		return nil, fmt.Errorf("unknown imputation strategy: %s", name)
+	}
+	for _, attribute := range by {
+		if !model.IsAttribute(attribute) {
+			return nil, fmt.Errorf("unknown attribute: %s", attribute)
+		}
+	}
+	return &Fitter{
+		name:     strings.ToLower(name),
+		by:       by,
+		strategy: strategy,
+		ages:     make(map[string][]float64),
+		embarked: make(map[string][]string),
+	}, nil
+}
+
+// Fitter collects the known values an Imputer is fitted on.
+type Fitter struct {
+	name        string
+	by          []string
+	strategy    Strategy
+	ages        map[string][]float64
+	embarked    map[string][]string
+	allAges     []float64
+	allEmbarked []string
+}
+
+// Add records the known values of one passenger.
+func (f *Fitter) Add(p *model.Passenger) error {
+	key, err := groupKey(p, f.by)
+	if err != nil {
+		return err
+	}
+	if p.Age.Valid {
+		f.ages[key] = append(f.ages[key], p.Age.Float64)
+		f.allAges = append(f.allAges, p.Age.Float64)
+	}
+	if p.Embarked != "" {
+		f.embarked[key] = append(f.embarked[key], p.Embarked)
+		f.allEmbarked = append(f.allEmbarked, p.Embarked)
+	}
+	return nil
+}
+
+// Imputer returns the fitted Imputer.
+func (f *Fitter) Imputer() *Imputer {
+	imputer := &Imputer{
+		Strategy:     f.name,
+		By:           f.by,
+		ages:         make(map[string]float64, len(f.ages)),
+		embarked:     make(map[string]string, len(f.embarked)),
+		haveAges:     len(f.allAges) > 0,
+		haveEmbarked: len(f.allEmbarked) > 0,
+	}
+	for key, ages := range f.ages {
+		imputer.ages[key] = f.strategy.Age(ages)
+	}
+	for key, ports := range f.embarked {
+		imputer.embarked[key] = f.strategy.Embarked(ports)
+	}
+	if imputer.haveAges {
+		imputer.allAges = f.strategy.Age(f.allAges)
+	}
+	if imputer.haveEmbarked {
+		imputer.allEmbarked = f.strategy.Embarked(f.allEmbarked)
+	}
+	return imputer
+}
+
+// Apply fills the missing fields of p in place and returns the names of
+// the fields it filled.
+func (i *Imputer) Apply(p *model.Passenger) []string {
+	if p.Age.Valid && p.Embarked != "" {
+		return nil
+	}
+	key, _ := groupKey(p, i.By)
+
+	var imputed []string
+	if !p.Age.Valid {
+		if age, ok := i.ages[key]; ok {
+			p.Age = model.NewNullFloat64(age)
+		} else if i.haveAges {
+			p.Age = model.NewNullFloat64(i.allAges)
+		}
+		if p.Age.Valid {
+			imputed = append(imputed, FieldAge)
+		}
+	}
+	if p.Embarked == "" {
+		if port, ok := i.embarked[key]; ok {
+			p.Embarked = port
+		} else if i.haveEmbarked {
+			p.Embarked = i.allEmbarked
+		}
+		if p.Embarked != "" {
+			imputed = append(imputed, FieldEmbarked)
+		}
+	}
+	return imputed
+}
+
+// String describes the imputer, e.g. "median by Pclass,Sex".
+func (i *Imputer) String() string {
+	if len(i.By) == 0 {
+		return i.Strategy
+	}
+	return i.Strategy + " by " + strings.Join(i.By, ",")
+}
+
+func groupKey(p *model.Passenger, by []string) (string, error) {
+	values := make([]string, len(by))
+	for j, attribute := range by {
+		value, err := p.Attribute(attribute)
+		if err != nil {
+			return "", err
+		}
+		values[j] = value
+	}
+	return strings.Join(values, "\x1f"), nil
+}
+
+type meanStrategy struct{}
+
+func (meanStrategy) Age(known []float64) float64 {
+	var sum float64
+	for _, age := range known {
+		sum += age
+	}
+	return roundAge(sum / float64(len(known)))
+}
+
+func (meanStrategy) Embarked(known []string) string {
+	return mostCommon(known)
+}
+
+type medianStrategy struct{}
+
+func (medianStrategy) Age(known []float64) float64 {
+	sorted := append([]float64(nil), known...)
+	sort.Float64s(sorted)
+	n := len(sorted)
+	if n%2 == 1 {
+		return sorted[n/2]
+	}
+	return roundAge((sorted[n/2-1] + sorted[n/2]) / 2)
+}
+
+func (medianStrategy) Embarked(known []string) string {
+	return mostCommon(known)
+}
+
+type modeStrategy struct{}
+
+func (modeStrategy) Age(known []float64) float64 {
+	values := make([]string, len(known))
+	for j, age := range known {
+		values[j] = fmt.Sprint(age)
+	}
+	var age float64
+	fmt.Sscan(mostCommon(values), &age)
+	return age
+}
+
+func (modeStrategy) Embarked(known []string) string {
+	return mostCommon(known)
+}
+
+// mostCommon returns the most frequent value, the smallest one on ties.
+func mostCommon(values []string) string {
+	counts := make(map[string]int)
+	for _, value := range values {
+		counts[value]++
+	}
+	var best string
+	for value, count := range counts {
+		if count > counts[best] || (count == counts[best] && value < best) {
+			best = value
+		}
+	}
+	return best
+}
+
+// roundAge keeps computed ages to one decimal place.
+func roundAge(age float64) float64 {
+	return math.Round(age*10) / 10
+}
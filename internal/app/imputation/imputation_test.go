@@ -0,0 +1,137 @@
+package imputation
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/shindesatish/titanic-service/pkg/model"
+)
+
+func passenger(pclass int, sex string, age float64, embarked string) model.Passenger {
+	p := model.Passenger{Pclass: pclass, Sex: sex, Embarked: embarked}
+	if age >= 0 {
+		p.Age = model.NewNullFloat64(age)
+	}
+	return p
+}
+
+// fit fits strategy grouped by by to passengers, failing the test on errors.
+func fit(t *testing.T, strategy string, by []string, passengers ...model.Passenger) *Imputer {
+	t.Helper()
+	fitter, err := NewFitter(strategy, by)
+	if err != nil {
+		t.Fatalf("NewFitter: %v", err)
+	}
+	for i := range passengers {
+		if err := fitter.Add(&passengers[i]); err != nil {
+			t.Fatalf("Add: %v", err)
+		}
+	}
+	return fitter.Imputer()
+}
+
+func TestStrategies(t *testing.T) {
+	known := []model.Passenger{
+		passenger(1, "female", 20, "S"),
+		passenger(1, "female", 20, "C"),
+		passenger(1, "female", 35, "C"),
+		passenger(1, "female", 60, "S"),
+		passenger(1, "female", 2, "Q"),
+	}
+	tests := []struct {
+		strategy string
+		age      float64
+		embarked string
+	}{
+		{"mean", 27.4, "C"},
+		{"median", 20, "C"},
+		{"mode", 20, "C"},
+		{"MEDIAN", 20, "C"},
+	}
+	for _, tt := range tests {
+		imputer := fit(t, tt.strategy, nil, known...)
+		p := passenger(2, "male", -1, "")
+		if fields := imputer.Apply(&p); !reflect.DeepEqual(fields, []string{FieldAge, FieldEmbarked}) {
+			t.Errorf("%s: filled %v", tt.strategy, fields)
+		}
+		if p.Age != model.NewNullFloat64(tt.age) || p.Embarked != tt.embarked {
+			t.Errorf("%s: got Age %v and Embarked %q, want %v and %q", tt.strategy, p.Age, p.Embarked, tt.age, tt.embarked)
+		}
+	}
+
+	// An even count of ages takes the midpoint, rounded to one decimal
+	imputer := fit(t, "median", nil, passenger(1, "male", 20, "S"), passenger(1, "male", 25.25, "S"))
+	p := passenger(1, "male", -1, "S")
+	if imputer.Apply(&p); p.Age != model.NewNullFloat64(22.6) {
+		t.Errorf("got median %v, want 22.6", p.Age)
+	}
+
+	if names := Strategies(); !reflect.DeepEqual(names, []string{None, "mean", "median", "mode"}) {
+		t.Errorf("got strategies %v", names)
+	}
+}
+
+func TestImputerGroups(t *testing.T) {
+	imputer := fit(t, "median", []string{"Pclass", "Sex"},
+		passenger(1, "female", 30, "C"),
+		passenger(1, "female", 40, "C"),
+		passenger(3, "male", 20, "S"),
+		passenger(3, "male", -1, "Q"),
+		passenger(3, "male", -1, "Q"),
+	)
+	if got := imputer.String(); got != "median by Pclass,Sex" {
+		t.Errorf("got %q", got)
+	}
+
+	tests := []struct {
+		p        model.Passenger
+		age      float64
+		embarked string
+		fields   []string
+	}{
+		{passenger(1, "female", -1, ""), 35, "C", []string{FieldAge, FieldEmbarked}},
+		{passenger(3, "male", -1, ""), 20, "Q", []string{FieldAge, FieldEmbarked}},
+		// No passengers in the group, so the whole dataset decides
+		{passenger(2, "female", -1, ""), 30, "C", []string{FieldAge, FieldEmbarked}},
+		// Known values are left alone
+		{passenger(1, "female", 5, ""), 5, "C", []string{FieldEmbarked}},
+		{passenger(3, "male", -1, "C"), 20, "C", []string{FieldAge}},
+		{passenger(3, "male", 50, "S"), 50, "S", nil},
+	}
+	for _, tt := range tests {
+		p := tt.p
+		fields := imputer.Apply(&p)
+		if !reflect.DeepEqual(fields, tt.fields) || p.Age != model.NewNullFloat64(tt.age) || p.Embarked != tt.embarked {
+			t.Errorf("%d %s: got Age %v, Embarked %q and fields %v, want %v, %q and %v",
+				tt.p.Pclass, tt.p.Sex, p.Age, p.Embarked, fields, tt.age, tt.embarked, tt.fields)
+		}
+	}
+
+	// Without any known values there is nothing to fill in
+	empty := fit(t, "mean", nil, passenger(1, "male", -1, ""))
+	p := passenger(1, "male", -1, "")
+	if fields := empty.Apply(&p); fields != nil || p.Age.Valid || p.Embarked != "" {
+		t.Errorf("got %+v and fields %v", p, fields)
+	}
+	if got := empty.String(); got != "mean" {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestNewFitterErrors(t *testing.T) {
+	tests := []struct {
+		strategy string
+		by       []string
+		err      string
+	}{
+		{"guess", nil, "unknown imputation strategy: guess"},
+		{None, nil, "unknown imputation strategy: none"},
+		{"mean", []string{"Pclass", "Colour"}, "unknown attribute: Colour"},
+	}
+	for _, tt := range tests {
+		if _, err := NewFitter(tt.strategy, tt.by); err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("%s by %v: got error %v, want %q", tt.strategy, tt.by, err, tt.err)
+		}
+	}
+}
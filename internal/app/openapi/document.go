@@ -0,0 +1,204 @@
+// internal/app/openapi/document.go
+
+// Package openapi builds an OpenAPI 3.1 document from the routes registered
+// on a Gin engine and validates requests against it.
+//
+// Operations are described in a table keyed by method and OpenAPI path,
+// e.g. "GET /passengers/{id}". The document is generated from the engine's
+// route table, so every registered route is listed even when the table has
+// no entry for it, and entries for routes that are gone are dropped.
+package openapi
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Version is the OpenAPI version of the generated documents.
+const Version = "3.1.0"
+
+// Document is an OpenAPI document.
+type Document struct {
+	OpenAPI string              `json:"openapi"`
+	Info    Info                `json:"info"`
+	Servers []Server            `json:"servers,omitempty"`
+	Paths   map[string]PathItem `json:"paths"`
+	Tags    []Tag               `json:"tags,omitempty"`
+}
+
+// Info describes the API.
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+// Server is a base URL of the API.
+type Server struct {
+	URL string `json:"url"`
+}
+
+// Tag groups operations.
+type Tag struct {
+	Name string `json:"name"`
+}
+
+// PathItem maps the lower-case HTTP methods of a path to its operations.
+type PathItem map[string]*Operation
+
+// Operation describes one method on one path.
+type Operation struct {
+	Summary     string              `json:"summary,omitempty"`
+	Description string              `json:"description,omitempty"`
+	OperationID string              `json:"operationId,omitempty"`
+	Tags        []string            `json:"tags,omitempty"`
+	Parameters  []Parameter         `json:"parameters,omitempty"`
+	RequestBody *RequestBody        `json:"requestBody,omitempty"`
+	Responses   map[string]Response `json:"responses"`
+}
+
+// Parameter is a path, query or header parameter.
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+	// Explode is set for array query parameters given as repeated keys,
+	// e.g. ?attributes=Name&attributes=Age
+	Explode *bool `json:"explode,omitempty"`
+}
+
+// RequestBody lists the accepted media types of a request body.
+type RequestBody struct {
+	Description string               `json:"description,omitempty"`
+	Required    bool                 `json:"required,omitempty"`
+	Content     map[string]MediaType `json:"content"`
+}
+
+// MediaType gives the schema of a body in one media type; a nil Schema
+// accepts any content.
+type MediaType struct {
+	Schema *Schema `json:"schema,omitempty"`
+}
+
+// Response describes a response status.
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+// Schema is the subset of JSON Schema used by the API.
+type Schema struct {
+	Type        string             `json:"type,omitempty"`
+	Description string             `json:"description,omitempty"`
+	Enum        []string           `json:"enum,omitempty"`
+	Default     interface{}        `json:"default,omitempty"`
+	Minimum     *float64           `json:"minimum,omitempty"`
+	Maximum     *float64           `json:"maximum,omitempty"`
+	Items       *Schema            `json:"items,omitempty"`
+	Properties  map[string]*Schema `json:"properties,omitempty"`
+	Required    []string           `json:"required,omitempty"`
+}
+
+// Path converts a Gin route path to an OpenAPI path, e.g. /passengers/:id
+// to /passengers/{id} and /tickets/*ticket to /tickets/{ticket}. Parameters
+// inside a segment, as in /passengers:action, are kept: they dispatch
+// custom methods, which are documented by their concrete paths.
+func Path(ginPath string) string {
+	segments := strings.Split(ginPath, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") {
+			segments[i] = "{" + segment[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// Key returns the key of an operation in an operations table.
+func Key(method, path string) string {
+	return method + " " + path
+}
+
+// Build generates the document of the routes under basePath. Routes without
+// an entry in operations are listed with their path parameters only.
+func Build(info Info, basePath string, routes gin.RoutesInfo, operations map[string]*Operation) *Document {
+	doc := &Document{
+		OpenAPI: Version,
+		Info:    info,
+		Servers: []Server{{URL: basePath}},
+		Paths:   map[string]PathItem{},
+	}
+
+	tags := map[string]bool{}
+	for _, route := range routes {
+		if !strings.HasPrefix(route.Path, basePath+"/") {
+			continue
+		}
+		path := Path(strings.TrimPrefix(route.Path, basePath))
+		documented := map[string]*Operation{}
+		if operation, ok := operations[Key(route.Method, path)]; ok {
+			documented[path] = operation
+		} else if prefix, ok := customMethodPrefix(path); ok {
+			// A route such as /passengers:action serves every documented
+			// /passengers:<method>
+			for key, operation := range operations {
+				if strings.HasPrefix(key, Key(route.Method, prefix)) {
+					documented[strings.TrimPrefix(key, route.Method+" ")] = operation
+				}
+			}
+		}
+		if len(documented) == 0 {
+			documented[path] = &Operation{
+				Parameters: pathParameters(path),
+				Responses:  map[string]Response{"200": {Description: "OK"}},
+			}
+		}
+
+		for path, operation := range documented {
+			if doc.Paths[path] == nil {
+				doc.Paths[path] = PathItem{}
+			}
+			doc.Paths[path][strings.ToLower(route.Method)] = operation
+			for _, tag := range operation.Tags {
+				tags[tag] = true
+			}
+		}
+	}
+
+	for tag := range tags {
+		doc.Tags = append(doc.Tags, Tag{Name: tag})
+	}
+	sort.Slice(doc.Tags, func(i, j int) bool { return doc.Tags[i].Name < doc.Tags[j].Name })
+	return doc
+}
+
+// customMethodPrefix reports whether the last segment of an OpenAPI path
+// ends in a Gin parameter, as in /passengers:action, and returns the path
+// up to and including the colon.
+func customMethodPrefix(path string) (string, bool) {
+	colon := strings.LastIndex(path, ":")
+	if colon < 0 || colon < strings.LastIndex(path, "/") {
+		return "", false
+	}
+	return path[:colon+1], true
+}
+
+// pathParameters returns a required string parameter for each {name} in an
+// OpenAPI path.
+func pathParameters(path string) []Parameter {
+	var parameters []Parameter
+	for _, segment := range strings.Split(path, "/") {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			parameters = append(parameters, Parameter{
+				Name:     segment[1 : len(segment)-1],
+				In:       "path",
+				Required: true,
+				Schema:   &Schema{Type: "string"},
+			})
+		}
+	}
+	return parameters
+}
@@ -0,0 +1,238 @@
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// operations documents the routes of newRouter, except GET /undocumented.
+var operations = map[string]*Operation{
+	"GET /passengers/{id}": {
+		Summary:    "Get passenger by ID",
+		Tags:       []string{"passengers"},
+		Parameters: []Parameter{PathParam("id", "Passenger ID", Integer().AtLeast(1))},
+		Responses:  map[string]Response{"200": {Description: "OK"}},
+	},
+	"GET /passenger-attributes/{id}": {
+		Summary: "Get selected attributes",
+		Tags:    []string{"passengers"},
+		Parameters: []Parameter{
+			PathParam("id", "Passenger ID", Integer()),
+			Query("attributes", "Attributes to retrieve", Array(String("Name", "Age"))).Require(),
+		},
+		Responses: map[string]Response{"200": {Description: "OK"}},
+	},
+	"GET /tickets/{ticket}": {
+		Summary:    "Get a ticket",
+		Tags:       []string{"tickets"},
+		Parameters: []Parameter{PathParam("ticket", "Ticket", String())},
+		Responses:  map[string]Response{"200": {Description: "OK"}},
+	},
+	"POST /passengers:batchGet": {
+		Summary: "Get passengers by ID",
+		Tags:    []string{"passengers"},
+		RequestBody: &RequestBody{
+			Required: true,
+			Content: map[string]MediaType{
+				"application/json": {Schema: Object(map[string]*Schema{"ids": Array(Integer().AtLeast(0))}, "ids")},
+			},
+		},
+		Responses: map[string]Response{"200": {Description: "OK"}},
+	},
+	"POST /predict": {
+		Summary: "Predict survival",
+		Tags:    []string{"models"},
+		RequestBody: &RequestBody{
+			Required: true,
+			Content: map[string]MediaType{
+				"application/json": {Schema: Object(map[string]*Schema{
+					"Sex":    String("male", "female"),
+					"Pclass": Integer().Between(1, 3),
+				}, "Sex", "Pclass")},
+			},
+		},
+		Responses: map[string]Response{"200": {Description: "OK"}},
+	},
+	"POST /imports": {
+		Summary: "Import passengers",
+		Tags:    []string{"imports"},
+		RequestBody: &RequestBody{
+			Required: true,
+			Content:  map[string]MediaType{"text/csv": {}, "application/json": {}},
+		},
+		Responses: map[string]Response{"200": {Description: "OK"}},
+	},
+	"GET /gone": {
+		Summary:   "A route that is no longer registered",
+		Tags:      []string{"gone"},
+		Responses: map[string]Response{"200": {Description: "OK"}},
+	},
+}
+
+// newRouter registers a small API under /v1, validated against operations
+// when validate is set; every handler answers 200 with its own path.
+func newRouter(validate bool) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	v1 := router.Group("/v1")
+	if validate {
+		v1.Use(Validator("/v1", operations))
+	}
+	ok := func(c *gin.Context) { c.JSON(http.StatusOK, gin.H{"path": c.FullPath()}) }
+	v1.GET("/passengers/:id", ok)
+	v1.GET("/passenger-attributes/:id", ok)
+	v1.GET("/tickets/*ticket", ok)
+	v1.POST("/passengers:action", ok)
+	v1.POST("/predict", ok)
+	v1.POST("/imports", ok)
+	v1.GET("/undocumented/:name", ok)
+	router.GET("/healthz", ok)
+	return router
+}
+
+func TestPath(t *testing.T) {
+	tests := map[string]string{
+		"/passengers":         "/passengers",
+		"/passengers/:id":     "/passengers/{id}",
+		"/tickets/*ticket":    "/tickets/{ticket}",
+		"/models/:name/evals": "/models/{name}/evals",
+		"/passengers:action":  "/passengers:action",
+	}
+	for ginPath, want := range tests {
+		if got := Path(ginPath); got != want {
+			t.Errorf("Path(%q) = %q, want %q", ginPath, got, want)
+		}
+	}
+}
+
+func TestBuild(t *testing.T) {
+	router := newRouter(false)
+	doc := Build(Info{Title: "Test", Version: "1"}, "/v1", router.Routes(), operations)
+
+	if doc.OpenAPI != Version || len(doc.Servers) != 1 || doc.Servers[0].URL != "/v1" {
+		t.Errorf("got header %q %v", doc.OpenAPI, doc.Servers)
+	}
+
+	// Every route under /v1 has an operation, documented or not
+	for _, route := range router.Routes() {
+		if !strings.HasPrefix(route.Path, "/v1/") {
+			if _, ok := doc.Paths[Path(route.Path)]; ok {
+				t.Errorf("%s is outside the base path but documented", route.Path)
+			}
+			continue
+		}
+		path := Path(strings.TrimPrefix(route.Path, "/v1"))
+		if path == "/passengers:action" {
+			path = "/passengers:batchGet"
+		}
+		if doc.Paths[path][strings.ToLower(route.Method)] == nil {
+			t.Errorf("%s %s has no operation", route.Method, route.Path)
+		}
+	}
+
+	if operation := doc.Paths["/passengers/{id}"]["get"]; operation != operations["GET /passengers/{id}"] {
+		t.Errorf("GET /passengers/{id}: got %+v, want the documented operation", operation)
+	}
+	if _, ok := doc.Paths["/passengers:action"]; ok {
+		t.Errorf("the custom method route is listed under its Gin path")
+	}
+	if _, ok := doc.Paths["/gone"]; ok {
+		t.Errorf("an operation without a route is listed")
+	}
+
+	undocumented := doc.Paths["/undocumented/{name}"]["get"]
+	if undocumented == nil || len(undocumented.Parameters) != 1 || undocumented.Parameters[0].Name != "name" || !undocumented.Parameters[0].Required {
+		t.Errorf("undocumented route: got %+v, want its path parameter", undocumented)
+	}
+
+	var tags []string
+	for _, tag := range doc.Tags {
+		tags = append(tags, tag.Name)
+	}
+	if strings.Join(tags, ",") != "imports,models,passengers,tickets" {
+		t.Errorf("got tags %v", tags)
+	}
+
+	if _, err := json.Marshal(doc); err != nil {
+		t.Errorf("document does not encode: %v", err)
+	}
+}
+
+func TestValidator(t *testing.T) {
+	router := newRouter(true)
+	tests := []struct {
+		name        string
+		method      string
+		target      string
+		contentType string
+		body        string
+		status      int
+		// error is a substring of the error message
+		error string
+	}{
+		{"valid path parameter", "GET", "/v1/passengers/1", "", "", http.StatusOK, ""},
+		{"path parameter not an integer", "GET", "/v1/passengers/abc", "", "", http.StatusBadRequest, `Invalid path parameter id - "abc" is not an integer`},
+		{"path parameter out of range", "GET", "/v1/passengers/0", "", "", http.StatusBadRequest, "Invalid path parameter id - 0 is less than the minimum of 1"},
+		{"wildcard path parameter", "GET", "/v1/tickets/A/5 21171", "", "", http.StatusOK, ""},
+		{"valid query array", "GET", "/v1/passenger-attributes/1?attributes=Name&attributes=Age", "", "", http.StatusOK, ""},
+		{"missing query array", "GET", "/v1/passenger-attributes/1", "", "", http.StatusBadRequest, "Missing required query parameter - attributes"},
+		{"bad value in query array", "GET", "/v1/passenger-attributes/1?attributes=Name&attributes=Shoe", "", "", http.StatusBadRequest, `Invalid query parameter attributes - "Shoe" is not one of Name, Age`},
+		{"valid body", "POST", "/v1/predict", "application/json", `{"Sex": "female", "Pclass": 1}`, http.StatusOK, ""},
+		{"body without Content-Type", "POST", "/v1/predict", "", `{"Sex": "female", "Pclass": 1}`, http.StatusOK, ""},
+		{"missing body", "POST", "/v1/predict", "application/json", "", http.StatusBadRequest, "Missing request body"},
+		{"malformed body", "POST", "/v1/predict", "application/json", `{"Sex":`, http.StatusBadRequest, "Invalid JSON body"},
+		{"body missing a required field", "POST", "/v1/predict", "application/json", `{"Sex": "female"}`, http.StatusBadRequest, "Invalid request body - body.Pclass is required"},
+		{"body field of the wrong type", "POST", "/v1/predict", "application/json", `{"Sex": "female", "Pclass": "1"}`, http.StatusBadRequest, "Invalid request body - body.Pclass must be an integer"},
+		{"body field out of range", "POST", "/v1/predict", "application/json", `{"Sex": "female", "Pclass": 4}`, http.StatusBadRequest, "Invalid request body - body.Pclass: 4 is greater than the maximum of 3"},
+		{"body field not allowed", "POST", "/v1/predict", "application/json", `{"Sex": "other", "Pclass": 1}`, http.StatusBadRequest, `Invalid request body - body.Sex: "other" is not one of male, female`},
+		{"body array item", "POST", "/v1/passengers:batchGet", "application/json", `{"ids": [1, -2]}`, http.StatusBadRequest, "Invalid request body - body.ids[1]: -2 is less than the minimum of 0"},
+		{"body too large", "POST", "/v1/predict", "application/json", `{"Sex": "` + strings.Repeat("x", maxValidatedBody) + `"}`, http.StatusRequestEntityTooLarge, "Request body is too large"},
+		{"unsupported media type", "POST", "/v1/imports", "text/plain", "1", http.StatusUnsupportedMediaType, "Content-Type must be application/json or text/csv"},
+		{"body without a schema", "POST", "/v1/imports", "text/csv", "not,validated", http.StatusOK, ""},
+		{"undocumented route", "GET", "/v1/undocumented/x", "", "", http.StatusOK, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(tt.method, strings.ReplaceAll(tt.target, " ", "%20"), strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				request.Header.Set("Content-Type", tt.contentType)
+			}
+			router.ServeHTTP(recorder, request)
+
+			if recorder.Code != tt.status {
+				t.Fatalf("got status %d, want %d: %s", recorder.Code, tt.status, recorder.Body)
+			}
+			var body struct {
+				Error string `json:"error"`
+			}
+			if err := json.Unmarshal(recorder.Body.Bytes(), &body); err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(body.Error, tt.error) || (tt.error == "") != (body.Error == "") {
+				t.Errorf("got error %q, want %q", body.Error, tt.error)
+			}
+		})
+	}
+}
+
+func TestValidatorAllowedValues(t *testing.T) {
+	router := newRouter(true)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest("GET", "/v1/passenger-attributes/1?attributes=Shoe", nil))
+
+	var body struct {
+		Allowed []string `json:"allowed_attributes"`
+	}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(body.Allowed, ",") != "Name,Age" {
+		t.Errorf("got allowed_attributes %v, want Name,Age", body.Allowed)
+	}
+}
@@ -0,0 +1,76 @@
+// internal/app/openapi/schema.go
+package openapi
+
+// Query returns an optional query parameter.
+func Query(name, description string, schema *Schema) Parameter {
+	parameter := Parameter{Name: name, In: "query", Description: description, Schema: schema}
+	if schema.Type == "array" {
+		explode := true
+		parameter.Explode = &explode
+	}
+	return parameter
+}
+
+// PathParam returns a path parameter, which is always required.
+func PathParam(name, description string, schema *Schema) Parameter {
+	return Parameter{Name: name, In: "path", Description: description, Required: true, Schema: schema}
+}
+
+// Header returns an optional header parameter.
+func Header(name, description string, schema *Schema) Parameter {
+	return Parameter{Name: name, In: "header", Description: description, Schema: schema}
+}
+
+// Require marks the parameter as required.
+func (p Parameter) Require() Parameter {
+	p.Required = true
+	return p
+}
+
+// String returns a string schema, limited to enum when given.
+func String(enum ...string) *Schema {
+	return &Schema{Type: "string", Enum: enum}
+}
+
+// Integer returns an integer schema.
+func Integer() *Schema {
+	return &Schema{Type: "integer"}
+}
+
+// Number returns a number schema.
+func Number() *Schema {
+	return &Schema{Type: "number"}
+}
+
+// Boolean returns a boolean schema.
+func Boolean() *Schema {
+	return &Schema{Type: "boolean"}
+}
+
+// Array returns an array schema of items.
+func Array(items *Schema) *Schema {
+	return &Schema{Type: "array", Items: items}
+}
+
+// Object returns an object schema with the given properties.
+func Object(properties map[string]*Schema, required ...string) *Schema {
+	return &Schema{Type: "object", Properties: properties, Required: required}
+}
+
+// Between limits a numeric schema to [min, max].
+func (s *Schema) Between(min, max float64) *Schema {
+	s.Minimum, s.Maximum = &min, &max
+	return s
+}
+
+// AtLeast limits a numeric schema to values of at least min.
+func (s *Schema) AtLeast(min float64) *Schema {
+	s.Minimum = &min
+	return s
+}
+
+// WithDefault documents the value used when the parameter is omitted.
+func (s *Schema) WithDefault(value interface{}) *Schema {
+	s.Default = value
+	return s
+}
@@ -0,0 +1,279 @@
+// internal/app/openapi/validate.go
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxValidatedBody caps the JSON bodies read for validation; bodies without
+// a schema, such as imports, are streamed to the handler unread.
+const maxValidatedBody = 1 << 20
+
+// validationError is a request that does not match its operation.
+type validationError struct {
+	status int
+	body   gin.H
+}
+
+// Validator returns middleware that checks requests against their operation
+// in operations before the handler runs. basePath is stripped from the route
+// path to find the operation; routes without one pass through.
+//
+// Path, query and header parameters are checked for presence, type, range
+// and allowed values, and bodies for their media type and JSON schema.
+// Invalid requests are rejected with 400 Bad Request, 413 Request Entity Too
+// Large or 415 Unsupported Media Type and an error naming the problem.
+func Validator(basePath string, operations map[string]*Operation) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		operation, ok := operations[Key(c.Request.Method, Path(strings.TrimPrefix(c.FullPath(), basePath)))]
+		if !ok {
+			// Custom methods are documented by their concrete paths
+			operation, ok = operations[Key(c.Request.Method, strings.TrimPrefix(c.Request.URL.Path, basePath))]
+		}
+		if !ok {
+			c.Next()
+			return
+		}
+		if err := validateRequest(c, operation); err != nil {
+			c.AbortWithStatusJSON(err.status, err.body)
+			return
+		}
+		c.Next()
+	}
+}
+
+func validateRequest(c *gin.Context, operation *Operation) *validationError {
+	for _, parameter := range operation.Parameters {
+		if err := validateParameter(c, parameter); err != nil {
+			return err
+		}
+	}
+	if operation.RequestBody != nil {
+		return validateBody(c, operation.RequestBody)
+	}
+	return nil
+}
+
+func validateParameter(c *gin.Context, parameter Parameter) *validationError {
+	var values []string
+	switch parameter.In {
+	case "path":
+		values = []string{strings.TrimPrefix(c.Param(parameter.Name), "/")}
+	case "query":
+		if parameter.Schema.Type == "array" {
+			values = c.QueryArray(parameter.Name)
+		} else if value, ok := c.GetQuery(parameter.Name); ok {
+			values = []string{value}
+		}
+	case "header":
+		if value := c.GetHeader(parameter.Name); value != "" {
+			values = []string{value}
+		}
+	}
+
+	if len(values) == 0 || values[0] == "" {
+		if !parameter.Required {
+			return nil
+		}
+		return &validationError{http.StatusBadRequest, gin.H{
+			"error": fmt.Sprintf("Missing required %s parameter - %s", parameter.In, parameter.Name),
+		}}
+	}
+
+	schema := parameter.Schema
+	if schema.Type == "array" {
+		schema = schema.Items
+	}
+	for _, value := range values {
+		if err := checkValue(schema, value); err != nil {
+			body := gin.H{"error": fmt.Sprintf("Invalid %s parameter %s - %v", parameter.In, parameter.Name, err)}
+			if len(schema.Enum) > 0 {
+				body["allowed_"+parameter.Name] = schema.Enum
+			}
+			return &validationError{http.StatusBadRequest, body}
+		}
+	}
+	return nil
+}
+
+// checkValue checks a parameter value, which is always a string on the
+// wire, against a scalar schema.
+func checkValue(schema *Schema, value string) error {
+	switch schema.Type {
+	case "integer":
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return fmt.Errorf("%q is not an integer", value)
+		}
+		return checkRange(schema, float64(n))
+	case "number":
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("%q is not a number", value)
+		}
+		return checkRange(schema, f)
+	case "boolean":
+		if _, err := strconv.ParseBool(value); err != nil {
+			return fmt.Errorf("%q is not a boolean", value)
+		}
+	}
+	return checkEnum(schema, value)
+}
+
+func checkRange(schema *Schema, f float64) error {
+	if schema.Minimum != nil && f < *schema.Minimum {
+		return fmt.Errorf("%v is less than the minimum of %v", f, *schema.Minimum)
+	}
+	if schema.Maximum != nil && f > *schema.Maximum {
+		return fmt.Errorf("%v is greater than the maximum of %v", f, *schema.Maximum)
+	}
+	return nil
+}
+
+func checkEnum(schema *Schema, value string) error {
+	if len(schema.Enum) == 0 {
+		return nil
+	}
+	for _, allowed := range schema.Enum {
+		if value == allowed {
+			return nil
+		}
+	}
+	return fmt.Errorf("%q is not one of %s", value, strings.Join(schema.Enum, ", "))
+}
+
+func validateBody(c *gin.Context, requestBody *RequestBody) *validationError {
+	mediaType, _, _ := mime.ParseMediaType(c.GetHeader("Content-Type"))
+	content, ok := requestBody.Content[mediaType]
+	if !ok {
+		// Operations that only take JSON parse the body as JSON whatever its
+		// Content-Type, as `curl -d` sends form encoding by default
+		if len(requestBody.Content) != 1 {
+			return &validationError{http.StatusUnsupportedMediaType, gin.H{
+				"error": "Content-Type must be " + strings.Join(mediaTypes(requestBody), " or "),
+			}}
+		}
+		mediaType = mediaTypes(requestBody)[0]
+		content = requestBody.Content[mediaType]
+		if mediaType != "application/json" {
+			return &validationError{http.StatusUnsupportedMediaType, gin.H{"error": "Content-Type must be " + mediaType}}
+		}
+	}
+	if content.Schema == nil {
+		return nil
+	}
+
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxValidatedBody+1))
+	if err != nil {
+		return &validationError{http.StatusBadRequest, gin.H{"error": "Failed to read request body - " + err.Error()}}
+	}
+	if len(body) > maxValidatedBody {
+		return &validationError{http.StatusRequestEntityTooLarge, gin.H{"error": "Request body is too large"}}
+	}
+	// Hand the body on to the handler
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+	if len(bytes.TrimSpace(body)) == 0 {
+		if !requestBody.Required {
+			return nil
+		}
+		return &validationError{http.StatusBadRequest, gin.H{"error": "Missing request body"}}
+	}
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return &validationError{http.StatusBadRequest, gin.H{"error": "Invalid JSON body - " + err.Error()}}
+	}
+	if err := checkJSON(content.Schema, value, "body"); err != nil {
+		return &validationError{http.StatusBadRequest, gin.H{"error": "Invalid request body - " + err.Error()}}
+	}
+	return nil
+}
+
+func mediaTypes(requestBody *RequestBody) []string {
+	types := make([]string, 0, len(requestBody.Content))
+	for mediaType := range requestBody.Content {
+		types = append(types, mediaType)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// checkJSON checks a decoded JSON value against schema; path names the
+// value in errors, e.g. body.Sex.
+func checkJSON(schema *Schema, value interface{}, path string) error {
+	switch schema.Type {
+	case "object":
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%s must be an object", path)
+		}
+		for _, name := range schema.Required {
+			if field, ok := object[name]; !ok || field == nil {
+				return fmt.Errorf("%s.%s is required", path, name)
+			}
+		}
+		for name, field := range object {
+			property, ok := schema.Properties[name]
+			if !ok || field == nil {
+				continue
+			}
+			if err := checkJSON(property, field, path+"."+name); err != nil {
+				return err
+			}
+		}
+	case "array":
+		items, ok := value.([]interface{})
+		if !ok {
+			return fmt.Errorf("%s must be an array", path)
+		}
+		if schema.Items != nil {
+			for i, item := range items {
+				if err := checkJSON(schema.Items, item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+					return err
+				}
+			}
+		}
+	case "string":
+		s, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("%s must be a string", path)
+		}
+		if err := checkEnum(schema, s); err != nil {
+			return fmt.Errorf("%s: %v", path, err)
+		}
+	case "integer", "number":
+		n, ok := value.(json.Number)
+		if schema.Type == "integer" {
+			if _, err := n.Int64(); !ok || err != nil {
+				return fmt.Errorf("%s must be an integer", path)
+			}
+		}
+		if !ok {
+			return fmt.Errorf("%s must be a number", path)
+		}
+		f, err := n.Float64()
+		if err != nil {
+			return fmt.Errorf("%s must be a number", path)
+		}
+		if err := checkRange(schema, f); err != nil {
+			return fmt.Errorf("%s: %v", path, err)
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return fmt.Errorf("%s must be a boolean", path)
+		}
+	}
+	return nil
+}
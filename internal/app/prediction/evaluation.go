@@ -0,0 +1,209 @@
+// internal/app/prediction/evaluation.go
+package prediction
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+
+	"github.com/shindesatish/titanic-service/pkg/model"
+)
+
+// calibrationBuckets is the number of equal-width probability buckets in
+// an Evaluation.
+const calibrationBuckets = 10
+
+// ConfusionMatrix counts predictions at the 0.5 threshold against the
+// actual outcome, survival being the positive class.
+type ConfusionMatrix struct {
+	TruePositives  int `json:"true_positives"`
+	FalsePositives int `json:"false_positives"`
+	TrueNegatives  int `json:"true_negatives"`
+	FalseNegatives int `json:"false_negatives"`
+}
+
+// CalibrationBucket compares the predicted and observed survival rate of
+// the passengers whose predicted probability falls in [Lower, Upper).
+type CalibrationBucket struct {
+	Lower         float64 `json:"lower"`
+	Upper         float64 `json:"upper"`
+	Count         int     `json:"count"`
+	MeanPredicted float64 `json:"mean_predicted"`
+	ObservedRate  float64 `json:"observed_rate"`
+}
+
+// FoldResult is the accuracy of the model trained without one fold, on
+// that fold.
+type FoldResult struct {
+	Fold     int     `json:"fold"`
+	Rows     int     `json:"rows"`
+	Accuracy float64 `json:"accuracy"`
+}
+
+// Evaluation is the result of k-fold cross-validation. Every passenger is
+// scored exactly once, by the model trained on the other folds, and the
+// metrics are computed over those out-of-fold predictions.
+type Evaluation struct {
+	Model       string              `json:"model"`
+	Folds       int                 `json:"folds"`
+	Seed        int64               `json:"seed"`
+	Rows        int                 `json:"rows"`
+	Options     TrainOptions        `json:"options"`
+	Accuracy    float64             `json:"accuracy"`
+	Precision   float64             `json:"precision"`
+	Recall      float64             `json:"recall"`
+	F1          float64             `json:"f1"`
+	ROCAUC      float64             `json:"roc_auc"`
+	Confusion   ConfusionMatrix     `json:"confusion_matrix"`
+	Calibration []CalibrationBucket `json:"calibration"`
+	FoldResults []FoldResult        `json:"fold_results"`
+}
+
+// CrossValidate trains and scores opts over folds folds of passengers. The
+// folds are drawn from a shuffle seeded with seed, so the same inputs always
+// give the same Evaluation.
+func CrossValidate(passengers []model.Passenger, opts TrainOptions, folds int, seed int64) (*Evaluation, error) {
+	if folds < 2 {
+		return nil, fmt.Errorf("need at least 2 folds, got %d", folds)
+	}
+	if len(passengers) < folds {
+		return nil, fmt.Errorf("cannot split %d passengers into %d folds", len(passengers), folds)
+	}
+
+	order := rand.New(rand.NewSource(seed)).Perm(len(passengers))
+	probabilities := make([]float64, len(passengers))
+	evaluation := &Evaluation{Folds: folds, Seed: seed, Rows: len(passengers), Options: opts}
+
+	for fold := 0; fold < folds; fold++ {
+		var train, test []model.Passenger
+		var testIndexes []int
+		for position, i := range order {
+			if position%folds == fold {
+				test = append(test, passengers[i])
+				testIndexes = append(testIndexes, i)
+			} else {
+				train = append(train, passengers[i])
+			}
+		}
+
+		m, err := Train(train, opts)
+		if err != nil {
+			return nil, fmt.Errorf("fold %d: %v", fold+1, err)
+		}
+		correct := 0
+		for j := range test {
+			probability := m.Predict(&test[j])
+			probabilities[testIndexes[j]] = probability
+			if predicted(probability) == test[j].Survived {
+				correct++
+			}
+		}
+		evaluation.FoldResults = append(evaluation.FoldResults, FoldResult{
+			Fold:     fold + 1,
+			Rows:     len(test),
+			Accuracy: float64(correct) / float64(len(test)),
+		})
+	}
+
+	evaluation.score(passengers, probabilities)
+	return evaluation, nil
+}
+
+// score fills in the metrics from the out-of-fold probabilities.
+func (e *Evaluation) score(passengers []model.Passenger, probabilities []float64) {
+	c := &e.Confusion
+	for i, p := range passengers {
+		switch {
+		case predicted(probabilities[i]) == 1 && p.Survived == 1:
+			c.TruePositives++
+		case predicted(probabilities[i]) == 1:
+			c.FalsePositives++
+		case p.Survived == 1:
+			c.FalseNegatives++
+		default:
+			c.TrueNegatives++
+		}
+	}
+
+	e.Accuracy = ratio(c.TruePositives+c.TrueNegatives, len(passengers))
+	e.Precision = ratio(c.TruePositives, c.TruePositives+c.FalsePositives)
+	e.Recall = ratio(c.TruePositives, c.TruePositives+c.FalseNegatives)
+	if e.Precision+e.Recall > 0 {
+		e.F1 = 2 * e.Precision * e.Recall / (e.Precision + e.Recall)
+	}
+	e.ROCAUC = rocAUC(passengers, probabilities)
+	e.Calibration = calibrate(passengers, probabilities)
+}
+
+// rocAUC is the probability that a random survivor is scored above a
+// random non-survivor, computed from the rank sum (Mann–Whitney U) with
+// tied scores sharing their average rank.
+func rocAUC(passengers []model.Passenger, probabilities []float64) float64 {
+	indexes := make([]int, len(passengers))
+	for i := range indexes {
+		indexes[i] = i
+	}
+	sort.Slice(indexes, func(a, b int) bool { return probabilities[indexes[a]] < probabilities[indexes[b]] })
+
+	var positives, negatives int
+	var positiveRanks float64
+	for start := 0; start < len(indexes); {
+		end := start
+		for end < len(indexes) && probabilities[indexes[end]] == probabilities[indexes[start]] {
+			end++
+		}
+		rank := float64(start+end+1) / 2
+		for _, i := range indexes[start:end] {
+			if passengers[i].Survived == 1 {
+				positives++
+				positiveRanks += rank
+			} else {
+				negatives++
+			}
+		}
+		start = end
+	}
+	if positives == 0 || negatives == 0 {
+		return 0
+	}
+	return (positiveRanks - float64(positives*(positives+1))/2) / float64(positives*negatives)
+}
+
+func calibrate(passengers []model.Passenger, probabilities []float64) []CalibrationBucket {
+	buckets := make([]CalibrationBucket, calibrationBuckets)
+	survivors := make([]int, calibrationBuckets)
+	for i := range buckets {
+		buckets[i].Lower = float64(i) / calibrationBuckets
+		buckets[i].Upper = float64(i+1) / calibrationBuckets
+	}
+	for i, probability := range probabilities {
+		b := int(probability * calibrationBuckets)
+		if b >= calibrationBuckets {
+			b = calibrationBuckets - 1
+		}
+		buckets[b].Count++
+		buckets[b].MeanPredicted += probability
+		survivors[b] += passengers[i].Survived
+	}
+	for i := range buckets {
+		if buckets[i].Count > 0 {
+			buckets[i].MeanPredicted /= float64(buckets[i].Count)
+			buckets[i].ObservedRate = ratio(survivors[i], buckets[i].Count)
+		}
+	}
+	return buckets
+}
+
+func predicted(probability float64) int {
+	if probability >= 0.5 {
+		return 1
+	}
+	return 0
+}
+
+func ratio(a, b int) float64 {
+	if b == 0 {
+		return 0
+	}
+	return float64(a) / float64(b)
+}
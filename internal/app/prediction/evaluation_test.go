@@ -0,0 +1,67 @@
+package prediction
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/shindesatish/titanic-service/pkg/model"
+)
+
+// syntheticPassengers returns n passengers whose survival depends on Sex
+// and Pclass, with some noise so no model scores perfectly.
+func syntheticPassengers(n int) []model.Passenger {
+	passengers := make([]model.Passenger, n)
+	for i := range passengers {
+		p := &passengers[i]
+		p.PassengerID = i + 1
+		p.Pclass = i%3 + 1
+		p.Sex = "male"
+		if i%2 == 0 {
+			p.Sex = "female"
+		}
+		if i%7 != 0 {
+			p.Age = model.NewNullFloat64(float64(i%60 + 1))
+		}
+		p.Fare = float64(100 / p.Pclass)
+		p.Embarked = []string{"S", "C", "Q"}[i%3]
+		if (p.Sex == "female") != (i%11 == 0) {
+			p.Survived = 1
+		}
+	}
+	return passengers
+}
+
+func TestCrossValidateDeterministic(t *testing.T) {
+	passengers := syntheticPassengers(120)
+	opts := TrainOptions{LearningRate: 0.1, Epochs: 200, L2: 0.01}
+
+	first, err := CrossValidate(passengers, opts, 5, 42)
+	if err != nil {
+		t.Fatalf("CrossValidate: %v", err)
+	}
+	second, err := CrossValidate(passengers, opts, 5, 42)
+	if err != nil {
+		t.Fatalf("CrossValidate: %v", err)
+	}
+	if !reflect.DeepEqual(first, second) {
+		t.Errorf("same seed gave different evaluations:\n%+v\n%+v", first, second)
+	}
+
+	rows := 0
+	for _, fold := range first.FoldResults {
+		rows += fold.Rows
+	}
+	if rows != len(passengers) || len(first.FoldResults) != 5 {
+		t.Errorf("got %d folds scoring %d rows, want 5 folds scoring %d", len(first.FoldResults), rows, len(passengers))
+	}
+}
+
+func TestCrossValidateFolds(t *testing.T) {
+	passengers := syntheticPassengers(3)
+	opts := TrainOptions{LearningRate: 0.1, Epochs: 10}
+	for _, folds := range []int{1, 4} {
+		if _, err := CrossValidate(passengers, opts, folds, 1); err == nil {
+			t.Errorf("CrossValidate with %d folds of %d passengers: got no error", folds, len(passengers))
+		}
+	}
+}
@@ -0,0 +1,112 @@
+// internal/app/prediction/features.go
+package prediction
+
+import (
+	"math"
+	"sort"
+
+	"github.com/shindesatish/titanic-service/pkg/model"
+)
+
+// FeatureNames lists the encoded features in the order of Model.Weights.
+var FeatureNames = []string{
+	"sex_female",
+	"pclass_2",
+	"pclass_3",
+	"age",
+	"age_missing",
+	"log_fare",
+	"embarked_c",
+	"embarked_q",
+	"sibsp",
+	"parch",
+}
+
+// Encoding holds what the feature encoder learned from the training data:
+// the value used for a missing Age and the scale of the continuous
+// features, which are standardized to zero mean and unit variance.
+type Encoding struct {
+	AgeFill    float64 `json:"age_fill"`
+	AgeMean    float64 `json:"age_mean"`
+	AgeStdDev  float64 `json:"age_std_dev"`
+	FareMean   float64 `json:"fare_mean"`
+	FareStdDev float64 `json:"fare_std_dev"`
+}
+
+// FitEncoding learns an Encoding from passengers. Missing ages are filled
+// with the median known age.
+func FitEncoding(passengers []model.Passenger) Encoding {
+	var ages, fares []float64
+	for i := range passengers {
+		if passengers[i].Age.Valid {
+			ages = append(ages, passengers[i].Age.Float64)
+		}
+		fares = append(fares, math.Log1p(passengers[i].Fare))
+	}
+
+	var encoding Encoding
+	if len(ages) > 0 {
+		sort.Float64s(ages)
+		encoding.AgeFill = ages[len(ages)/2]
+	}
+	for i := range passengers {
+		if !passengers[i].Age.Valid {
+			ages = append(ages, encoding.AgeFill)
+		}
+	}
+	encoding.AgeMean, encoding.AgeStdDev = meanStdDev(ages)
+	encoding.FareMean, encoding.FareStdDev = meanStdDev(fares)
+	return encoding
+}
+
+// Encode turns a passenger into the feature vector described by
+// FeatureNames. Pclass 1 and Embarked S (or unknown) are the baselines of
+// their one-hot encodings.
+func (e Encoding) Encode(p *model.Passenger) []float64 {
+	age, ageMissing := p.Age.Float64, 0.0
+	if !p.Age.Valid {
+		age, ageMissing = e.AgeFill, 1
+	}
+	return []float64{
+		indicator(p.Sex == "female"),
+		indicator(p.Pclass == 2),
+		indicator(p.Pclass == 3),
+		standardize(age, e.AgeMean, e.AgeStdDev),
+		ageMissing,
+		standardize(math.Log1p(p.Fare), e.FareMean, e.FareStdDev),
+		indicator(p.Embarked == "C"),
+		indicator(p.Embarked == "Q"),
+		float64(p.SibSp),
+		float64(p.Parch),
+	}
+}
+
+func indicator(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func standardize(value, mean, stdDev float64) float64 {
+	if stdDev == 0 {
+		return value - mean
+	}
+	return (value - mean) / stdDev
+}
+
+func meanStdDev(values []float64) (float64, float64) {
+	if len(values) == 0 {
+		return 0, 0
+	}
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	mean := sum / float64(len(values))
+	var squares float64
+	for _, v := range values {
+		squares += (v - mean) * (v - mean)
+	}
+	return mean, math.Sqrt(squares / float64(len(values)))
+}
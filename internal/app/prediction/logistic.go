@@ -0,0 +1,110 @@
+// internal/app/prediction/logistic.go
+package prediction
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"github.com/shindesatish/titanic-service/pkg/model"
+)
+
+// KindLogisticRegression is the only kind of Model trained so far.
+const KindLogisticRegression = "logistic_regression"
+
+// TrainOptions are the hyperparameters of Train.
+type TrainOptions struct {
+	LearningRate float64 `json:"learning_rate"`
+	Epochs       int     `json:"epochs"`
+	L2           float64 `json:"l2"`
+}
+
+// DefaultTrainOptions converge on the full dataset in well under a second.
+var DefaultTrainOptions = TrainOptions{
+	LearningRate: 0.1,
+	Epochs:       2000,
+	L2:           0.01,
+}
+
+// Model is a trained survival classifier. It is stored as JSON and holds
+// everything needed to score a passenger.
+type Model struct {
+	Name         string       `json:"name"`
+	Kind         string       `json:"kind"`
+	TrainedAt    time.Time    `json:"trained_at"`
+	DataVersion  string       `json:"data_version,omitempty"`
+	TrainingRows int          `json:"training_rows"`
+	Options      TrainOptions `json:"options"`
+	Encoding     Encoding     `json:"encoding"`
+	Features     []string     `json:"features"`
+	Weights      []float64    `json:"weights"`
+	Bias         float64      `json:"bias"`
+}
+
+// Train fits a logistic regression to passengers with full-batch gradient
+// descent and L2 regularization. It is deterministic: the same passengers
+// and options always give the same weights.
+func Train(passengers []model.Passenger, opts TrainOptions) (*Model, error) {
+	if len(passengers) == 0 {
+		return nil, errors.New("no passengers to train on")
+	}
+	if opts.LearningRate <= 0 || opts.Epochs <= 0 || opts.L2 < 0 {
+		return nil, errors.New("learning rate and epochs must be positive and l2 must not be negative")
+	}
+
+	encoding := FitEncoding(passengers)
+	features := make([][]float64, len(passengers))
+	for i := range passengers {
+		features[i] = encoding.Encode(&passengers[i])
+	}
+
+	weights := make([]float64, len(FeatureNames))
+	gradient := make([]float64, len(FeatureNames))
+	var bias float64
+	n := float64(len(passengers))
+	for epoch := 0; epoch < opts.Epochs; epoch++ {
+		for j := range gradient {
+			gradient[j] = opts.L2 * weights[j]
+		}
+		var biasGradient float64
+		for i, x := range features {
+			diff := (sigmoid(dot(weights, x)+bias) - float64(passengers[i].Survived)) / n
+			for j, value := range x {
+				gradient[j] += diff * value
+			}
+			biasGradient += diff
+		}
+		for j := range weights {
+			weights[j] -= opts.LearningRate * gradient[j]
+		}
+		bias -= opts.LearningRate * biasGradient
+	}
+
+	return &Model{
+		Kind:         KindLogisticRegression,
+		TrainedAt:    time.Now().UTC(),
+		TrainingRows: len(passengers),
+		Options:      opts,
+		Encoding:     encoding,
+		Features:     append([]string(nil), FeatureNames...),
+		Weights:      weights,
+		Bias:         bias,
+	}, nil
+}
+
+// Predict returns the probability that the passenger survived.
+func (m *Model) Predict(p *model.Passenger) float64 {
+	return sigmoid(dot(m.Weights, m.Encoding.Encode(p)) + m.Bias)
+}
+
+func sigmoid(z float64) float64 {
+	return 1 / (1 + math.Exp(-z))
+}
+
+func dot(a, b []float64) float64 {
+	var sum float64
+	for i := range a {
+		sum += a[i] * b[i]
+	}
+	return sum
+}
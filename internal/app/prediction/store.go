@@ -0,0 +1,88 @@
+// internal/app/prediction/store.go
+package prediction
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+)
+
+// ErrModelNotFound is returned when no artifact is stored under a name.
+var ErrModelNotFound = errors.New("model not found")
+
+var validName = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+// Store keeps model artifacts as <Dir>/<name>.json.
+type Store struct {
+	Dir string
+}
+
+func NewStore(dir string) *Store {
+	return &Store{Dir: dir}
+}
+
+// ValidName reports whether name can be used for a stored model.
+func ValidName(name string) bool {
+	return validName.MatchString(name)
+}
+
+// Load reads the model stored under name.
+func (s *Store) Load(name string) (*Model, error) {
+	if !ValidName(name) {
+		return nil, fmt.Errorf("invalid model name %q", name)
+	}
+	data, err := os.ReadFile(s.path(name))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrModelNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read model: %v", err)
+	}
+
+	var m Model
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("failed to decode model %s: %v", name, err)
+	}
+	if m.Kind != KindLogisticRegression || len(m.Weights) != len(FeatureNames) {
+		return nil, fmt.Errorf("model %s is not a %s over %d features", name, KindLogisticRegression, len(FeatureNames))
+	}
+	return &m, nil
+}
+
+// Save writes m under m.Name, replacing any previous artifact atomically.
+func (s *Store) Save(m *Model) error {
+	if !ValidName(m.Name) {
+		return fmt.Errorf("invalid model name %q", m.Name)
+	}
+	if err := os.MkdirAll(s.Dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create model directory: %v", err)
+	}
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode model: %v", err)
+	}
+
+	tmp, err := os.CreateTemp(s.Dir, m.Name+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create model file: %v", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write model file: %v", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write model file: %v", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path(m.Name)); err != nil {
+		return fmt.Errorf("failed to replace model file: %v", err)
+	}
+	return nil
+}
+
+func (s *Store) path(name string) string {
+	return filepath.Join(s.Dir, name+".json")
+}
@@ -0,0 +1,59 @@
+// internal/app/query/ast.go
+package query
+
+// Node is a node of a filter expression's syntax tree.
+type Node interface {
+	// Pos is the 1-based character position of the node in the input.
+	Pos() int
+}
+
+// Logical joins two expressions with AND or OR.
+type Logical struct {
+	Op          string
+	Left, Right Node
+}
+
+func (n *Logical) Pos() int { return n.Left.Pos() }
+
+// Not negates an expression.
+type Not struct {
+	X        Node
+	Position int
+}
+
+func (n *Not) Pos() int { return n.Position }
+
+// Operators of a Predicate besides the comparison operators =, !=, <, <=,
+// > and >=.
+const (
+	OpIn        = "IN"
+	OpNotIn     = "NOT IN"
+	OpLike      = "LIKE"
+	OpNotLike   = "NOT LIKE"
+	OpIsNull    = "IS NULL"
+	OpIsNotNull = "IS NOT NULL"
+)
+
+// Predicate tests one passenger attribute: "Age < 12", "Pclass IN (1, 2)",
+// "Name LIKE '%Andersson%'" or "Cabin IS NULL".
+type Predicate struct {
+	// Attribute is the canonical attribute name once the expression has
+	// been checked.
+	Attribute string
+	Op        string
+	Values    []Literal
+	// Numeric is set by the type check for numeric attributes, which are
+	// compared as numbers rather than text.
+	Numeric  bool
+	Position int
+}
+
+func (n *Predicate) Pos() int { return n.Position }
+
+// Literal is a number or a quoted string.
+type Literal struct {
+	Text     string
+	Number   float64
+	IsNumber bool
+	Position int
+}
@@ -0,0 +1,160 @@
+// internal/app/query/lexer.go
+package query
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Error is a syntax or type error in a filter expression. Pos is the
+// 1-based character position in the input where the problem starts.
+type Error struct {
+	Pos int
+	Msg string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s at position %d", e.Msg, e.Pos)
+}
+
+func errorf(pos int, format string, args ...interface{}) *Error {
+	return &Error{Pos: pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenNumber
+	tokenString
+	tokenOperator
+	tokenLParen
+	tokenRParen
+	tokenComma
+	tokenAnd
+	tokenOr
+	tokenNot
+	tokenIn
+	tokenIs
+	tokenNull
+	tokenLike
+)
+
+var keywords = map[string]tokenKind{
+	"AND":  tokenAnd,
+	"OR":   tokenOr,
+	"NOT":  tokenNot,
+	"IN":   tokenIn,
+	"IS":   tokenIs,
+	"NULL": tokenNull,
+	"LIKE": tokenLike,
+}
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+// describe names the token for error messages.
+func (t token) describe() string {
+	switch t.kind {
+	case tokenEOF:
+		return "end of filter"
+	case tokenString:
+		return fmt.Sprintf("string '%s'", t.text)
+	case tokenIdent:
+		return fmt.Sprintf("%q", t.text)
+	}
+	return fmt.Sprintf("%q", strings.ToUpper(t.text))
+}
+
+// lex splits input into tokens, ending with a tokenEOF.
+func lex(input string) ([]token, error) {
+	runes := []rune(input)
+	var tokens []token
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		pos := i + 1
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, token{tokenLParen, "(", pos})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{tokenRParen, ")", pos})
+			i++
+		case r == ',':
+			tokens = append(tokens, token{tokenComma, ",", pos})
+			i++
+		case r == '=':
+			tokens = append(tokens, token{tokenOperator, "=", pos})
+			i++
+		case r == '!' || r == '<' || r == '>':
+			op := string(r)
+			if i+1 < len(runes) && (runes[i+1] == '=' || (r == '<' && runes[i+1] == '>')) {
+				op += string(runes[i+1])
+			}
+			if op == "!" {
+				return nil, errorf(pos, "unexpected '!', did you mean '!='")
+			}
+			if op == "<>" {
+				op = "!="
+			}
+			tokens = append(tokens, token{tokenOperator, op, pos})
+			i += len([]rune(op))
+		case r == '\'' || r == '"':
+			text, end, err := lexString(runes, i)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{tokenString, text, pos})
+			i = end
+		case unicode.IsDigit(r) || r == '.' || (r == '-' && i+1 < len(runes) && (unicode.IsDigit(runes[i+1]) || runes[i+1] == '.')):
+			end := i + 1
+			for end < len(runes) && (unicode.IsDigit(runes[end]) || runes[end] == '.') {
+				end++
+			}
+			tokens = append(tokens, token{tokenNumber, string(runes[i:end]), pos})
+			i = end
+		case unicode.IsLetter(r) || r == '_':
+			end := i + 1
+			for end < len(runes) && (unicode.IsLetter(runes[end]) || unicode.IsDigit(runes[end]) || runes[end] == '_') {
+				end++
+			}
+			word := string(runes[i:end])
+			kind, ok := keywords[strings.ToUpper(word)]
+			if !ok {
+				kind = tokenIdent
+			}
+			tokens = append(tokens, token{kind, word, pos})
+			i = end
+		default:
+			return nil, errorf(pos, "unexpected character %q", r)
+		}
+	}
+	return append(tokens, token{tokenEOF, "", len(runes) + 1}), nil
+}
+
+// lexString reads the quoted string starting at runes[start]. A doubled
+// quote stands for the quote itself, as in SQL.
+func lexString(runes []rune, start int) (string, int, error) {
+	quote := runes[start]
+	var text strings.Builder
+	for i := start + 1; i < len(runes); i++ {
+		if runes[i] != quote {
+			text.WriteRune(runes[i])
+			continue
+		}
+		if i+1 < len(runes) && runes[i+1] == quote {
+			text.WriteRune(quote)
+			i++
+			continue
+		}
+		return text.String(), i + 1, nil
+	}
+	return "", 0, errorf(start+1, "unterminated string")
+}
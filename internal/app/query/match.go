@@ -0,0 +1,130 @@
+// internal/app/query/match.go
+package query
+
+import (
+	"strconv"
+
+	"github.com/shindesatish/titanic-service/pkg/model"
+)
+
+// Match reports whether the passenger satisfies the expression. A missing
+// value (an unknown Age, an empty Cabin or Embarked) satisfies IS NULL and
+// fails every other test, so "NOT Age < 12" includes passengers without an
+// Age.
+func (e *Expr) Match(p *model.Passenger) bool {
+	return match(e.Root, p)
+}
+
+func match(node Node, p *model.Passenger) bool {
+	switch n := node.(type) {
+	case *Logical:
+		if n.Op == "AND" {
+			return match(n.Left, p) && match(n.Right, p)
+		}
+		return match(n.Left, p) || match(n.Right, p)
+	case *Not:
+		return !match(n.X, p)
+	case *Predicate:
+		return matchPredicate(n, p)
+	}
+	return false
+}
+
+func matchPredicate(n *Predicate, p *model.Passenger) bool {
+	value, err := p.Attribute(n.Attribute)
+	if err != nil {
+		return false
+	}
+	switch n.Op {
+	case OpIsNull:
+		return value == ""
+	case OpIsNotNull:
+		return value != ""
+	}
+	if value == "" {
+		return false
+	}
+
+	var number float64
+	if n.Numeric {
+		if number, err = strconv.ParseFloat(value, 64); err != nil {
+			return false
+		}
+	}
+	compare := func(literal Literal) int {
+		switch {
+		case n.Numeric && number < literal.Number, !n.Numeric && value < literal.Text:
+			return -1
+		case n.Numeric && number > literal.Number, !n.Numeric && value > literal.Text:
+			return 1
+		}
+		return 0
+	}
+
+	switch n.Op {
+	case "=":
+		return compare(n.Values[0]) == 0
+	case "!=":
+		return compare(n.Values[0]) != 0
+	case "<":
+		return compare(n.Values[0]) < 0
+	case "<=":
+		return compare(n.Values[0]) <= 0
+	case ">":
+		return compare(n.Values[0]) > 0
+	case ">=":
+		return compare(n.Values[0]) >= 0
+	case OpIn, OpNotIn:
+		found := false
+		for _, literal := range n.Values {
+			if compare(literal) == 0 {
+				found = true
+				break
+			}
+		}
+		return found == (n.Op == OpIn)
+	case OpLike, OpNotLike:
+		return like([]rune(n.Values[0].Text), []rune(value)) == (n.Op == OpLike)
+	}
+	return false
+}
+
+// like matches value against a LIKE pattern the way SQLite does: % is any
+// run of characters, _ any single character, and ASCII letters match
+// either case.
+func like(pattern, value []rune) bool {
+	for len(pattern) > 0 {
+		switch pattern[0] {
+		case '%':
+			for len(pattern) > 0 && pattern[0] == '%' {
+				pattern = pattern[1:]
+			}
+			if len(pattern) == 0 {
+				return true
+			}
+			for i := range value {
+				if like(pattern, value[i:]) {
+					return true
+				}
+			}
+			return false
+		case '_':
+			if len(value) == 0 {
+				return false
+			}
+		default:
+			if len(value) == 0 || foldASCII(pattern[0]) != foldASCII(value[0]) {
+				return false
+			}
+		}
+		pattern, value = pattern[1:], value[1:]
+	}
+	return len(value) == 0
+}
+
+func foldASCII(r rune) rune {
+	if r >= 'A' && r <= 'Z' {
+		return r + 'a' - 'A'
+	}
+	return r
+}
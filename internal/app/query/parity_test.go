@@ -0,0 +1,158 @@
+package query_test
+
+import (
+	"database/sql"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/shindesatish/titanic-service/internal/app/query"
+	"github.com/shindesatish/titanic-service/internal/app/repository"
+	"github.com/shindesatish/titanic-service/pkg/model"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// parityFilters are expressions whose attributes are all columns of the
+// titanic table, so the SQLite backend compiles them to SQL.
+var parityFilters = []string{
+	"Age < 12",
+	"NOT Age < 12",
+	"Age >= 18 AND Age <= 30",
+	"Age = 0.42 OR Age = 80",
+	"Age IS NULL",
+	"Age IS NOT NULL AND Survived = 1",
+	"NOT (Age IS NULL OR Cabin IS NULL)",
+	"Cabin IS NULL",
+	"Cabin LIKE 'C%'",
+	"Cabin NOT LIKE '%6%'",
+	"Embarked IS NULL",
+	"Embarked NOT IN ('S', 'Q')",
+	"Embarked != 'S'",
+	"Pclass IN (1, 2) AND Sex = 'female'",
+	"Pclass = 1 AND Sex = 'female' AND Embarked = 'C'",
+	"Pclass <> 3 OR Fare > 50",
+	"Fare = 0",
+	"Fare > 7.25 AND Fare < 7.9",
+	"Fare >= 512.3292",
+	"SibSp = 1",
+	"SibSp > 2 OR Parch > 2",
+	"Name LIKE '%andersson%'",
+	"Name LIKE '%(%)%' AND NOT Name LIKE '%Mrs.%'",
+	"Name LIKE 'O''%'",
+	"Name LIKE '_____, Mr. %'",
+	"Ticket < '2'",
+	"Ticket >= 'PC' AND Ticket < 'PD'",
+	"Ticket IN ('347082', 'CA. 2343', '1601')",
+	"Sex = 'Female'",
+	"PassengerId NOT IN (1, 2, 3) AND PassengerId <= 10",
+}
+
+// openBackends opens both backends on temporary copies of the dataset.
+func openBackends(t *testing.T) (csvRepo, sqliteRepo repository.Repository, db *sql.DB) {
+	t.Helper()
+	dir := t.TempDir()
+	for _, name := range []string{"titanic.csv", "titanic.db"} {
+		data, err := os.ReadFile(filepath.Join("..", "..", "..", "datastore", name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	csvRepo, _, err := repository.Open(repository.Options{CSVPath: filepath.Join(dir, "titanic.csv")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err = sql.Open("sqlite3", filepath.Join(dir, "titanic.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return csvRepo, repository.NewSQLiteRepository(db), db
+}
+
+// column maps an attribute to its column of the titanic table.
+func column(attribute string) string {
+	for _, name := range model.CSVHeader {
+		if strings.EqualFold(name, attribute) {
+			return name
+		}
+	}
+	return ""
+}
+
+// matchingIDs returns the PassengerIds of the passengers of repo that
+// match expr, in ascending order.
+func matchingIDs(t *testing.T, repo repository.Repository, expr *query.Expr) []int {
+	t.Helper()
+	ids := []int{}
+	err := repo.ForEachPassenger(func(p *model.Passenger) error {
+		if expr.Match(p) {
+			ids = append(ids, p.PassengerID)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ids
+}
+
+func TestMatchSQLParity(t *testing.T) {
+	csvRepo, sqliteRepo, db := openBackends(t)
+	for _, filter := range parityFilters {
+		expr, err := query.Parse(filter)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", filter, err)
+		}
+
+		csvIDs := matchingIDs(t, csvRepo, expr)
+		if sqliteIDs := matchingIDs(t, sqliteRepo, expr); !reflect.DeepEqual(sqliteIDs, csvIDs) {
+			t.Errorf("%s: Match selects %d passengers on SQLite and %d on CSV", filter, len(sqliteIDs), len(csvIDs))
+		}
+
+		where, args, ok := expr.SQL(column)
+		if !ok {
+			t.Fatalf("%s: not compiled to SQL", filter)
+		}
+		rows, err := db.Query("SELECT PassengerId FROM titanic WHERE "+where+" ORDER BY CAST(PassengerId AS INTEGER)", args...)
+		if err != nil {
+			t.Fatalf("%s: %v", filter, err)
+		}
+		sqlIDs := []int{}
+		for rows.Next() {
+			var id int
+			if err := rows.Scan(&id); err != nil {
+				t.Fatal(err)
+			}
+			sqlIDs = append(sqlIDs, id)
+		}
+		rows.Close()
+		if !reflect.DeepEqual(sqlIDs, csvIDs) {
+			t.Errorf("%s: SQL selects %d passengers, Match %d", filter, len(sqlIDs), len(csvIDs))
+		}
+		// Only the case-sensitive comparison is expected to select nobody
+		if len(csvIDs) == 0 && filter != "Sex = 'Female'" {
+			t.Errorf("%s: matches no passengers, so it does not test much", filter)
+		}
+
+		// The backends' own filtered passes agree too
+		for name, repo := range map[string]repository.Repository{"csv": csvRepo, "sqlite": sqliteRepo} {
+			ids := []int{}
+			err := repo.ForEachMatchingPassenger(expr, func(p *model.Passenger) error {
+				ids = append(ids, p.PassengerID)
+				return nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(ids, csvIDs) {
+				t.Errorf("%s: ForEachMatchingPassenger on %s selects %d passengers, Match %d", filter, name, len(ids), len(csvIDs))
+			}
+		}
+	}
+}
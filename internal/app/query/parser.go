@@ -0,0 +1,277 @@
+// internal/app/query/parser.go
+
+// Package query implements the filter expression language accepted by
+// ?filter=, e.g.
+//
+//	Age < 12 OR (Sex = 'female' AND Pclass IN (1, 2))
+//
+// The grammar, with case-insensitive keywords, is
+//
+//	expression = term { "OR" term } .
+//	term       = factor { "AND" factor } .
+//	factor     = "NOT" factor | "(" expression ")" | predicate .
+//	predicate  = attribute ( operator literal
+//	           | [ "NOT" ] "IN" "(" literal { "," literal } ")"
+//	           | [ "NOT" ] "LIKE" string
+//	           | "IS" [ "NOT" ] "NULL" ) .
+//	operator   = "=" | "!=" | "<>" | "<" | "<=" | ">" | ">=" .
+//	literal    = number | string .
+//
+// Attributes are the passenger attributes of model.AttributeNames. Strings
+// are quoted with ' or " and a doubled quote escapes itself. LIKE takes the
+// SQL wildcards % and _ and ignores ASCII case.
+package query
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/shindesatish/titanic-service/pkg/model"
+)
+
+// Expr is a parsed and type-checked filter expression.
+type Expr struct {
+	Root   Node
+	source string
+}
+
+// Parse parses input and checks it against the passenger attributes. The
+// returned error is an *Error carrying the position of the problem.
+func Parse(input string) (*Expr, error) {
+	tokens, err := lex(input)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	if p.peek().kind == tokenEOF {
+		return nil, errorf(p.peek().pos, "empty filter")
+	}
+	root, err := p.expression()
+	if err != nil {
+		return nil, err
+	}
+	if next := p.peek(); next.kind != tokenEOF {
+		return nil, errorf(next.pos, "unexpected %s, expected AND, OR or end of filter", next.describe())
+	}
+	if err := check(root); err != nil {
+		return nil, err
+	}
+	return &Expr{Root: root, source: strings.TrimSpace(input)}, nil
+}
+
+// String returns the expression as it was written.
+func (e *Expr) String() string {
+	return e.source
+}
+
+type parser struct {
+	tokens []token
+	next   int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.next]
+}
+
+func (p *parser) advance() token {
+	t := p.tokens[p.next]
+	if t.kind != tokenEOF {
+		p.next++
+	}
+	return t
+}
+
+func (p *parser) expect(kind tokenKind, what string) (token, error) {
+	t := p.advance()
+	if t.kind != kind {
+		return t, errorf(t.pos, "expected %s, got %s", what, t.describe())
+	}
+	return t, nil
+}
+
+func (p *parser) expression() (Node, error) {
+	left, err := p.term()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokenOr {
+		p.advance()
+		right, err := p.term()
+		if err != nil {
+			return nil, err
+		}
+		left = &Logical{Op: "OR", Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) term() (Node, error) {
+	left, err := p.factor()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokenAnd {
+		p.advance()
+		right, err := p.factor()
+		if err != nil {
+			return nil, err
+		}
+		left = &Logical{Op: "AND", Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) factor() (Node, error) {
+	switch t := p.peek(); t.kind {
+	case tokenNot:
+		p.advance()
+		x, err := p.factor()
+		if err != nil {
+			return nil, err
+		}
+		return &Not{X: x, Position: t.pos}, nil
+	case tokenLParen:
+		p.advance()
+		x, err := p.expression()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := p.expect(tokenRParen, "')'"); err != nil {
+			return nil, err
+		}
+		return x, nil
+	}
+	return p.predicate()
+}
+
+func (p *parser) predicate() (Node, error) {
+	attribute, err := p.expect(tokenIdent, "an attribute name")
+	if err != nil {
+		return nil, err
+	}
+	predicate := &Predicate{Attribute: attribute.text, Position: attribute.pos}
+
+	t := p.advance()
+	switch t.kind {
+	case tokenOperator:
+		predicate.Op = t.text
+		value, err := p.literal()
+		if err != nil {
+			return nil, err
+		}
+		predicate.Values = []Literal{value}
+		return predicate, nil
+	case tokenIs:
+		predicate.Op = OpIsNull
+		if p.peek().kind == tokenNot {
+			p.advance()
+			predicate.Op = OpIsNotNull
+		}
+		if _, err := p.expect(tokenNull, "NULL"); err != nil {
+			return nil, err
+		}
+		return predicate, nil
+	case tokenNot:
+		t = p.advance()
+		if t.kind != tokenIn && t.kind != tokenLike {
+			return nil, errorf(t.pos, "expected IN or LIKE after NOT, got %s", t.describe())
+		}
+		predicate.Op = "NOT "
+	}
+
+	switch t.kind {
+	case tokenIn:
+		predicate.Op += OpIn
+		if _, err := p.expect(tokenLParen, "'(' to start the IN list"); err != nil {
+			return nil, err
+		}
+		for {
+			value, err := p.literal()
+			if err != nil {
+				return nil, err
+			}
+			predicate.Values = append(predicate.Values, value)
+			if p.peek().kind != tokenComma {
+				break
+			}
+			p.advance()
+		}
+		if _, err := p.expect(tokenRParen, "',' or ')' in the IN list"); err != nil {
+			return nil, err
+		}
+		return predicate, nil
+	case tokenLike:
+		predicate.Op += OpLike
+		pattern, err := p.expect(tokenString, "a quoted LIKE pattern")
+		if err != nil {
+			return nil, err
+		}
+		predicate.Values = []Literal{{Text: pattern.text, Position: pattern.pos}}
+		return predicate, nil
+	}
+	return nil, errorf(t.pos, "expected an operator after %s, got %s", attribute.text, t.describe())
+}
+
+func (p *parser) literal() (Literal, error) {
+	t := p.advance()
+	switch t.kind {
+	case tokenString:
+		return Literal{Text: t.text, Position: t.pos}, nil
+	case tokenNumber:
+		number, err := strconv.ParseFloat(t.text, 64)
+		if err != nil {
+			return Literal{}, errorf(t.pos, "invalid number %s", t.text)
+		}
+		return Literal{Text: t.text, Number: number, IsNumber: true, Position: t.pos}, nil
+	case tokenIdent:
+		return Literal{}, errorf(t.pos, "expected a value, got %s (quote text values: '%s')", t.describe(), t.text)
+	}
+	return Literal{}, errorf(t.pos, "expected a value, got %s", t.describe())
+}
+
+// check resolves attribute names and makes sure each value has the type of
+// its attribute.
+func check(node Node) error {
+	switch n := node.(type) {
+	case *Logical:
+		if err := check(n.Left); err != nil {
+			return err
+		}
+		return check(n.Right)
+	case *Not:
+		return check(n.X)
+	case *Predicate:
+		return checkPredicate(n)
+	}
+	return nil
+}
+
+func checkPredicate(n *Predicate) error {
+	attribute := ""
+	for _, name := range model.AttributeNames() {
+		if strings.EqualFold(name, n.Attribute) {
+			attribute = name
+			break
+		}
+	}
+	if attribute == "" {
+		return errorf(n.Position, "unknown attribute %s", n.Attribute)
+	}
+	n.Attribute = attribute
+	n.Numeric = model.IsNumericAttribute(attribute)
+
+	if n.Numeric && (n.Op == OpLike || n.Op == OpNotLike) {
+		return errorf(n.Position, "%s is numeric and cannot be matched with LIKE", attribute)
+	}
+	for _, value := range n.Values {
+		switch {
+		case n.Numeric && !value.IsNumber:
+			return errorf(value.Position, "%s is numeric, got string '%s'", attribute, value.Text)
+		case !n.Numeric && value.IsNumber:
+			return errorf(value.Position, "%s is text, quote the value: '%s'", attribute, value.Text)
+		case !n.Numeric && value.Text == "":
+			return errorf(value.Position, "an empty string never matches, use %s IS NULL", attribute)
+		}
+	}
+	return nil
+}
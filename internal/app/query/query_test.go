@@ -0,0 +1,261 @@
+package query
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/shindesatish/titanic-service/pkg/model"
+)
+
+// format writes a syntax tree with every AND and OR parenthesized, to show
+// how the parser grouped it.
+func format(node Node) string {
+	switch n := node.(type) {
+	case *Logical:
+		return fmt.Sprintf("(%s %s %s)", format(n.Left), n.Op, format(n.Right))
+	case *Not:
+		return "NOT " + format(n.X)
+	case *Predicate:
+		if len(n.Values) == 0 {
+			return n.Attribute + " " + n.Op
+		}
+		values := make([]string, len(n.Values))
+		for i, value := range n.Values {
+			values[i] = value.Text
+		}
+		return fmt.Sprintf("%s %s %s", n.Attribute, n.Op, strings.Join(values, ","))
+	}
+	return "?"
+}
+
+func TestParsePrecedence(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"Age < 12", "Age < 12"},
+		{"Age < 12 OR Sex = 'female' AND Pclass = 1", "(Age < 12 OR (Sex = female AND Pclass = 1))"},
+		{"Age < 12 AND Sex = 'female' OR Pclass = 1", "((Age < 12 AND Sex = female) OR Pclass = 1)"},
+		{"(Age < 12 OR Sex = 'female') AND Pclass = 1", "((Age < 12 OR Sex = female) AND Pclass = 1)"},
+		{"Age < 1 OR Age < 2 OR Age < 3", "((Age < 1 OR Age < 2) OR Age < 3)"},
+		{"Age < 1 AND Age < 2 AND Age < 3", "((Age < 1 AND Age < 2) AND Age < 3)"},
+		{"NOT Age < 12 AND Pclass = 1", "(NOT Age < 12 AND Pclass = 1)"},
+		{"NOT (Age < 12 AND Pclass = 1)", "NOT (Age < 12 AND Pclass = 1)"},
+		{"NOT NOT Survived = 1", "NOT NOT Survived = 1"},
+		{"((Survived = 1))", "Survived = 1"},
+		{"age <> 3 and SEX = 'male' or pclass in (1,2)", "((Age != 3 AND Sex = male) OR Pclass IN 1,2)"},
+		{"Pclass NOT IN (1, 2)", "Pclass NOT IN 1,2"},
+		{"Name not like '%Mr.%'", "Name NOT LIKE %Mr.%"},
+		{"Cabin IS NULL OR Age is not null", "(Cabin IS NULL OR Age IS NOT NULL)"},
+		{"Age >= -1.5", "Age >= -1.5"},
+		{"Name = 'O''Brien' OR Name = \"Say \"\"hi\"\"\"", "(Name = O'Brien OR Name = Say \"hi\")"},
+		{"Title = 'Master'", "Title = Master"},
+	}
+	for _, tt := range tests {
+		expr, err := Parse(tt.input)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.input, err)
+			continue
+		}
+		if got := format(expr.Root); got != tt.want {
+			t.Errorf("Parse(%q) = %s, want %s", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		input string
+		pos   int
+		msg   string
+	}{
+		// Lexer
+		{"Age ! 3", 5, "unexpected '!', did you mean '!='"},
+		{"Age # 3", 5, "unexpected character '#'"},
+		{"Name = 'abc", 8, "unterminated string"},
+		{"Name = 'é' AND Age ~ 3", 20, "unexpected character '~'"},
+		// Parser
+		{"", 1, "empty filter"},
+		{"   ", 4, "empty filter"},
+		{"Age <", 6, "expected a value, got end of filter"},
+		{"Age 12", 5, `expected an operator after Age, got "12"`},
+		{"Age < 12 Pclass = 1", 10, `unexpected "Pclass", expected AND, OR or end of filter`},
+		{"(Age < 12", 10, "expected ')', got end of filter"},
+		{"Age < 12)", 9, `unexpected ")", expected AND, OR or end of filter`},
+		{"Age NOT 12", 9, `expected IN or LIKE after NOT, got "12"`},
+		{"Pclass IN 1", 11, `expected '(' to start the IN list, got "1"`},
+		{"Pclass IN (1, 2", 16, "expected ',' or ')' in the IN list, got end of filter"},
+		{"Pclass IN ()", 12, `expected a value, got ")"`},
+		{"Name LIKE 3", 11, `expected a quoted LIKE pattern, got "3"`},
+		{"Age IS 3", 8, `expected NULL, got "3"`},
+		{"Sex = female", 7, `expected a value, got "female" (quote text values: 'female')`},
+		{"Age = 1.2.3", 7, "invalid number 1.2.3"},
+		{"Age < 12 AND", 13, "expected an attribute name, got end of filter"},
+		{"= 3", 1, `expected an attribute name, got "="`},
+		// Type check
+		{"Nom = 'x'", 1, "unknown attribute Nom"},
+		{"Age < 3 OR Nom = 'x'", 12, "unknown attribute Nom"},
+		{"Age = 'x'", 7, "Age is numeric, got string 'x'"},
+		{"Pclass IN (1, '2')", 15, "Pclass is numeric, got string '2'"},
+		{"Sex = 1", 7, "Sex is text, quote the value: '1'"},
+		{"Fare LIKE '1%'", 1, "Fare is numeric and cannot be matched with LIKE"},
+		{"Cabin = ''", 9, "an empty string never matches, use Cabin IS NULL"},
+	}
+	for _, tt := range tests {
+		_, err := Parse(tt.input)
+		var queryErr *Error
+		if !errors.As(err, &queryErr) {
+			t.Errorf("Parse(%q): got %v, want an *Error", tt.input, err)
+			continue
+		}
+		if queryErr.Pos != tt.pos || queryErr.Msg != tt.msg {
+			t.Errorf("Parse(%q): got %q at %d, want %q at %d", tt.input, queryErr.Msg, queryErr.Pos, tt.msg, tt.pos)
+		}
+	}
+
+	_, err := Parse("Age <")
+	if err.Error() != "expected a value, got end of filter at position 6" {
+		t.Errorf("got error text %q", err)
+	}
+}
+
+func TestParseResolvesAttributes(t *testing.T) {
+	expr, err := Parse("  pclass = 1 AND name LIKE 'a%'  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	left := expr.Root.(*Logical).Left.(*Predicate)
+	right := expr.Root.(*Logical).Right.(*Predicate)
+	if left.Attribute != "Pclass" || !left.Numeric || right.Attribute != "Name" || right.Numeric {
+		t.Errorf("got %+v and %+v", left, right)
+	}
+	if expr.String() != "pclass = 1 AND name LIKE 'a%'" {
+		t.Errorf("String() = %q", expr.String())
+	}
+}
+
+func TestMatch(t *testing.T) {
+	braund := &model.Passenger{PassengerID: 1, Survived: 0, Pclass: 3, Name: "Braund, Mr. Owen Harris", Sex: "male", Age: model.NewNullFloat64(22), SibSp: 1, Ticket: "A/5 21171", Fare: 7.25, Embarked: "S"}
+	moran := &model.Passenger{PassengerID: 6, Survived: 0, Pclass: 3, Name: "Moran, Mr. James", Sex: "male", Ticket: "330877", Fare: 8.4583, Embarked: "Q"}
+	obrien := &model.Passenger{PassengerID: 7, Survived: 1, Pclass: 1, Name: "O'Brien, Mrs. Thomas", Sex: "female", Age: model.NewNullFloat64(0), Ticket: "PC 17599", Fare: 71.2833, Cabin: "C85", Embarked: "C"}
+
+	tests := []struct {
+		filter string
+		want   []*model.Passenger
+	}{
+		{"Age < 30", []*model.Passenger{braund, obrien}},
+		{"NOT Age < 30", []*model.Passenger{moran}},
+		{"Age >= 0", []*model.Passenger{braund, obrien}},
+		{"Age = 0", []*model.Passenger{obrien}},
+		{"Age IS NULL", []*model.Passenger{moran}},
+		{"Age IS NOT NULL", []*model.Passenger{braund, obrien}},
+		{"Age != 22", []*model.Passenger{obrien}},
+		{"Cabin IS NULL", []*model.Passenger{braund, moran}},
+		{"Cabin != 'C85'", nil},
+		{"Fare > 8", []*model.Passenger{moran, obrien}},
+		{"Fare = 8.4583", []*model.Passenger{moran}},
+		{"Pclass IN (1, 2)", []*model.Passenger{obrien}},
+		{"Pclass NOT IN (1, 2)", []*model.Passenger{braund, moran}},
+		{"Embarked IN ('S', 'Q')", []*model.Passenger{braund, moran}},
+		{"Ticket < 'B'", []*model.Passenger{braund, moran}},
+		{"Name LIKE '%mr.%'", []*model.Passenger{braund, moran}},
+		{"Name LIKE 'm_ran%'", []*model.Passenger{moran}},
+		{"Name NOT LIKE '%Mr.%'", []*model.Passenger{obrien}},
+		{"Name LIKE 'O''Brien%'", []*model.Passenger{obrien}},
+		{"Name LIKE 'Braund'", nil},
+		{"Title = 'Mrs' OR Surname = 'Braund'", []*model.Passenger{braund, obrien}},
+		{"Sex = 'male' AND (Embarked = 'Q' OR Age > 20)", []*model.Passenger{braund, moran}},
+		{"NOT (Sex = 'male' AND Embarked = 'Q')", []*model.Passenger{braund, obrien}},
+	}
+	passengers := []*model.Passenger{braund, moran, obrien}
+	for _, tt := range tests {
+		expr, err := Parse(tt.filter)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.filter, err)
+			continue
+		}
+		var got []*model.Passenger
+		for _, p := range passengers {
+			if expr.Match(p) {
+				got = append(got, p)
+			}
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s matched %v, want %v", tt.filter, ids(got), ids(tt.want))
+		}
+	}
+}
+
+func ids(passengers []*model.Passenger) []int {
+	var ids []int
+	for _, p := range passengers {
+		ids = append(ids, p.PassengerID)
+	}
+	return ids
+}
+
+func TestLike(t *testing.T) {
+	tests := []struct {
+		pattern, value string
+		want           bool
+	}{
+		{"", "", true},
+		{"%", "", true},
+		{"_", "", false},
+		{"a%", "ABC", true},
+		{"%b%", "abc", true},
+		{"a_c", "abc", true},
+		{"a_c", "abbc", false},
+		{"%c", "abcd", false},
+		{"%%a", "ba", true},
+		{"é", "É", false},
+		{"É", "É", true},
+	}
+	for _, tt := range tests {
+		if got := like([]rune(tt.pattern), []rune(tt.value)); got != tt.want {
+			t.Errorf("like(%q, %q) = %v, want %v", tt.pattern, tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestSQL(t *testing.T) {
+	columns := func(attribute string) string {
+		if attribute == "Title" {
+			return ""
+		}
+		return `"` + attribute + `"`
+	}
+	tests := []struct {
+		filter string
+		where  string
+		args   []interface{}
+	}{
+		{"Age < 12", `("Age" IS NOT NULL AND "Age" <> '' AND CAST("Age" AS REAL) < ?)`, []interface{}{12.0}},
+		{"Sex = 'female'", `("Sex" IS NOT NULL AND "Sex" <> '' AND "Sex" = ?)`, []interface{}{"female"}},
+		{"Pclass NOT IN (1, 2)", `("Pclass" IS NOT NULL AND "Pclass" <> '' AND CAST("Pclass" AS REAL) NOT IN (?, ?))`, []interface{}{1.0, 2.0}},
+		{"Cabin IS NULL", `("Cabin" IS NULL OR "Cabin" = '')`, nil},
+		{"NOT Cabin IS NOT NULL", `NOT ("Cabin" IS NOT NULL AND "Cabin" <> '')`, nil},
+		{"Name LIKE 'a%' OR Age >= 1 AND Fare != 2", `(("Name" IS NOT NULL AND "Name" <> '' AND "Name" LIKE ?) OR (("Age" IS NOT NULL AND "Age" <> '' AND CAST("Age" AS REAL) >= ?) AND ("Fare" IS NOT NULL AND "Fare" <> '' AND CAST("Fare" AS REAL) != ?)))`, []interface{}{"a%", 1.0, 2.0}},
+	}
+	for _, tt := range tests {
+		expr, err := Parse(tt.filter)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", tt.filter, err)
+		}
+		where, args, ok := expr.SQL(columns)
+		if !ok || where != tt.where || !reflect.DeepEqual(args, tt.args) {
+			t.Errorf("%s compiled to %s %v (%v), want %s %v", tt.filter, where, args, ok, tt.where, tt.args)
+		}
+	}
+
+	expr, err := Parse("Age < 12 AND Title = 'Mr'")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if where, args, ok := expr.SQL(columns); ok || where != "" || args != nil {
+		t.Errorf("an attribute without a column compiled to %q %v", where, args)
+	}
+}
@@ -0,0 +1,76 @@
+// internal/app/query/sql.go
+package query
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SQL compiles the expression to a WHERE clause with ? placeholders for
+// args. column maps an attribute to its table column; ok is false when it
+// returns "" for an attribute the expression uses. Columns are expected to
+// hold text, with missing values stored as NULL or as an empty string, and
+// numeric attributes are cast to REAL so they compare like Match compares
+// them.
+func (e *Expr) SQL(column func(attribute string) string) (where string, args []interface{}, ok bool) {
+	c := &sqlCompiler{column: column}
+	where = c.compile(e.Root)
+	if c.missing {
+		return "", nil, false
+	}
+	return where, c.args, true
+}
+
+type sqlCompiler struct {
+	column  func(string) string
+	args    []interface{}
+	missing bool
+}
+
+func (c *sqlCompiler) compile(node Node) string {
+	switch n := node.(type) {
+	case *Logical:
+		return fmt.Sprintf("(%s %s %s)", c.compile(n.Left), n.Op, c.compile(n.Right))
+	case *Not:
+		return fmt.Sprintf("NOT %s", c.compile(n.X))
+	case *Predicate:
+		return c.predicate(n)
+	}
+	return ""
+}
+
+// predicate guards every comparison against missing values so that it is
+// never NULL, which keeps NOT consistent with Match.
+func (c *sqlCompiler) predicate(n *Predicate) string {
+	column := c.column(n.Attribute)
+	if column == "" {
+		c.missing = true
+		return ""
+	}
+	switch n.Op {
+	case OpIsNull:
+		return fmt.Sprintf("(%[1]s IS NULL OR %[1]s = '')", column)
+	case OpIsNotNull:
+		return fmt.Sprintf("(%[1]s IS NOT NULL AND %[1]s <> '')", column)
+	}
+
+	operand := column
+	if n.Numeric {
+		operand = fmt.Sprintf("CAST(%s AS REAL)", column)
+	}
+	placeholders := make([]string, len(n.Values))
+	for i, literal := range n.Values {
+		placeholders[i] = "?"
+		if n.Numeric {
+			c.args = append(c.args, literal.Number)
+		} else {
+			c.args = append(c.args, literal.Text)
+		}
+	}
+
+	test := fmt.Sprintf("%s %s ?", operand, n.Op)
+	if n.Op == OpIn || n.Op == OpNotIn {
+		test = fmt.Sprintf("%s %s (%s)", operand, n.Op, strings.Join(placeholders, ", "))
+	}
+	return fmt.Sprintf("(%[1]s IS NOT NULL AND %[1]s <> '' AND %[2]s)", column, test)
+}
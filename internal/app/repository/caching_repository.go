@@ -0,0 +1,248 @@
+// internal/app/repository/caching_repository.go
+package repository
+
+import (
+	"container/list"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/shindesatish/titanic-service/internal/app/query"
+	"github.com/shindesatish/titanic-service/internal/app/validation"
+	"github.com/shindesatish/titanic-service/pkg/model"
+)
+
+// CacheStats reports the effectiveness of a CachingRepository.
+type CacheStats struct {
+	Hits     uint64 `json:"hits"`
+	Misses   uint64 `json:"misses"`
+	Entries  int    `json:"entries"`
+	Capacity int    `json:"capacity"`
+	TTL      string `json:"ttl"`
+}
+
+// CachingRepository decorates a Repository with an in-process LRU cache.
+// Entries are keyed by method name and arguments, expire after the TTL and
+// are dropped wholesale whenever the wrapped repository's DataVersion
+// changes or Invalidate is called.
+type CachingRepository struct {
+	Repository Repository
+
+	capacity int
+	ttl      time.Duration
+	now      func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List
+	version string
+	hits    uint64
+	misses  uint64
+}
+
+type cacheEntry struct {
+	key     string
+	value   interface{}
+	expires time.Time
+}
+
+func NewCachingRepository(repository Repository, capacity int, ttl time.Duration) *CachingRepository {
+	if capacity <= 0 {
+		capacity = 1
+	}
+	return &CachingRepository{
+		Repository: repository,
+		capacity:   capacity,
+		ttl:        ttl,
+		now:        time.Now,
+		entries:    make(map[string]*list.Element),
+		order:      list.New(),
+	}
+}
+
+func (r *CachingRepository) GetAllPassengers() ([]model.Passenger, error) {
+	value, err := r.cached("GetAllPassengers", func() (interface{}, error) {
+		return r.Repository.GetAllPassengers()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return append([]model.Passenger(nil), value.([]model.Passenger)...), nil
+}
+
+// ForEachPassenger is not cached: streaming exists to keep memory flat, so
+// it always reads through to the wrapped repository.
+func (r *CachingRepository) ForEachPassenger(fn func(*model.Passenger) error) error {
+	return r.Repository.ForEachPassenger(fn)
+}
+
+// ForEachMatchingPassenger reads through for the same reason.
+func (r *CachingRepository) ForEachMatchingPassenger(where *query.Expr, fn func(*model.Passenger) error) error {
+	return r.Repository.ForEachMatchingPassenger(where, fn)
+}
+
+func (r *CachingRepository) GetPassengerByID(passengerID uint) (*model.Passenger, error) {
+	key := fmt.Sprintf("GetPassengerByID:%d", passengerID)
+	value, err := r.cached(key, func() (interface{}, error) {
+		return r.Repository.GetPassengerByID(passengerID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	passenger := *value.(*model.Passenger)
+	return &passenger, nil
+}
+
+func (r *CachingRepository) GetPassengersByIDs(passengerIDs []uint) ([]model.Passenger, error) {
+	key := fmt.Sprintf("GetPassengersByIDs:%v", passengerIDs)
+	value, err := r.cached(key, func() (interface{}, error) {
+		return r.Repository.GetPassengersByIDs(passengerIDs)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return append([]model.Passenger(nil), value.([]model.Passenger)...), nil
+}
+
+func (r *CachingRepository) GetPassengerAttributes(passengerID uint, attributes []string) (*model.Passenger, error) {
+	key := fmt.Sprintf("GetPassengerAttributes:%d:%q", passengerID, attributes)
+	value, err := r.cached(key, func() (interface{}, error) {
+		return r.Repository.GetPassengerAttributes(passengerID, attributes)
+	})
+	if err != nil {
+		return nil, err
+	}
+	passenger := *value.(*model.Passenger)
+	return &passenger, nil
+}
+
+func (r *CachingRepository) GetFareHistogram() (map[string]int, error) {
+	value, err := r.cached("GetFareHistogram", func() (interface{}, error) {
+		return r.Repository.GetFareHistogram()
+	})
+	if err != nil {
+		return nil, err
+	}
+	histogram := make(map[string]int)
+	for label, count := range value.(map[string]int) {
+		histogram[label] = count
+	}
+	return histogram, nil
+}
+
+func (r *CachingRepository) DataVersion() (string, error) {
+	return r.Repository.DataVersion()
+}
+
+// ImportPassengers writes through to the wrapped repository and drops the
+// cache once the import has been applied.
+func (r *CachingRepository) ImportPassengers(passengers []model.Passenger, mode ImportMode, dryRun bool, precondition Precondition) (*ImportResult, error) {
+	result, err := r.Repository.ImportPassengers(passengers, mode, dryRun, precondition)
+	if err == nil && !dryRun {
+		r.Invalidate()
+	}
+	return result, err
+}
+
+func (r *CachingRepository) CrossTabulate(rows, cols, value string, withValues bool) ([]model.CrossTabCell, error) {
+	key := fmt.Sprintf("CrossTabulate:%q:%q:%q:%t", rows, cols, value, withValues)
+	cached, err := r.cached(key, func() (interface{}, error) {
+		return r.Repository.CrossTabulate(rows, cols, value, withValues)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return append([]model.CrossTabCell(nil), cached.([]model.CrossTabCell)...), nil
+}
+
+func (r *CachingRepository) SearchPassengers(query, mode string, limit int) ([]model.SearchHit, error) {
+	key := fmt.Sprintf("SearchPassengers:%q:%q:%d", query, mode, limit)
+	cached, err := r.cached(key, func() (interface{}, error) {
+		return r.Repository.SearchPassengers(query, mode, limit)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return append([]model.SearchHit(nil), cached.([]model.SearchHit)...), nil
+}
+
+// ValidateDataset always reads through so reports reflect the data on disk.
+func (r *CachingRepository) ValidateDataset() (*validation.Report, error) {
+	return r.Repository.ValidateDataset()
+}
+
+// Invalidate drops every cached entry.
+func (r *CachingRepository) Invalidate() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.purge()
+}
+
+// CacheStats returns the hit and miss counters along with the current size.
+func (r *CachingRepository) CacheStats() CacheStats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return CacheStats{
+		Hits:     r.hits,
+		Misses:   r.misses,
+		Entries:  r.order.Len(),
+		Capacity: r.capacity,
+		TTL:      r.ttl.String(),
+	}
+}
+
+// cached returns the value stored under key, calling load on a miss. Errors
+// are never cached.
+func (r *CachingRepository) cached(key string, load func() (interface{}, error)) (interface{}, error) {
+	version, err := r.Repository.DataVersion()
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	if version != r.version {
+		r.purge()
+		r.version = version
+	}
+	if elem, ok := r.entries[key]; ok {
+		entry := elem.Value.(*cacheEntry)
+		if r.ttl <= 0 || r.now().Before(entry.expires) {
+			r.order.MoveToFront(elem)
+			r.hits++
+			r.mu.Unlock()
+			return entry.value, nil
+		}
+		r.order.Remove(elem)
+		delete(r.entries, key)
+	}
+	r.misses++
+	r.mu.Unlock()
+
+	value, err := load()
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	// Don't store a result computed from data that changed while loading
+	if version != r.version {
+		return value, nil
+	}
+	if elem, ok := r.entries[key]; ok {
+		r.order.Remove(elem)
+	}
+	r.entries[key] = r.order.PushFront(&cacheEntry{key: key, value: value, expires: r.now().Add(r.ttl)})
+	for r.order.Len() > r.capacity {
+		oldest := r.order.Back()
+		r.order.Remove(oldest)
+		delete(r.entries, oldest.Value.(*cacheEntry).key)
+	}
+	return value, nil
+}
+
+// purge empties the cache. The caller must hold r.mu.
+func (r *CachingRepository) purge() {
+	r.entries = make(map[string]*list.Element)
+	r.order.Init()
+}
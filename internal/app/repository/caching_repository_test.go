@@ -0,0 +1,176 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/shindesatish/titanic-service/pkg/model"
+)
+
+// versionedRepository serves passengers 1 to 10 under a version the test
+// controls, and counts the lookups that reach it.
+type versionedRepository struct {
+	Repository
+	version string
+	loads   map[uint]int
+}
+
+func newVersionedRepository() *versionedRepository {
+	return &versionedRepository{version: "v1", loads: map[uint]int{}}
+}
+
+func (r *versionedRepository) GetPassengerByID(passengerID uint) (*model.Passenger, error) {
+	r.loads[passengerID]++
+	if passengerID < 1 || passengerID > 10 {
+		return nil, fmt.Errorf("%w with ID %d", ErrPassengerNotFound, passengerID)
+	}
+	return &model.Passenger{PassengerID: int(passengerID), Name: r.version}, nil
+}
+
+func (r *versionedRepository) DataVersion() (string, error) {
+	return r.version, nil
+}
+
+func (r *versionedRepository) ImportPassengers(passengers []model.Passenger, mode ImportMode, dryRun bool, precondition Precondition) (*ImportResult, error) {
+	return &ImportResult{Mode: mode, DryRun: dryRun, Received: len(passengers)}, nil
+}
+
+// get looks up each passenger through cache, failing the test on errors.
+func get(t *testing.T, cache *CachingRepository, ids ...uint) {
+	t.Helper()
+	for _, id := range ids {
+		if _, err := cache.GetPassengerByID(id); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func checkStats(t *testing.T, cache *CachingRepository, hits, misses uint64, entries int) {
+	t.Helper()
+	stats := cache.CacheStats()
+	if stats.Hits != hits || stats.Misses != misses || stats.Entries != entries {
+		t.Errorf("got %d hits, %d misses and %d entries, want %d, %d and %d", stats.Hits, stats.Misses, stats.Entries, hits, misses, entries)
+	}
+}
+
+func TestCachingRepositoryEvictsLeastRecentlyUsed(t *testing.T) {
+	repo := newVersionedRepository()
+	cache := NewCachingRepository(repo, 2, 0)
+
+	get(t, cache, 1, 2, 1)
+	checkStats(t, cache, 1, 2, 2)
+
+	// 2 is now the least recently used, so 3 pushes it out
+	get(t, cache, 3, 1)
+	checkStats(t, cache, 2, 3, 2)
+	get(t, cache, 2)
+	checkStats(t, cache, 2, 4, 2)
+	if repo.loads[1] != 1 || repo.loads[2] != 2 || repo.loads[3] != 1 {
+		t.Errorf("got loads %v", repo.loads)
+	}
+
+	// 3 went out when 2 came back, leaving 1 and 2
+	get(t, cache, 1, 2)
+	checkStats(t, cache, 4, 4, 2)
+	get(t, cache, 3)
+	if repo.loads[3] != 2 {
+		t.Errorf("passenger 3 was loaded %d times, want 2", repo.loads[3])
+	}
+
+	if stats := cache.CacheStats(); stats.Capacity != 2 || stats.TTL != "0s" {
+		t.Errorf("got %+v", stats)
+	}
+	if NewCachingRepository(repo, 0, 0).CacheStats().Capacity != 1 {
+		t.Errorf("a cache of no entries holds one")
+	}
+}
+
+func TestCachingRepositoryExpiresEntries(t *testing.T) {
+	repo := newVersionedRepository()
+	cache := NewCachingRepository(repo, 10, time.Minute)
+	now := time.Date(1912, 4, 15, 2, 20, 0, 0, time.UTC)
+	cache.now = func() time.Time { return now }
+
+	get(t, cache, 1)
+	now = now.Add(59 * time.Second)
+	get(t, cache, 1)
+	checkStats(t, cache, 1, 1, 1)
+
+	// A hit does not extend the entry's life
+	now = now.Add(time.Second)
+	get(t, cache, 1)
+	checkStats(t, cache, 1, 2, 1)
+	if repo.loads[1] != 2 {
+		t.Errorf("passenger 1 was loaded %d times, want 2", repo.loads[1])
+	}
+
+	// Without a TTL, entries never expire
+	forever := NewCachingRepository(repo, 10, 0)
+	forever.now = cache.now
+	get(t, forever, 2)
+	now = now.Add(24 * time.Hour)
+	get(t, forever, 2)
+	checkStats(t, forever, 1, 1, 1)
+}
+
+func TestCachingRepositoryPurgesOnDataVersion(t *testing.T) {
+	repo := newVersionedRepository()
+	cache := NewCachingRepository(repo, 10, 0)
+
+	get(t, cache, 1, 2, 3)
+	checkStats(t, cache, 0, 3, 3)
+
+	repo.version = "v2"
+	passenger, err := cache.GetPassengerByID(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if passenger.Name != "v2" {
+		t.Errorf("got passenger %+v from the old version", passenger)
+	}
+	// Every entry of v1 went, not just the one asked for
+	checkStats(t, cache, 0, 4, 1)
+
+	// Imports drop the cache even when the version stays the same, dry runs
+	// leave it alone
+	if _, err := cache.ImportPassengers(nil, ImportUpsert, true, nil); err != nil {
+		t.Fatal(err)
+	}
+	checkStats(t, cache, 0, 4, 1)
+	if _, err := cache.ImportPassengers(nil, ImportUpsert, false, nil); err != nil {
+		t.Fatal(err)
+	}
+	checkStats(t, cache, 0, 4, 0)
+
+	get(t, cache, 1)
+	cache.Invalidate()
+	checkStats(t, cache, 0, 5, 0)
+}
+
+func TestCachingRepositoryErrorsAndCopies(t *testing.T) {
+	repo := newVersionedRepository()
+	cache := NewCachingRepository(repo, 10, 0)
+
+	// Errors are counted as misses but never stored
+	for i := 0; i < 2; i++ {
+		if _, err := cache.GetPassengerByID(99); !errors.Is(err, ErrPassengerNotFound) {
+			t.Fatalf("got error %v", err)
+		}
+	}
+	checkStats(t, cache, 0, 2, 0)
+	if repo.loads[99] != 2 {
+		t.Errorf("passenger 99 was loaded %d times, want 2", repo.loads[99])
+	}
+
+	// Callers get their own copy of a cached passenger
+	passenger, err := cache.GetPassengerByID(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	passenger.Name = "changed"
+	if again, _ := cache.GetPassengerByID(1); again.Name != "v1" {
+		t.Errorf("cached passenger was changed to %+v", again)
+	}
+}
@@ -2,17 +2,43 @@
 package repository
 
 import (
+	"crypto/sha256"
 	"encoding/csv"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"io"
 	"os"
-	"sort"
+	"path/filepath"
 	"strconv"
+	"sync"
+	"time"
 
+	"github.com/shindesatish/titanic-service/internal/app/query"
+	"github.com/shindesatish/titanic-service/internal/app/validation"
 	"github.com/shindesatish/titanic-service/pkg/model"
 )
 
 type CSVRepository struct {
 	Path string
+
+	// Lenient skips rows that fail validation instead of failing the whole
+	// load; the skipped rows are available from SkippedRows.
+	Lenient bool
+	skippedRows
+
+	// searches is the inverted index SearchPassengers uses.
+	searches searchCache
+
+	// writeMu serializes imports, which rewrite the whole file.
+	writeMu sync.Mutex
+
+	// checksum caches the file digest for the modification time and size it
+	// was computed at, so DataVersion only re-hashes after the file changes.
+	mu       sync.Mutex
+	checksum string
+	modTime  time.Time
+	size     int64
 }
 
 func NewCSVRepository(path string) *CSVRepository {
@@ -20,141 +46,231 @@ func NewCSVRepository(path string) *CSVRepository {
 }
 
 func (r *CSVRepository) GetAllPassengers() ([]model.Passenger, error) {
-	file, err := os.Open(r.Path)
-	if err != nil {
-		return nil, fmt.Errorf("failed to open CSV file: %v", err)
-	}
-	defer file.Close()
-
-	reader := csv.NewReader(file)
-	records, err := reader.ReadAll()
-	if err != nil {
-		return nil, fmt.Errorf("failed to read CSV records: %v", err)
-	}
-
 	var passengers []model.Passenger
-	for i := 1; i < len(records); i++ {
-		passenger, err := convertCSVRecordToPassenger(records[i])
-		if err != nil {
-			return nil, fmt.Errorf("failed to convert CSV record to Passenger: %v", err)
-		}
+	err := r.ForEachPassenger(func(passenger *model.Passenger) error {
 		passengers = append(passengers, *passenger)
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return passengers, nil
 }
 
-func (r *CSVRepository) GetPassengerByID(passengerID uint) (*model.Passenger, error) {
+// ForEachMatchingPassenger filters a single pass over the CSV file.
+func (r *CSVRepository) ForEachMatchingPassenger(where *query.Expr, fn func(*model.Passenger) error) error {
+	return forEachMatching(r.ForEachPassenger, where, fn)
+}
+
+// ForEachPassenger reads the CSV file one record at a time and calls fn for
+// each passenger.
+func (r *CSVRepository) ForEachPassenger(fn func(*model.Passenger) error) error {
 	file, err := os.Open(r.Path)
 	if err != nil {
-		return nil, fmt.Errorf("failed to open CSV file: %v", err)
+		return fmt.Errorf("failed to open CSV file: %v", err)
 	}
 	defer file.Close()
 
 	reader := csv.NewReader(file)
-	records, err := reader.ReadAll()
-	if err != nil {
-		return nil, fmt.Errorf("failed to read CSV records: %v", err)
+	reader.ReuseRecord = true
+
+	// Skip the header row
+	if _, err := reader.Read(); err != nil {
+		return fmt.Errorf("failed to read CSV header: %v", err)
 	}
 
-	for i := 1; i < len(records); i++ {
-		record := records[i]
+	var validator *validation.Validator
+	if r.Lenient {
+		validator = validation.NewValidator(r.Path)
+		reader.FieldsPerRecord = -1
+	}
 
-		id, err := strconv.Atoi(record[0])
-		if err != nil {
-			return nil, fmt.Errorf("failed to convert ID to integer: %v", err)
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			if validator != nil {
+				r.setSkippedRows(validator.Report().Errors)
+			}
+			return nil
 		}
 
-		if uint(id) == passengerID {
-			passenger, err := convertCSVRecordToPassenger(record)
+		var passenger *model.Passenger
+		if validator != nil {
+			if parseErr, ok := err.(*csv.ParseError); ok {
+				validator.AddError(validation.RowError{Line: parseErr.StartLine, Check: validation.CheckFormat, Message: parseErr.Err.Error()})
+				continue
+			}
+			if err != nil {
+				return fmt.Errorf("failed to read CSV record: %v", err)
+			}
+			line, _ := reader.FieldPos(0)
+			if passenger, _ = validator.CheckRecord(line, record); passenger == nil {
+				continue
+			}
+		} else {
+			if err != nil {
+				return fmt.Errorf("failed to read CSV record: %v", err)
+			}
+			passenger, err = convertCSVRecordToPassenger(record)
 			if err != nil {
-				return nil, fmt.Errorf("failed to convert CSV record to Passenger: %v", err)
+				line, _ := reader.FieldPos(0)
+				return fmt.Errorf("failed to convert CSV record on line %d to Passenger: %v", line, err)
+			}
+		}
+
+		if err := fn(passenger); err != nil {
+			if errors.Is(err, ErrStopIteration) {
+				return nil
 			}
-			return passenger, nil
+			return err
 		}
 	}
+}
 
-	return nil, fmt.Errorf("passenger not found with ID %d", passengerID)
+// GetPassengerByID reads the CSV file up to the passenger. It goes through
+// ForEachPassenger, so lenient mode skips invalid rows here too.
+func (r *CSVRepository) GetPassengerByID(passengerID uint) (*model.Passenger, error) {
+	return findPassenger(r.ForEachPassenger, passengerID)
+}
+
+// GetPassengersByIDs reads the CSV file once, however many IDs are asked
+// for.
+func (r *CSVRepository) GetPassengersByIDs(passengerIDs []uint) ([]model.Passenger, error) {
+	return getPassengersByIDs(r.ForEachPassenger, passengerIDs)
 }
 
 func (r *CSVRepository) GetPassengerAttributes(passengerID uint, attributes []string) (*model.Passenger, error) {
-	file, err := os.Open(r.Path)
+	passenger, err := r.GetPassengerByID(passengerID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to open CSV file: %v", err)
+		return nil, err
 	}
-	defer file.Close()
+	return passenger.Project(attributes)
+}
 
-	reader := csv.NewReader(file)
-	records, err := reader.ReadAll()
+// GetFareHistogram buckets the fares of a single pass over the passengers.
+func (r *CSVRepository) GetFareHistogram() (map[string]int, error) {
+	return fareHistogram(r.ForEachPassenger)
+}
+
+// DataVersion returns the SHA-256 checksum of the CSV file.
+func (r *CSVRepository) DataVersion() (string, error) {
+	info, err := os.Stat(r.Path)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read CSV records: %v", err)
+		return "", fmt.Errorf("failed to stat CSV file: %v", err)
 	}
 
-	for i := 1; i < len(records); i++ {
-		record := records[i]
-		id, err := strconv.Atoi(record[0])
-		if err != nil {
-			return nil, fmt.Errorf("failed to convert ID to integer: %v", err)
-		}
+	r.mu.Lock()
+	defer r.mu.Unlock()
 
-		if uint(id) == passengerID {
-			passenger, err := convertCSVRecordToPassengerWithAttributes(record, attributes)
-			if err != nil {
-				return nil, fmt.Errorf("failed to convert CSV record to Passenger: %v", err)
-			}
-			return passenger, nil
-		}
+	if r.checksum != "" && info.ModTime().Equal(r.modTime) && info.Size() == r.size {
+		return r.checksum, nil
 	}
 
-	return nil, fmt.Errorf("passenger not found with ID %d", passengerID)
-}
-
-func (r *CSVRepository) GetFareHistogram() (map[string]int, error) {
 	file, err := os.Open(r.Path)
 	if err != nil {
-		return nil, fmt.Errorf("failed to open CSV file: %v", err)
+		return "", fmt.Errorf("failed to open CSV file: %v", err)
 	}
 	defer file.Close()
 
-	reader := csv.NewReader(file)
-	records, err := reader.ReadAll()
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", fmt.Errorf("failed to checksum CSV file: %v", err)
+	}
+
+	r.checksum = hex.EncodeToString(hash.Sum(nil))
+	r.modTime = info.ModTime()
+	r.size = info.Size()
+	return r.checksum, nil
+}
+
+// ImportPassengers merges the passengers into the CSV file. The new contents
+// are written to a temporary file that atomically replaces the original, so
+// readers never see a partially written dataset. The precondition is
+// checked under the same lock as the write, so no other import of this
+// repository can slip in between. In lenient mode, upserts fail with
+// ErrRowsSkipped while the file has rows the rewrite would drop.
+func (r *CSVRepository) ImportPassengers(passengers []model.Passenger, mode ImportMode, dryRun bool, precondition Precondition) (*ImportResult, error) {
+	r.writeMu.Lock()
+	defer r.writeMu.Unlock()
+
+	version, err := r.DataVersion()
 	if err != nil {
-		return nil, fmt.Errorf("failed to read CSV records: %v", err)
+		return nil, err
+	}
+	if err := precondition.check(version); err != nil {
+		return nil, err
 	}
 
-	var fares []float64
-	for i := 1; i < len(records); i++ {
-		record := records[i]
-		fare, err := strconv.ParseFloat(record[9], 64)
-		if err != nil {
-			return nil, fmt.Errorf("failed to convert Fare to float64: %v", err)
+	existing, err := r.GetAllPassengers()
+	if err != nil {
+		return nil, err
+	}
+	if lines := validation.Lines(r.SkippedRows()); mode == ImportUpsert && len(lines) > 0 {
+		return nil, fmt.Errorf("%w (%d rows, first on line %d)", ErrRowsSkipped, len(lines), lines[0])
+	}
+	merged, result := mergePassengers(existing, passengers, mode)
+	result.DryRun = dryRun
+	if dryRun {
+		return result, nil
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(r.Path), ".import-*.csv")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary CSV file: %v", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	writer := csv.NewWriter(tmp)
+	if err := writer.Write(model.CSVHeader); err != nil {
+		tmp.Close()
+		return nil, fmt.Errorf("failed to write CSV header: %v", err)
+	}
+	for i := range merged {
+		if err := writer.Write(merged[i].CSVRecord()); err != nil {
+			tmp.Close()
+			return nil, fmt.Errorf("failed to write CSV record: %v", err)
 		}
-		fares = append(fares, fare)
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		tmp.Close()
+		return nil, fmt.Errorf("failed to write CSV records: %v", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return nil, fmt.Errorf("failed to close temporary CSV file: %v", err)
+	}
+	if err := os.Rename(tmp.Name(), r.Path); err != nil {
+		return nil, fmt.Errorf("failed to replace CSV file: %v", err)
 	}
 
-	// Sort the fares in ascending order
-	sort.Float64s(fares)
+	return result, nil
+}
 
-	// Calculate percentiles
-	percentiles := []float64{25, 50, 75, 90, 95, 99}
-	percentileValues := make(map[string]float64)
-	for _, p := range percentiles {
-		idx := int(float64(len(fares)-1) * (p / 100.0))
-		percentileValues[fmt.Sprintf("%.2f%%", p)] = fares[idx]
+// CrossTabulate groups the passengers of the CSV file in a single pass.
+func (r *CSVRepository) CrossTabulate(rows, cols, value string, withValues bool) ([]model.CrossTabCell, error) {
+	return crossTabulate(r.ForEachPassenger, rows, cols, value, withValues)
+}
+
+// SearchPassengers searches an in-memory inverted index of the CSV file,
+// rebuilt whenever the file changes.
+func (r *CSVRepository) SearchPassengers(query, mode string, limit int) ([]model.SearchHit, error) {
+	version, err := r.DataVersion()
+	if err != nil {
+		return nil, err
 	}
+	return r.searches.search(version, r.GetAllPassengers, query, mode, limit)
+}
 
-	// Create histogram buckets and count occurrences
-	fareHistogram := make(map[string]int)
-	for _, fare := range fares {
-		for label, pValue := range percentileValues {
-			if fare <= pValue {
-				fareHistogram[label]++
-				break
-			}
-		}
+// ValidateDataset checks every row of the CSV file and reports all problems.
+func (r *CSVRepository) ValidateDataset() (*validation.Report, error) {
+	file, err := os.Open(r.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open CSV file: %v", err)
 	}
+	defer file.Close()
 
-	return fareHistogram, nil
+	return validation.ValidateCSV(r.Path, file)
 }
 
 // Helper function to convert CSV record to Passenger
@@ -180,13 +296,13 @@ func convertCSVRecordToPassenger(record []string) (*model.Passenger, error) {
 		return nil, fmt.Errorf("failed to convert Pclass to integer: %v", err)
 	}
 
-	var age float64
-	fmt.Println(record)
+	var age model.NullFloat64
 	if record[5] != "" {
-		age, err = strconv.ParseFloat(record[5], 64)
+		age.Float64, err = strconv.ParseFloat(record[5], 64)
 		if err != nil {
 			return nil, fmt.Errorf("failed to convert Age to float64: %v", err)
 		}
+		age.Valid = true
 	}
 
 	sibSp, err := strconv.Atoi(record[6])
@@ -223,99 +339,3 @@ func convertCSVRecordToPassenger(record []string) (*model.Passenger, error) {
 	return passenger, nil
 }
 
-// Helper function to convert CSV record to Passenger with specific attributes
-func convertCSVRecordToPassengerWithAttributes(record []string, attributes []string) (*model.Passenger, error) {
-	// Ensure that the CSV record has the expected number of fields
-	if len(record) != 12 {
-		return nil, fmt.Errorf("invalid CSV record format")
-	}
-
-	// Create a map to store the attribute values
-	attributeValues := make(map[string]interface{})
-
-	// Parse fields from the CSV record and store in the map
-	for _, attribute := range attributes {
-		switch attribute {
-		case "PassengerId":
-			value, err := strconv.Atoi(record[0])
-			if err != nil {
-				return nil, fmt.Errorf("failed to convert ID to integer: %v", err)
-			}
-			attributeValues["PassengerId"] = uint(value)
-		case "Survived":
-			value, err := strconv.Atoi(record[1])
-			if err != nil {
-				return nil, fmt.Errorf("failed to convert Survived to integer: %v", err)
-			}
-			attributeValues["Survived"] = value
-		case "Pclass":
-			value, err := strconv.Atoi(record[2])
-			if err != nil {
-				return nil, fmt.Errorf("failed to convert Pclass to integer: %v", err)
-			}
-			attributeValues["Pclass"] = value
-		case "Name":
-			attributeValues["Name"] = record[3]
-		case "Sex":
-			attributeValues["Sex"] = record[4]
-		case "Age":
-			value, err := strconv.ParseFloat(record[5], 64)
-			if err != nil {
-				return nil, fmt.Errorf("failed to convert Age to float64: %v", err)
-			}
-			attributeValues["Age"] = value
-		case "SibSp":
-			value, err := strconv.Atoi(record[6])
-			if err != nil {
-				return nil, fmt.Errorf("failed to convert SibSp to integer: %v", err)
-			}
-			attributeValues["SibSp"] = value
-		case "Parch":
-			value, err := strconv.Atoi(record[7])
-			if err != nil {
-				return nil, fmt.Errorf("failed to convert Parch to integer: %v", err)
-			}
-			attributeValues["Parch"] = value
-		case "Ticket":
-			attributeValues["Ticket"] = record[8]
-		case "Fare":
-			value, err := strconv.ParseFloat(record[9], 64)
-			if err != nil {
-				return nil, fmt.Errorf("failed to convert Fare to float64: %v", err)
-			}
-			attributeValues["Fare"] = value
-		case "Cabin":
-			attributeValues["Cabin"] = record[10]
-		case "Embarked":
-			attributeValues["Embarked"] = record[11]
-		// Add cases for other attributes as needed
-		default:
-			return nil, fmt.Errorf("unknown attribute: %s", attribute)
-		}
-	}
-
-	id := 0
-	if val, ok := attributeValues["PassengerId"]; ok && val != nil {
-		// Attempt to type assert only if the value is not nil
-		id = val.(int)
-	}
-
-	// Create a model.Passenger instance with specific attributes
-	passenger := &model.Passenger{
-		PassengerID: id,
-		Survived:    attributeValues["Survived"].(int),
-		Pclass:      attributeValues["Pclass"].(int),
-		Name:        attributeValues["Name"].(string),
-		Sex:         attributeValues["Sex"].(string),
-		Age:         attributeValues["Age"].(float64),
-		SibSp:       attributeValues["SibSp"].(int),
-		Parch:       attributeValues["Parch"].(int),
-		Ticket:      attributeValues["Ticket"].(string),
-		Fare:        attributeValues["Fare"].(float64),
-		Cabin:       attributeValues["Cabin"].(string),
-		Embarked:    attributeValues["Embarked"].(string),
-		// Add assignments for other attributes as needed
-	}
-
-	return passenger, nil
-}
@@ -0,0 +1,357 @@
+package repository
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/shindesatish/titanic-service/internal/app/validation"
+	"github.com/shindesatish/titanic-service/pkg/model"
+)
+
+// backends opens both backends on temporary copies of the dataset.
+func backends(t *testing.T) map[string]Repository {
+	dir := copyDatastore(t)
+	return map[string]Repository{
+		"csv":    NewCSVRepository(filepath.Join(dir, "titanic.csv")),
+		"sqlite": openSQLite(t, dir),
+	}
+}
+
+func dataVersion(t *testing.T, repo Repository) string {
+	t.Helper()
+	version, err := repo.DataVersion()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return version
+}
+
+func TestImportPassengers(t *testing.T) {
+	updated := model.Passenger{PassengerID: 1, Survived: 1, Pclass: 3, Name: "Braund, Mr. Owen Harris", Sex: "male", Age: model.NewNullFloat64(23), SibSp: 1, Ticket: "A/5 21171", Fare: 7.25, Embarked: "S"}
+	inserted := model.Passenger{PassengerID: 1000, Survived: 0, Pclass: 2, Name: "Doe, Mr. John", Sex: "male", Ticket: "X 1", Fare: 13, Cabin: "F1"}
+
+	for name, repo := range backends(t) {
+		before := dataVersion(t, repo)
+
+		// A dry run reports the changes and makes none of them
+		result, err := repo.ImportPassengers([]model.Passenger{updated, inserted}, ImportUpsert, true, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := (ImportResult{Mode: ImportUpsert, DryRun: true, Received: 2, Inserted: 1, Updated: 1}); *result != want {
+			t.Errorf("%s: dry run got %+v, want %+v", name, *result, want)
+		}
+		if version := dataVersion(t, repo); version != before {
+			t.Errorf("%s: dry run changed the version from %s to %s", name, before, version)
+		}
+		if _, err := repo.GetPassengerByID(1000); !errors.Is(err, ErrPassengerNotFound) {
+			t.Errorf("%s: dry run inserted passenger 1000: %v", name, err)
+		}
+
+		result, err = repo.ImportPassengers([]model.Passenger{updated, inserted, inserted}, ImportUpsert, false, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := (ImportResult{Mode: ImportUpsert, Received: 3, Inserted: 1, Updated: 1}); *result != want {
+			t.Errorf("%s: upsert got %+v, want %+v", name, *result, want)
+		}
+		afterUpsert := dataVersion(t, repo)
+		if afterUpsert == before {
+			t.Errorf("%s: upsert left the version at %s", name, before)
+		}
+		for _, want := range []model.Passenger{updated, inserted} {
+			got, err := repo.GetPassengerByID(uint(want.PassengerID))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(*got, want) {
+				t.Errorf("%s: got %+v, want %+v", name, *got, want)
+			}
+		}
+		all, err := repo.GetAllPassengers()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(all) != 892 || all[891].PassengerID != 1000 {
+			t.Errorf("%s: got %d passengers after the upsert", name, len(all))
+		}
+
+		result, err = repo.ImportPassengers([]model.Passenger{inserted, updated}, ImportReplace, false, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := (ImportResult{Mode: ImportReplace, Received: 2, Updated: 2, Deleted: 890}); *result != want {
+			t.Errorf("%s: replace got %+v, want %+v", name, *result, want)
+		}
+		if version := dataVersion(t, repo); version == afterUpsert || version == before {
+			t.Errorf("%s: replace left the version at %s", name, version)
+		}
+		all, err = repo.GetAllPassengers()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(all, []model.Passenger{inserted, updated}) {
+			t.Errorf("%s: got %+v after the replace", name, all)
+		}
+	}
+}
+
+func TestImportPassengersPrecondition(t *testing.T) {
+	for name, repo := range backends(t) {
+		before := dataVersion(t, repo)
+
+		// A rejected import changes nothing, not even the version
+		rejected := func(version string) bool { return false }
+		passenger := model.Passenger{PassengerID: 1000, Pclass: 3, Name: "Doe, Mr. John", Sex: "male", Ticket: "X 1"}
+		if _, err := repo.ImportPassengers([]model.Passenger{passenger}, ImportUpsert, false, rejected); !errors.Is(err, ErrVersionMismatch) {
+			t.Fatalf("%s: got error %v, want ErrVersionMismatch", name, err)
+		}
+		if version := dataVersion(t, repo); version != before {
+			t.Errorf("%s: rejected import changed the version from %s to %s", name, before, version)
+		}
+
+		// Of several imports expecting the same version, only one applies
+		var wg sync.WaitGroup
+		errs := make([]error, 8)
+		for i := range errs {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				passenger := model.Passenger{PassengerID: 1000 + i, Pclass: 3, Name: "Doe, Mr. John", Sex: "male", Ticket: "X 1"}
+				_, errs[i] = repo.ImportPassengers([]model.Passenger{passenger}, ImportUpsert, false, func(version string) bool {
+					return version == before
+				})
+			}(i)
+		}
+		wg.Wait()
+
+		applied := 0
+		for _, err := range errs {
+			if err == nil {
+				applied++
+			} else if !errors.Is(err, ErrVersionMismatch) {
+				t.Errorf("%s: got error %v, want ErrVersionMismatch", name, err)
+			}
+		}
+		all, err := repo.GetAllPassengers()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if applied != 1 || len(all) != 892 {
+			t.Errorf("%s: %d imports applied, leaving %d passengers", name, applied, len(all))
+		}
+	}
+}
+
+func TestDataVersion(t *testing.T) {
+	dir := copyDatastore(t)
+
+	// The CSV version is the checksum of the file, whoever wrote it
+	path := filepath.Join(dir, "titanic.csv")
+	csvRepo := NewCSVRepository(path)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sum := sha256.Sum256(data)
+	if version := dataVersion(t, csvRepo); version != hex.EncodeToString(sum[:]) || dataVersion(t, csvRepo) != version {
+		t.Errorf("got CSV version %s", version)
+	}
+	edited := append(data, []byte("1000,0,3,\"Doe, Mr. John\",male,,0,0,X 1,8,,S\n")...)
+	if err := os.WriteFile(path, edited, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	sum = sha256.Sum256(edited)
+	if version := dataVersion(t, csvRepo); version != hex.EncodeToString(sum[:]) {
+		t.Errorf("got CSV version %s after an edit", version)
+	}
+
+	// The SQLite version counts imports, and only those
+	sqliteRepo := openSQLite(t, dir)
+	before := dataVersion(t, sqliteRepo)
+	if _, err := sqliteRepo.DB.Exec("UPDATE titanic SET Fare = '8' WHERE PassengerId = '1'"); err != nil {
+		t.Fatal(err)
+	}
+	if version := dataVersion(t, sqliteRepo); version != before {
+		t.Errorf("a write outside ImportPassengers changed the version from %s to %s", before, version)
+	}
+	var n int
+	if _, err := sqliteRepo.DB.Exec("PRAGMA user_version = 41"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sqliteRepo.ImportPassengers(nil, ImportUpsert, false, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := sqliteRepo.DB.QueryRow("PRAGMA user_version").Scan(&n); err != nil || n != 42 || dataVersion(t, sqliteRepo) != "v42" {
+		t.Errorf("got user_version %d (%v) and version %s", n, err, dataVersion(t, sqliteRepo))
+	}
+}
+
+// invalidRows are the records, by line of titanic.csv, that lenient loads
+// skip.
+var invalidRows = map[int][]string{
+	3:  {"2", "1", "first", "Cumings, Mrs. John Bradley (Florence Briggs Thayer)", "female", "38", "1", "0", "PC 17599", "71.2833", "C85", "C"},
+	5:  {"4", "1", "1", "Futrelle, Mrs. Jacques Heath (Lily May Peel)", "female", "-35", "1", "0", "113803", "53.1", "C123", "S"},
+	7:  {"6", "0", "3", "Moran, Mr. James", "male", "", "0", "0", "330877", "8.4583"},
+	10: {"3", "1", "3", "Heikkinen, Miss. Laina", "female", "26", "0", "0", "STON/O2. 3101282", "7.925", "", "S"},
+}
+
+// writeInvalidDataset cuts both backends down to the first 10 passengers
+// and breaks the rows of invalidRows. Line n of the CSV file holds the row
+// with rowid n-1.
+func writeInvalidDataset(t *testing.T) (*CSVRepository, *SQLiteRepository) {
+	t.Helper()
+	dir := copyDatastore(t)
+	path := filepath.Join(dir, "titanic.csv")
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	records, err := csv.NewReader(file).ReadAll()
+	file.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	records = records[:11]
+	for line, record := range invalidRows {
+		records[line-1] = record
+	}
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	if err := writer.WriteAll(records); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	sqliteRepo := openSQLite(t, dir)
+	if _, err := sqliteRepo.DB.Exec("DELETE FROM titanic WHERE rowid > 10"); err != nil {
+		t.Fatal(err)
+	}
+	for line, record := range invalidRows {
+		// Rows always have every column, so the short one is padded
+		values := make([]interface{}, len(model.CSVHeader))
+		for i := range values {
+			values[i] = ""
+			if i < len(record) {
+				values[i] = record[i]
+			}
+		}
+		statement := "UPDATE titanic SET PassengerId = ?, Survived = ?, Pclass = ?, Name = ?, Sex = ?, Age = ?, SibSp = ?, Parch = ?, Ticket = ?, Fare = ?, Cabin = ?, Embarked = ? WHERE rowid = ?"
+		if _, err := sqliteRepo.DB.Exec(statement, append(values, line-1)...); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return NewCSVRepository(path), sqliteRepo
+}
+
+func passengerIDs(t *testing.T, repo Repository) []int {
+	t.Helper()
+	var ids []int
+	err := repo.ForEachPassenger(func(p *model.Passenger) error {
+		ids = append(ids, p.PassengerID)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ids
+}
+
+func TestLenientLoad(t *testing.T) {
+	csvRepo, sqliteRepo := writeInvalidDataset(t)
+
+	if _, err := csvRepo.GetAllPassengers(); err == nil || !strings.Contains(err.Error(), "line 3") {
+		t.Errorf("strict CSV load got error %v, want one on line 3", err)
+	}
+	if _, err := sqliteRepo.GetAllPassengers(); err == nil {
+		t.Errorf("strict SQLite load succeeded")
+	}
+	if len(csvRepo.SkippedRows()) != 0 || len(sqliteRepo.SkippedRows()) != 0 {
+		t.Errorf("strict loads skipped rows")
+	}
+
+	csvRepo.Lenient, sqliteRepo.Lenient = true, true
+	tests := []struct {
+		name string
+		repo interface {
+			Repository
+			SkippedRows() []validation.RowError
+		}
+		ids     []int
+		skipped map[int]string
+	}{
+		// The second passenger 3 is skipped as a duplicate, not the first
+		{"csv", csvRepo, []int{1, 3, 5, 7, 8, 10}, map[int]string{3: validation.CheckType, 5: validation.CheckRange, 7: validation.CheckFormat, 10: validation.CheckDuplicate}},
+		// Lines are rowids, and the padded row of passenger 6 is valid
+		{"sqlite", sqliteRepo, []int{1, 3, 5, 6, 7, 8, 10}, map[int]string{2: validation.CheckType, 4: validation.CheckRange, 9: validation.CheckDuplicate}},
+	}
+	for _, tt := range tests {
+		name, repo := tt.name, tt.repo
+		if ids := passengerIDs(t, repo); !reflect.DeepEqual(ids, tt.ids) {
+			t.Errorf("%s: lenient load got passengers %v, want %v", name, ids, tt.ids)
+		}
+		checks := map[int]string{}
+		for _, rowErr := range repo.SkippedRows() {
+			checks[rowErr.Line] = rowErr.Check
+		}
+		if !reflect.DeepEqual(checks, tt.skipped) {
+			t.Errorf("%s: got skipped rows %v, want %v", name, repo.SkippedRows(), tt.skipped)
+		}
+
+		if _, err := repo.GetPassengerByID(2); !errors.Is(err, ErrPassengerNotFound) {
+			t.Errorf("%s: got skipped passenger 2: %v", name, err)
+		}
+		if passenger, err := repo.GetPassengerByID(5); err != nil || passenger.Name != "Allen, Mr. William Henry" {
+			t.Errorf("%s: got passenger %+v, %v", name, passenger, err)
+		}
+	}
+
+	// Upserts would drop the skipped rows from the CSV file, replacements
+	// are fine
+	if _, err := csvRepo.ImportPassengers(nil, ImportUpsert, true, nil); !errors.Is(err, ErrRowsSkipped) {
+		t.Errorf("got error %v, want ErrRowsSkipped", err)
+	}
+	if _, err := csvRepo.ImportPassengers([]model.Passenger{{PassengerID: 1, Name: "Doe, Mr. John", Sex: "male", Pclass: 3, Ticket: "X 1"}}, ImportReplace, false, nil); err != nil {
+		t.Fatal(err)
+	}
+	if ids := passengerIDs(t, csvRepo); !reflect.DeepEqual(ids, []int{1}) || len(csvRepo.SkippedRows()) != 0 {
+		t.Errorf("got passengers %v and skipped rows %v after the replace", ids, csvRepo.SkippedRows())
+	}
+}
+
+func TestSkippedRows(t *testing.T) {
+	csvRepo, sqliteRepo := writeInvalidDataset(t)
+
+	// Strict repositories skip nothing, and are not even read
+	for _, repo := range []Repository{csvRepo, sqliteRepo, &versionedRepository{}} {
+		if skipped, err := SkippedRows(repo); err != nil || skipped != nil {
+			t.Errorf("%T: got %v, %v", repo, skipped, err)
+		}
+	}
+
+	// Lenient ones are read afresh, through any cache
+	csvRepo.Lenient, sqliteRepo.Lenient = true, true
+	for repo, want := range map[Repository][]int{
+		NewCachingRepository(csvRepo, 10, 0): {3, 5, 7, 10},
+		sqliteRepo:                           {2, 4, 9},
+	} {
+		skipped, err := SkippedRows(repo)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if lines := validation.Lines(skipped); !reflect.DeepEqual(lines, want) {
+			t.Errorf("%T: got skipped lines %v, want %v", repo, lines, want)
+		}
+	}
+}
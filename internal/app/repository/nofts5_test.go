@@ -0,0 +1,7 @@
+//go:build !sqlite_fts5
+
+package repository
+
+// fts5 reports whether the SQLite driver is built with FTS5, so token
+// searches on SQLite are ranked by bm25.
+const fts5 = false
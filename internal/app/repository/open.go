@@ -0,0 +1,54 @@
+// internal/app/repository/open.go
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// Options selects and configures the repository backend.
+type Options struct {
+	UseSQLite  bool
+	CSVPath    string
+	SQLitePath string
+
+	// Lenient skips invalid rows instead of failing the load.
+	Lenient bool
+
+	// CacheSize enables a CachingRepository of that many entries when
+	// positive.
+	CacheSize int
+	CacheTTL  time.Duration
+}
+
+// Open builds the repository described by opts. The returned close function
+// releases the backend and must be called once the repository is no longer
+// needed.
+func Open(opts Options) (Repository, func() error, error) {
+	var repo Repository
+	closeFn := func() error { return nil }
+
+	if opts.UseSQLite {
+		db, err := sql.Open("sqlite3", opts.SQLitePath)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to open SQLite database: %v", err)
+		}
+		sqliteRepo := NewSQLiteRepository(db)
+		sqliteRepo.Lenient = opts.Lenient
+		repo = sqliteRepo
+		closeFn = db.Close
+	} else {
+		csvRepo := NewCSVRepository(opts.CSVPath)
+		csvRepo.Lenient = opts.Lenient
+		repo = csvRepo
+	}
+
+	if opts.CacheSize > 0 {
+		repo = NewCachingRepository(repo, opts.CacheSize, opts.CacheTTL)
+	}
+
+	return repo, closeFn, nil
+}
@@ -1,19 +1,348 @@
 package repository
 
 import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
 
+	"github.com/shindesatish/titanic-service/internal/app/query"
+	"github.com/shindesatish/titanic-service/internal/app/validation"
 	"github.com/shindesatish/titanic-service/pkg/model"
 )
 
 type Repository interface {
 	GetAllPassengers() ([]model.Passenger, error)
+	ForEachPassenger(fn func(*model.Passenger) error) error
+	// ForEachMatchingPassenger is ForEachPassenger restricted to the
+	// passengers matching where; a nil where matches every passenger.
+	ForEachMatchingPassenger(where *query.Expr, fn func(*model.Passenger) error) error
 	GetPassengerByID(passengerID uint) (*model.Passenger, error)
+	// GetPassengersByIDs returns the passengers with any of the given
+	// PassengerIds in dataset order; IDs without a passenger are skipped.
+	GetPassengersByIDs(passengerIDs []uint) ([]model.Passenger, error)
 	GetPassengerAttributes(passengerID uint, attributes []string) (*model.Passenger, error)
 	GetFareHistogram() (map[string]int, error)
+	DataVersion() (string, error)
+	// ImportPassengers fails with ErrVersionMismatch, changing nothing,
+	// unless precondition accepts the data version the import applies to.
+	ImportPassengers(passengers []model.Passenger, mode ImportMode, dryRun bool, precondition Precondition) (*ImportResult, error)
+	ValidateDataset() (*validation.Report, error)
+	// CrossTabulate groups passengers by the rows and cols attributes,
+	// counting them and summing the numeric attribute value (none when
+	// empty). withValues also collects the individual values.
+	CrossTabulate(rows, cols, value string, withValues bool) ([]model.CrossTabCell, error)
+	// SearchPassengers returns up to limit passengers matching query in one
+	// of the model.SearchModes, best first.
+	SearchPassengers(query, mode string, limit int) ([]model.SearchHit, error)
+}
+
+// ImportMode controls how imported passengers are merged into the dataset.
+type ImportMode string
+
+const (
+	// ImportUpsert inserts new passengers and overwrites existing ones that
+	// share a PassengerId.
+	ImportUpsert ImportMode = "upsert"
+	// ImportReplace discards the existing dataset in favour of the import.
+	ImportReplace ImportMode = "replace"
+)
+
+// Precondition decides from the current data version whether an import may
+// proceed, e.g. by comparing it with an If-Match header. A nil Precondition
+// accepts every version.
+type Precondition func(version string) bool
+
+// check returns ErrVersionMismatch unless p accepts version.
+func (p Precondition) check(version string) error {
+	if p != nil && !p(version) {
+		return fmt.Errorf("%w (now %s)", ErrVersionMismatch, version)
+	}
+	return nil
+}
+
+// ImportResult summarizes the changes an import made, or would have made
+// for a dry run.
+type ImportResult struct {
+	Mode     ImportMode `json:"mode"`
+	DryRun   bool       `json:"dry_run"`
+	Received int        `json:"received"`
+	Inserted int        `json:"inserted"`
+	Updated  int        `json:"updated"`
+	Deleted  int        `json:"deleted"`
+}
+
+// ErrPassengerNotFound is returned when no passenger has the requested
+// PassengerId.
+var ErrPassengerNotFound = errors.New("passenger not found")
+
+// ErrVersionMismatch is returned by an import whose Precondition rejects the
+// data version it would have applied to.
+var ErrVersionMismatch = errors.New("dataset has been modified")
+
+// ErrRowsSkipped is returned by an upsert import into a CSV dataset while
+// lenient mode skips some of its rows: rewriting the file from the valid
+// passengers would silently delete the skipped rows.
+var ErrRowsSkipped = errors.New("dataset has invalid rows that lenient mode skips; fix them or import with mode=replace")
+
+// ErrStopIteration can be returned from a ForEachPassenger callback to end the
+// iteration early without ForEachPassenger reporting an error.
+var ErrStopIteration = errors.New("stop iteration")
+
+// skippedRows remembers the rows a lenient load passed over on its last
+// complete pass through the dataset.
+type skippedRows struct {
+	mu   sync.Mutex
+	rows []validation.RowError
+}
+
+// SkippedRows returns the problems that made the last lenient load skip
+// rows. It is always empty in strict mode.
+func (s *skippedRows) SkippedRows() []validation.RowError {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]validation.RowError(nil), s.rows...)
+}
+
+func (s *skippedRows) setSkippedRows(rows []validation.RowError) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rows = rows
+}
+
+// SkippedRows makes a pass over the dataset of a lenient repo and returns
+// the problems that made it skip rows. It is empty for strict repositories.
+func SkippedRows(repo Repository) ([]validation.RowError, error) {
+	if cached, ok := repo.(*CachingRepository); ok {
+		repo = cached.Repository
+	}
+	var skipped *skippedRows
+	switch r := repo.(type) {
+	case *CSVRepository:
+		if r.Lenient {
+			skipped = &r.skippedRows
+		}
+	case *SQLiteRepository:
+		if r.Lenient {
+			skipped = &r.skippedRows
+		}
+	}
+	if skipped == nil {
+		return nil, nil
+	}
+
+	if err := repo.ForEachPassenger(func(*model.Passenger) error { return nil }); err != nil {
+		return nil, err
+	}
+	return skipped.SkippedRows(), nil
 }
 
 // JoinAttributes joins a list of attributes into a comma-separated string
 func JoinAttributes(attributes []string) string {
 	return strings.Join(attributes, ", ")
 }
+
+// mergePassengers applies an import to the existing passengers and returns
+// the resulting dataset. Upserts keep the existing order and append new
+// passengers; replacements take the import order.
+func mergePassengers(existing, imported []model.Passenger, mode ImportMode) ([]model.Passenger, *ImportResult) {
+	result := &ImportResult{Mode: mode, Received: len(imported)}
+
+	index := make(map[int]int, len(existing))
+	for i, passenger := range existing {
+		index[passenger.PassengerID] = i
+	}
+
+	if mode == ImportReplace {
+		kept := make(map[int]bool, len(imported))
+		for _, passenger := range imported {
+			if _, ok := index[passenger.PassengerID]; ok {
+				if !kept[passenger.PassengerID] {
+					result.Updated++
+				}
+			} else if !kept[passenger.PassengerID] {
+				result.Inserted++
+			}
+			kept[passenger.PassengerID] = true
+		}
+		result.Deleted = len(existing) - result.Updated
+		return append([]model.Passenger(nil), imported...), result
+	}
+
+	// A PassengerId imported twice counts once, as its first write
+	merged := append([]model.Passenger(nil), existing...)
+	written := make(map[int]bool, len(imported))
+	for _, passenger := range imported {
+		if i, ok := index[passenger.PassengerID]; ok {
+			merged[i] = passenger
+			if !written[passenger.PassengerID] {
+				result.Updated++
+			}
+		} else {
+			index[passenger.PassengerID] = len(merged)
+			merged = append(merged, passenger)
+			result.Inserted++
+		}
+		written[passenger.PassengerID] = true
+	}
+	return merged, result
+}
+
+// histogramPercentiles bound the buckets of FareHistogram.
+var histogramPercentiles = []float64{25, 50, 75, 90, 95, 99}
+
+// HistogramBuckets returns the keys of a FareHistogram in ascending
+// percentile order. Empty buckets are left out of the histogram itself.
+func HistogramBuckets() []string {
+	buckets := make([]string, len(histogramPercentiles))
+	for i, p := range histogramPercentiles {
+		buckets[i] = fmt.Sprintf("%.2f%%", p)
+	}
+	return buckets
+}
+
+// FareHistogram counts fares into buckets bounded by the 25th, 50th, 75th,
+// 90th, 95th and 99th percentiles. fares is sorted in place.
+func FareHistogram(fares []float64) map[string]int {
+	fareHistogram := make(map[string]int)
+	if len(fares) == 0 {
+		return fareHistogram
+	}
+
+	// Sort the fares in ascending order
+	sort.Float64s(fares)
+
+	// Count each fare into the lowest percentile bucket that holds it
+	buckets := HistogramBuckets()
+	for _, fare := range fares {
+		for i, p := range histogramPercentiles {
+			idx := int(float64(len(fares)-1) * (p / 100.0))
+			if fare <= fares[idx] {
+				fareHistogram[buckets[i]]++
+				break
+			}
+		}
+	}
+
+	return fareHistogram
+}
+
+// forEachMatching calls fn for each passenger visited by forEach that
+// matches where. Backends that cannot evaluate where themselves use it.
+func forEachMatching(forEach func(func(*model.Passenger) error) error, where *query.Expr, fn func(*model.Passenger) error) error {
+	return forEach(func(passenger *model.Passenger) error {
+		if where != nil && !where.Match(passenger) {
+			return nil
+		}
+		return fn(passenger)
+	})
+}
+
+// getPassengersByIDs collects the passengers with the given PassengerIds in
+// a single pass over forEach, stopping once every ID has been seen.
+func getPassengersByIDs(forEach func(func(*model.Passenger) error) error, passengerIDs []uint) ([]model.Passenger, error) {
+	wanted := make(map[int]bool, len(passengerIDs))
+	for _, id := range passengerIDs {
+		wanted[int(id)] = true
+	}
+
+	passengers := []model.Passenger{}
+	err := forEach(func(passenger *model.Passenger) error {
+		if !wanted[passenger.PassengerID] {
+			return nil
+		}
+		passengers = append(passengers, *passenger)
+		delete(wanted, passenger.PassengerID)
+		if len(wanted) == 0 {
+			return ErrStopIteration
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return passengers, nil
+}
+
+// findPassenger returns the passenger with passengerID from a pass over
+// forEach, which stops at the passenger.
+func findPassenger(forEach func(func(*model.Passenger) error) error, passengerID uint) (*model.Passenger, error) {
+	passengers, err := getPassengersByIDs(forEach, []uint{passengerID})
+	if err != nil {
+		return nil, err
+	}
+	if len(passengers) == 0 {
+		return nil, fmt.Errorf("%w with ID %d", ErrPassengerNotFound, passengerID)
+	}
+	return &passengers[0], nil
+}
+
+// fareHistogram buckets the fares of the passengers visited by forEach.
+func fareHistogram(forEach func(func(*model.Passenger) error) error) (map[string]int, error) {
+	var fares []float64
+	err := forEach(func(passenger *model.Passenger) error {
+		fares = append(fares, passenger.Fare)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return FareHistogram(fares), nil
+}
+
+// crossTabulate builds the cells of a cross-tabulation by visiting every
+// passenger. Backends without a faster way to group use it directly.
+func crossTabulate(forEach func(func(*model.Passenger) error) error, rows, cols, value string, withValues bool) ([]model.CrossTabCell, error) {
+	cells := make(map[[2]string]*model.CrossTabCell)
+	var order [][2]string
+	err := forEach(func(p *model.Passenger) error {
+		row, err := p.Attribute(rows)
+		if err != nil {
+			return err
+		}
+		col, err := p.Attribute(cols)
+		if err != nil {
+			return err
+		}
+		key := [2]string{row, col}
+		cell, ok := cells[key]
+		if !ok {
+			cell = &model.CrossTabCell{Row: row, Col: col}
+			cells[key] = cell
+			order = append(order, key)
+		}
+		cell.Count++
+
+		if value == "" {
+			return nil
+		}
+		raw, err := p.Attribute(value)
+		if err != nil {
+			return err
+		}
+		if raw == "" {
+			return nil
+		}
+		number, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return fmt.Errorf("%s is not numeric: %q", value, raw)
+		}
+		cell.ValueCount++
+		cell.Sum += number
+		if withValues {
+			cell.Values = append(cell.Values, number)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]model.CrossTabCell, 0, len(order))
+	for _, key := range order {
+		result = append(result, *cells[key])
+	}
+	return result, nil
+}
@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// copyDatastore copies titanic.csv and titanic.db into a temporary
+// directory, so the tests never touch datastore/, and returns it.
+func copyDatastore(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	for _, name := range []string{"titanic.csv", "titanic.db"} {
+		data, err := os.ReadFile(filepath.Join("..", "..", "..", "datastore", name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+// openSQLite opens the titanic.db in dir.
+func openSQLite(t *testing.T, dir string) *SQLiteRepository {
+	t.Helper()
+	db, err := sql.Open("sqlite3", filepath.Join(dir, "titanic.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return NewSQLiteRepository(db)
+}
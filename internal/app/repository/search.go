@@ -0,0 +1,32 @@
+// internal/app/repository/search.go
+package repository
+
+import (
+	"sync"
+
+	"github.com/shindesatish/titanic-service/internal/app/search"
+	"github.com/shindesatish/titanic-service/pkg/model"
+)
+
+// searchCache holds an in-memory search index together with the data
+// version it was built from, rebuilding it when the version moves on.
+type searchCache struct {
+	mu      sync.Mutex
+	version string
+	index   *search.Index
+}
+
+func (c *searchCache) search(version string, load func() ([]model.Passenger, error), query, mode string, limit int) ([]model.SearchHit, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.index == nil || c.version != version {
+		passengers, err := load()
+		if err != nil {
+			return nil, err
+		}
+		c.index = search.NewIndex(passengers)
+		c.version = version
+	}
+	return c.index.Search(query, mode, limit), nil
+}
@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/shindesatish/titanic-service/pkg/model"
+)
+
+var searchQueries = []string{
+	"andersson",
+	"mr john",
+	"sage",
+	"pc 17599",
+	"c23",
+	"miss mary",
+	"ca 2343",
+	"w c",
+	"mrs (",
+	"xyzzy",
+}
+
+func searchIDs(hits []model.SearchHit) []int {
+	ids := []int{}
+	for _, hit := range hits {
+		ids = append(ids, hit.Passenger.PassengerID)
+	}
+	return ids
+}
+
+// TestSearchPassengersBackends checks that both backends find the same
+// passengers. Without FTS5 they share the in-memory index, so the order is
+// the same too; with it, SQLite ranks token searches by bm25 instead.
+func TestSearchPassengersBackends(t *testing.T) {
+	dir := copyDatastore(t)
+	csvRepo := NewCSVRepository(filepath.Join(dir, "titanic.csv"))
+	sqliteRepo := openSQLite(t, dir)
+
+	for _, mode := range model.SearchModes {
+		for _, query := range searchQueries {
+			csvHits, err := csvRepo.SearchPassengers(query, mode, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			sqliteHits, err := sqliteRepo.SearchPassengers(query, mode, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			csvIDs, sqliteIDs := searchIDs(csvHits), searchIDs(sqliteHits)
+			if mode != model.SearchSubstring && query != "xyzzy" && len(csvIDs) == 0 {
+				t.Errorf("%s search for %q found nobody", mode, query)
+			}
+
+			if mode != model.SearchToken || !fts5 {
+				if !reflect.DeepEqual(sqliteHits, csvHits) {
+					t.Errorf("%s search for %q: SQLite found %v, CSV %v", mode, query, sqliteIDs, csvIDs)
+				}
+				continue
+			}
+
+			for i := 1; i < len(sqliteHits); i++ {
+				if sqliteHits[i].Score > sqliteHits[i-1].Score {
+					t.Errorf("token search for %q: hit %d scores %v, above %v", query, i, sqliteHits[i].Score, sqliteHits[i-1].Score)
+				}
+			}
+			sort.Ints(csvIDs)
+			sort.Ints(sqliteIDs)
+			if !reflect.DeepEqual(sqliteIDs, csvIDs) {
+				t.Errorf("token search for %q: SQLite found %v, CSV %v", query, sqliteIDs, csvIDs)
+			}
+		}
+	}
+
+	if fts5 && (sqliteRepo.fts.unavailable || sqliteRepo.fts.conn == nil) {
+		t.Errorf("token searches did not use FTS5")
+	}
+	if !fts5 && !sqliteRepo.fts.unavailable {
+		t.Errorf("FTS5 is not compiled in but the index is not marked unavailable")
+	}
+}
+
+func TestSearchPassengersLimit(t *testing.T) {
+	dir := copyDatastore(t)
+	for name, repo := range map[string]Repository{
+		"csv":    NewCSVRepository(filepath.Join(dir, "titanic.csv")),
+		"sqlite": openSQLite(t, dir),
+	} {
+		all, err := repo.SearchPassengers("mr", model.SearchToken, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		limited, err := repo.SearchPassengers("mr", model.SearchToken, 5)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(all) < 500 || !reflect.DeepEqual(limited, all[:5]) {
+			t.Errorf("%s: got %d hits, and %v for the first 5", name, len(all), searchIDs(limited))
+		}
+	}
+}
+
+func TestSearchPassengersDiacritics(t *testing.T) {
+	dir := copyDatastore(t)
+	passenger := model.Passenger{PassengerID: 1000, Survived: 1, Pclass: 2, Name: "Åberg, Miss. Hélène", Sex: "female", Ticket: "X 1", Fare: 10, Embarked: "S"}
+	for name, repo := range map[string]Repository{
+		"csv":    NewCSVRepository(filepath.Join(dir, "titanic.csv")),
+		"sqlite": openSQLite(t, dir),
+	} {
+		if _, err := repo.ImportPassengers([]model.Passenger{passenger}, ImportUpsert, false, nil); err != nil {
+			t.Fatal(err)
+		}
+		// Only the accented spellings find her
+		for query, want := range map[string]bool{"hélène åberg": true, "hél": true, "helene": false, "aberg": false} {
+			hits, err := repo.SearchPassengers(query, model.SearchToken, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			found := false
+			for _, hit := range hits {
+				found = found || hit.Passenger.PassengerID == 1000
+			}
+			if found != want {
+				t.Errorf("%s: token search for %q found %v", name, query, searchIDs(hits))
+			}
+		}
+	}
+}
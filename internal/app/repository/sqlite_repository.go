@@ -3,109 +3,330 @@ package repository
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
-	"sort"
+	"strings"
+	"sync"
 
+	"github.com/shindesatish/titanic-service/internal/app/query"
+	"github.com/shindesatish/titanic-service/internal/app/validation"
 	"github.com/shindesatish/titanic-service/pkg/model"
 )
 
 type SQLiteRepository struct {
 	DB *sql.DB
+
+	// Lenient skips rows that fail validation instead of failing the whole
+	// load; the skipped rows are available from SkippedRows.
+	Lenient bool
+	skippedRows
+
+	// fts and searches back SearchPassengers.
+	fts      ftsIndex
+	searches searchCache
+
+	// writeMu serializes imports, so that of two concurrent conditional
+	// imports the second fails its precondition rather than, possibly, the
+	// lock upgrade of its transaction.
+	writeMu sync.Mutex
 }
 
+// rawColumns selects a titanic row as text, preceded by its rowid, for
+// validation.
+const rawColumns = "rowid, PassengerId, Survived, Pclass, Name, Sex, Age, SibSp, Parch, Ticket, Fare, Cabin, Embarked"
+
 func NewSQLiteRepository(db *sql.DB) *SQLiteRepository {
 	return &SQLiteRepository{DB: db}
 }
 
 func (r *SQLiteRepository) GetAllPassengers() ([]model.Passenger, error) {
-	rows, err := r.DB.Query("SELECT * FROM titanic")
+	var passengers []model.Passenger
+	err := r.ForEachPassenger(func(passenger *model.Passenger) error {
+		passengers = append(passengers, *passenger)
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to query passengers: %v", err)
+		return nil, err
+	}
+
+	return passengers, nil
+}
+
+// ForEachPassenger walks the query result with rows.Next and calls fn for
+// each passenger.
+func (r *SQLiteRepository) ForEachPassenger(fn func(*model.Passenger) error) error {
+	if r.Lenient {
+		return r.forEachValidPassenger(fn)
+	}
+
+	return r.forEachRow(fn, "SELECT * FROM titanic")
+}
+
+// ForEachMatchingPassenger runs where as a WHERE clause when it only names
+// columns of the titanic table, and filters a full pass over the
+// passengers for derived attributes or in lenient mode.
+func (r *SQLiteRepository) ForEachMatchingPassenger(where *query.Expr, fn func(*model.Passenger) error) error {
+	if where == nil {
+		return r.ForEachPassenger(fn)
+	}
+	clause, args, ok := where.SQL(tableColumn)
+	if r.Lenient || !ok {
+		return forEachMatching(r.ForEachPassenger, where, fn)
+	}
+	return r.forEachRow(fn, "SELECT * FROM titanic WHERE "+clause, args...)
+}
+
+// forEachRow calls fn for each passenger selected by statement.
+func (r *SQLiteRepository) forEachRow(fn func(*model.Passenger) error, statement string, args ...interface{}) error {
+	rows, err := r.DB.Query(statement, args...)
+	if err != nil {
+		return fmt.Errorf("failed to query passengers: %v", err)
 	}
 	defer rows.Close()
 
-	var passengers []model.Passenger
 	for rows.Next() {
-		var passenger model.Passenger
-		var cabin, embarked sql.NullString
-		var age sql.NullFloat64
-
-		err := rows.Scan(
-			&passenger.PassengerID, &passenger.Survived, &passenger.Pclass, &passenger.Name,
-			&passenger.Sex, &age, &passenger.SibSp, &passenger.Parch,
-			&passenger.Ticket, &passenger.Fare, &cabin, &embarked,
-		)
+		passenger, err := scanPassenger(rows)
 		if err != nil {
-			return nil, fmt.Errorf("failed to scan passenger row: %v", err)
+			return fmt.Errorf("failed to scan passenger row: %v", err)
 		}
-		passenger.Age = age.Float64
-		passenger.Cabin = cabin.String
-		passenger.Embarked = embarked.String
-		passengers = append(passengers, passenger)
+		if err := fn(passenger); err != nil {
+			if errors.Is(err, ErrStopIteration) {
+				return nil
+			}
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to iterate passenger rows: %v", err)
 	}
 
-	return passengers, nil
+	return nil
 }
 
-func (r *SQLiteRepository) GetPassengerByID(passengerID uint) (*model.Passenger, error) {
-	row := r.DB.QueryRow("SELECT * FROM titanic WHERE PassengerID = ?", passengerID)
-	var passenger model.Passenger
-	var cabin, embarked sql.NullString
-	var age sql.NullFloat64
-	err := row.Scan(
-		&passenger.PassengerID, &passenger.Survived, &passenger.Pclass, &passenger.Name,
-		&passenger.Sex, &age, &passenger.SibSp, &passenger.Parch,
-		&passenger.Ticket, &passenger.Fare, &cabin, &embarked,
-	)
+// forEachValidPassenger is the lenient ForEachPassenger: rows are read as
+// text and run through a validation.Validator, and invalid ones skipped.
+func (r *SQLiteRepository) forEachValidPassenger(fn func(*model.Passenger) error) error {
+	validator := validation.NewValidator("titanic")
+	err := r.forEachRawRow(func(rowID int, record []string) error {
+		passenger, _ := validator.CheckRecord(rowID, record)
+		if passenger == nil {
+			return nil
+		}
+		return fn(passenger)
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to get passenger by ID: %v", err)
+		if errors.Is(err, ErrStopIteration) {
+			return nil
+		}
+		return err
 	}
-	passenger.Age = age.Float64
-	passenger.Cabin = cabin.String
-	passenger.Embarked = embarked.String
-	return &passenger, nil
+
+	r.setSkippedRows(validator.Report().Errors)
+	return nil
 }
 
-func (r *SQLiteRepository) GetPassengerAttributes(passengerID uint, attributes []string) (*model.Passenger, error) {
-	// Ensure attributes are not empty to prevent SQL injection
-	if len(attributes) == 0 {
-		return nil, fmt.Errorf("attributes cannot be empty")
+// CrossTabulate groups passengers in SQL when every attribute is a column
+// of the titanic table, and falls back to a pass over the passengers for
+// derived attributes or in lenient mode.
+func (r *SQLiteRepository) CrossTabulate(rows, cols, value string, withValues bool) ([]model.CrossTabCell, error) {
+	rowColumn, colColumn, valueColumn := tableColumn(rows), tableColumn(cols), tableColumn(value)
+	if r.Lenient || rowColumn == "" || colColumn == "" || (value != "" && valueColumn == "") {
+		return crossTabulate(r.ForEachPassenger, rows, cols, value, withValues)
 	}
 
-	// Construct SQL query with prepared statement
-	query := "SELECT " + JoinAttributes(attributes) + " FROM titanic WHERE PassengerID = ?"
-	stmt, err := r.DB.Prepare(query)
+	number := "NULL"
+	if valueColumn != "" {
+		number = fmt.Sprintf("CASE WHEN %[1]s IS NULL OR %[1]s = '' THEN NULL ELSE CAST(%[1]s AS REAL) END", valueColumn)
+	}
+	if withValues {
+		return r.crossTabulateValues(rowColumn, colColumn, number)
+	}
+
+	query := fmt.Sprintf("SELECT COALESCE(%s, ''), COALESCE(%s, ''), COUNT(*), COUNT(%[3]s), TOTAL(%[3]s) FROM titanic GROUP BY 1, 2", rowColumn, colColumn, number)
+	result, err := r.DB.Query(query)
 	if err != nil {
-		return nil, fmt.Errorf("error preparing SQL statement: %v", err)
+		return nil, fmt.Errorf("failed to query cross-tabulation: %v", err)
 	}
-	defer stmt.Close()
+	defer result.Close()
+
+	var cells []model.CrossTabCell
+	for result.Next() {
+		var cell model.CrossTabCell
+		if err := result.Scan(&cell.Row, &cell.Col, &cell.Count, &cell.ValueCount, &cell.Sum); err != nil {
+			return nil, fmt.Errorf("failed to scan cross-tabulation row: %v", err)
+		}
+		cells = append(cells, cell)
+	}
+	if err := result.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate cross-tabulation rows: %v", err)
+	}
+	return cells, nil
+}
 
-	// Execute the prepared statement
-	row := stmt.QueryRow(passengerID)
+// crossTabulateValues is CrossTabulate for medians, which SQLite cannot
+// compute, so only the three columns involved are read and grouped here.
+func (r *SQLiteRepository) crossTabulateValues(rowColumn, colColumn, number string) ([]model.CrossTabCell, error) {
+	query := fmt.Sprintf("SELECT COALESCE(%s, ''), COALESCE(%s, ''), %s FROM titanic", rowColumn, colColumn, number)
+	result, err := r.DB.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query cross-tabulation: %v", err)
+	}
+	defer result.Close()
 
-	// Create a map to store attribute values
-	attributeValues := make(map[string]interface{})
+	index := make(map[[2]string]int)
+	var cells []model.CrossTabCell
+	for result.Next() {
+		var row, col string
+		var value sql.NullFloat64
+		if err := result.Scan(&row, &col, &value); err != nil {
+			return nil, fmt.Errorf("failed to scan cross-tabulation row: %v", err)
+		}
+		i, ok := index[[2]string{row, col}]
+		if !ok {
+			i = len(cells)
+			index[[2]string{row, col}] = i
+			cells = append(cells, model.CrossTabCell{Row: row, Col: col})
+		}
+		cells[i].Count++
+		if value.Valid {
+			cells[i].ValueCount++
+			cells[i].Sum += value.Float64
+			cells[i].Values = append(cells[i].Values, value.Float64)
+		}
+	}
+	if err := result.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate cross-tabulation rows: %v", err)
+	}
+	return cells, nil
+}
 
-	// Scan the row and populate the attributeValues map
-	for _, attr := range attributes {
-		var value interface{}
-		err := row.Scan(&value)
-		if err != nil {
-			return nil, fmt.Errorf("error scanning attribute %s: %v", attr, err)
+// tableColumn returns the titanic table column for attribute, or "" for
+// derived attributes.
+func tableColumn(attribute string) string {
+	for _, column := range model.CSVHeader {
+		if strings.EqualFold(column, attribute) {
+			return column
 		}
-		attributeValues[attr] = value
 	}
+	return ""
+}
+
+// ValidateDataset checks every row of the titanic table and reports all
+// problems. Line numbers in the report are rowids.
+func (r *SQLiteRepository) ValidateDataset() (*validation.Report, error) {
+	validator := validation.NewValidator("titanic")
+	err := r.forEachRawRow(func(rowID int, record []string) error {
+		validator.CheckRecord(rowID, record)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return validator.Report(), nil
+}
 
-	// Convert the attributeValues map to a Passenger instance
-	passenger, err := ConvertAttributesToPassenger(attributeValues)
+// forEachRawRow calls fn with the rowid and text columns of every row, in
+// CSVHeader order with NULL read as blank.
+func (r *SQLiteRepository) forEachRawRow(fn func(rowID int, record []string) error) error {
+	rows, err := r.DB.Query("SELECT " + rawColumns + " FROM titanic")
 	if err != nil {
-		return nil, fmt.Errorf("error converting attributes to Passenger: %v", err)
+		return fmt.Errorf("failed to query passengers: %v", err)
+	}
+	defer rows.Close()
+
+	values := make([]sql.NullString, len(model.CSVHeader))
+	dest := make([]interface{}, len(values)+1)
+	var rowID int
+	dest[0] = &rowID
+	for i := range values {
+		dest[i+1] = &values[i]
+	}
+
+	for rows.Next() {
+		if err := rows.Scan(dest...); err != nil {
+			return fmt.Errorf("failed to scan passenger row: %v", err)
+		}
+		record := make([]string, len(values))
+		for i, value := range values {
+			record[i] = value.String
+		}
+		if err := fn(rowID, record); err != nil {
+			return err
+		}
 	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to iterate passenger rows: %v", err)
+	}
+	return nil
+}
 
+// GetPassengerByID selects the passenger by PassengerID. In lenient mode it
+// scans the validated rows instead, so an invalid row is skipped rather
+// than failing the lookup.
+func (r *SQLiteRepository) GetPassengerByID(passengerID uint) (*model.Passenger, error) {
+	if r.Lenient {
+		return findPassenger(r.ForEachPassenger, passengerID)
+	}
+	row := r.DB.QueryRow("SELECT * FROM titanic WHERE PassengerID = ?", passengerID)
+	passenger, err := scanPassenger(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w with ID %d", ErrPassengerNotFound, passengerID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get passenger by ID: %v", err)
+	}
 	return passenger, nil
 }
 
+// GetPassengersByIDs selects the passengers with a single IN query, or a
+// single pass over the validated rows in lenient mode.
+func (r *SQLiteRepository) GetPassengersByIDs(passengerIDs []uint) ([]model.Passenger, error) {
+	if r.Lenient {
+		return getPassengersByIDs(r.ForEachPassenger, passengerIDs)
+	}
+	passengers := []model.Passenger{}
+	if len(passengerIDs) == 0 {
+		return passengers, nil
+	}
+
+	placeholders := make([]string, len(passengerIDs))
+	args := make([]interface{}, len(passengerIDs))
+	for i, id := range passengerIDs {
+		placeholders[i] = "?"
+		args[i] = id
+	}
+	statement := "SELECT * FROM titanic WHERE PassengerID IN (" + strings.Join(placeholders, ", ") + ")"
+	err := r.forEachRow(func(passenger *model.Passenger) error {
+		passengers = append(passengers, *passenger)
+		return nil
+	}, statement, args...)
+	if err != nil {
+		return nil, err
+	}
+	return passengers, nil
+}
+
+func (r *SQLiteRepository) GetPassengerAttributes(passengerID uint, attributes []string) (*model.Passenger, error) {
+	if len(attributes) == 0 {
+		return nil, fmt.Errorf("attributes cannot be empty")
+	}
+
+	// Every column is stored as TEXT, so scan the full typed row and project
+	// it rather than selecting the attributes as raw values
+	passenger, err := r.GetPassengerByID(passengerID)
+	if err != nil {
+		return nil, err
+	}
+
+	return passenger.Project(attributes)
+}
+
+// GetFareHistogram reads the Fare column, or the validated rows in lenient
+// mode.
 func (r *SQLiteRepository) GetFareHistogram() (map[string]int, error) {
+	if r.Lenient {
+		return fareHistogram(r.ForEachPassenger)
+	}
 	rows, err := r.DB.Query("SELECT Fare FROM titanic")
 	if err != nil {
 		return nil, fmt.Errorf("failed to query fares: %v", err)
@@ -122,29 +343,165 @@ func (r *SQLiteRepository) GetFareHistogram() (map[string]int, error) {
 		fares = append(fares, fare)
 	}
 
-	// Sort the fares in ascending order
-	sort.Float64s(fares)
+	return FareHistogram(fares), nil
+}
+
+// DataVersion returns the database's user_version counter. Only writes made
+// through ImportPassengers, i.e. the service's imports and titanic import,
+// bump it. Changes made outside the service, e.g. with the sqlite3 shell or
+// by rebuilding titanic.db, leave it alone, so ETags derived from it go stale
+// until user_version is raised by hand (PRAGMA user_version = <n>).
+func (r *SQLiteRepository) DataVersion() (string, error) {
+	var version int64
+	if err := r.DB.QueryRow("PRAGMA user_version").Scan(&version); err != nil {
+		return "", fmt.Errorf("failed to read data version: %v", err)
+	}
+	return fmt.Sprintf("v%d", version), nil
+}
+
+// ImportPassengers applies the import in a single transaction and bumps the
+// data version. The version the precondition checks is read and bumped
+// first, in the same transaction as the writes, so a concurrent import
+// either fails or waits for this one to commit. A dry run performs the same statements and rolls them back,
+// so the reported counts are exact.
+func (r *SQLiteRepository) ImportPassengers(passengers []model.Passenger, mode ImportMode, dryRun bool, precondition Precondition) (*ImportResult, error) {
+	r.writeMu.Lock()
+	defer r.writeMu.Unlock()
+
+	tx, err := r.DB.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin import transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	version, err := bumpDataVersion(tx)
+	if err != nil {
+		return nil, err
+	}
+	if err := precondition.check(fmt.Sprintf("v%d", version)); err != nil {
+		return nil, err
+	}
+
+	existing := make(map[string]bool)
+	rows, err := tx.Query("SELECT PassengerId FROM titanic")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query passenger IDs: %v", err)
+	}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan passenger ID: %v", err)
+		}
+		existing[id] = true
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate passenger IDs: %v", err)
+	}
+
+	result := &ImportResult{Mode: mode, DryRun: dryRun, Received: len(passengers)}
+	if mode == ImportReplace {
+		if _, err := tx.Exec("DELETE FROM titanic"); err != nil {
+			return nil, fmt.Errorf("failed to clear passengers: %v", err)
+		}
+	}
 
-	// Calculate percentiles
-	percentiles := []float64{25, 50, 75, 90, 95, 99}
-	percentileValues := make(map[string]float64)
-	for _, p := range percentiles {
-		idx := int(float64(len(fares)-1) * (p / 100.0))
-		percentileValues[fmt.Sprintf("%.2f%%", p)] = fares[idx]
+	insert, err := tx.Prepare("INSERT INTO titanic (PassengerId, Survived, Pclass, Name, Sex, Age, SibSp, Parch, Ticket, Fare, Cabin, Embarked) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)")
+	if err != nil {
+		return nil, fmt.Errorf("error preparing SQL statement: %v", err)
 	}
+	defer insert.Close()
+	update, err := tx.Prepare("UPDATE titanic SET PassengerId = ?, Survived = ?, Pclass = ?, Name = ?, Sex = ?, Age = ?, SibSp = ?, Parch = ?, Ticket = ?, Fare = ?, Cabin = ?, Embarked = ? WHERE PassengerId = ?")
+	if err != nil {
+		return nil, fmt.Errorf("error preparing SQL statement: %v", err)
+	}
+	defer update.Close()
+
+	written := make(map[string]bool, len(passengers))
+	for i := range passengers {
+		values := passengerValues(&passengers[i])
+		id := values[0].(string)
 
-	// Create histogram buckets and count occurrences
-	fareHistogram := make(map[string]int)
-	for _, fare := range fares {
-		for label, pValue := range percentileValues {
-			if fare <= pValue {
-				fareHistogram[label]++
-				break
+		if (mode == ImportUpsert && existing[id]) || written[id] {
+			if _, err := update.Exec(append(values, id)...); err != nil {
+				return nil, fmt.Errorf("failed to update passenger %s: %v", id, err)
 			}
+		} else if _, err := insert.Exec(values...); err != nil {
+			return nil, fmt.Errorf("failed to insert passenger %s: %v", id, err)
+		}
+
+		if !written[id] {
+			if existing[id] {
+				result.Updated++
+			} else {
+				result.Inserted++
+			}
+		}
+		written[id] = true
+	}
+	if mode == ImportReplace {
+		result.Deleted = len(existing) - result.Updated
+	}
+
+	if dryRun {
+		return result, nil
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit import: %v", err)
+	}
+
+	return result, nil
+}
+
+// bumpDataVersion increments PRAGMA user_version within tx and returns the
+// version it replaced.
+func bumpDataVersion(tx *sql.Tx) (int64, error) {
+	var version int64
+	if err := tx.QueryRow("PRAGMA user_version").Scan(&version); err != nil {
+		return 0, fmt.Errorf("failed to read data version: %v", err)
+	}
+	// PRAGMA does not accept bound parameters
+	if _, err := tx.Exec(fmt.Sprintf("PRAGMA user_version = %d", version+1)); err != nil {
+		return 0, fmt.Errorf("failed to bump data version: %v", err)
+	}
+	return version, nil
+}
+
+// passengerValues returns the column values of a passenger for the titanic
+// table, whose columns are all TEXT. Blank Age, Cabin and Embarked are
+// stored as NULL, matching the original import.
+func passengerValues(p *model.Passenger) []interface{} {
+	record := p.CSVRecord()
+	values := make([]interface{}, len(record))
+	for i, value := range record {
+		values[i] = value
+	}
+	for _, column := range []int{5, 10, 11} {
+		if record[column] == "" {
+			values[column] = nil
 		}
 	}
+	return values
+}
+
+// scanPassenger scans a full titanic row, mapping NULL Cabin and Embarked
+// to empty strings and NULL Age to a null NullFloat64.
+func scanPassenger(row interface{ Scan(dest ...interface{}) error }) (*model.Passenger, error) {
+	var passenger model.Passenger
+	var cabin, embarked sql.NullString
 
-	return fareHistogram, nil
+	err := row.Scan(
+		&passenger.PassengerID, &passenger.Survived, &passenger.Pclass, &passenger.Name,
+		&passenger.Sex, &passenger.Age, &passenger.SibSp, &passenger.Parch,
+		&passenger.Ticket, &passenger.Fare, &cabin, &embarked,
+	)
+	if err != nil {
+		return nil, err
+	}
+	passenger.Cabin = cabin.String
+	passenger.Embarked = embarked.String
+	return &passenger, nil
 }
 
 // ConvertAttributesToPassenger converts a map of attribute values to a Passenger instance
@@ -165,7 +522,7 @@ func ConvertAttributesToPassenger(attributeValues map[string]interface{}) (*mode
             passenger.Sex = value.(string)
         case "Age":
             if value != nil {
-                passenger.Age = value.(float64)
+                passenger.Age = model.NewNullFloat64(value.(float64))
             }
         case "SibSp":
             passenger.SibSp = value.(int)
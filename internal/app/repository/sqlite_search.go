@@ -0,0 +1,140 @@
+// internal/app/repository/sqlite_search.go
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/shindesatish/titanic-service/internal/app/search"
+	"github.com/shindesatish/titanic-service/pkg/model"
+)
+
+// ftsIndex is a temporary FTS5 table over the titanic table. Temporary
+// tables belong to one connection, so it pins its own; nothing is written
+// to the database file. FTS5 is only compiled in with the sqlite_fts5
+// build tag, otherwise the index reports itself unavailable.
+type ftsIndex struct {
+	mu          sync.Mutex
+	conn        *sql.Conn
+	version     string
+	unavailable bool
+}
+
+// SearchPassengers ranks token searches with FTS5 and bm25 when available.
+// Substring, fuzzy and lenient searches, and token searches without FTS5,
+// use the same in-memory index as the CSV backend. Both find the same
+// passengers, but bm25 can order them differently.
+func (r *SQLiteRepository) SearchPassengers(query, mode string, limit int) ([]model.SearchHit, error) {
+	version, err := r.DataVersion()
+	if err != nil {
+		return nil, err
+	}
+
+	if mode == model.SearchToken && !r.Lenient {
+		hits, ok, err := r.fts.search(r.DB, version, query, limit)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			return hits, nil
+		}
+	}
+	return r.searches.search(version, r.GetAllPassengers, query, mode, limit)
+}
+
+// search runs a token query against the FTS5 table, building or
+// refreshing it first. ok is false when FTS5 is not compiled in.
+func (f *ftsIndex) search(db *sql.DB, version, query string, limit int) ([]model.SearchHit, bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.unavailable {
+		return nil, false, nil
+	}
+	if f.conn == nil || f.version != version {
+		if err := f.build(db); err != nil {
+			if strings.Contains(err.Error(), "no such module") {
+				f.unavailable = true
+				return nil, false, nil
+			}
+			return nil, false, err
+		}
+		f.version = version
+	}
+
+	var terms []string
+	for _, token := range search.Tokenize(query) {
+		terms = append(terms, `"`+token+`"*`)
+	}
+	if len(terms) == 0 {
+		return nil, true, nil
+	}
+	if limit <= 0 {
+		limit = -1
+	}
+
+	rows, err := f.conn.QueryContext(context.Background(), `
+		SELECT t.*, -bm25(passenger_fts)
+		FROM temp.passenger_fts f JOIN main.titanic t ON t.rowid = f.rowid
+		WHERE passenger_fts MATCH ?
+		ORDER BY bm25(passenger_fts), CAST(t.PassengerId AS INTEGER)
+		LIMIT ?`, strings.Join(terms, " "), limit)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to search passengers: %v", err)
+	}
+	defer rows.Close()
+
+	hits := []model.SearchHit{}
+	for rows.Next() {
+		var score float64
+		passenger, err := scanPassenger(withExtra{rows, []interface{}{&score}})
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to scan search result: %v", err)
+		}
+		hits = append(hits, model.SearchHit{Passenger: *passenger, Score: score})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, false, fmt.Errorf("failed to iterate search results: %v", err)
+	}
+	return hits, true, nil
+}
+
+// build (re)creates the FTS5 table from the titanic table.
+func (f *ftsIndex) build(db *sql.DB) error {
+	ctx := context.Background()
+	if f.conn == nil {
+		conn, err := db.Conn(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to open search connection: %v", err)
+		}
+		f.conn = conn
+	}
+
+	statements := []string{
+		"DROP TABLE IF EXISTS temp.passenger_fts",
+		// Keep diacritics, as search.Tokenize does, so both backends find
+		// the same passengers
+		"CREATE VIRTUAL TABLE temp.passenger_fts USING fts5(Name, Ticket, Cabin, tokenize = 'unicode61 remove_diacritics 0')",
+		"INSERT INTO temp.passenger_fts (rowid, Name, Ticket, Cabin) SELECT rowid, Name, Ticket, COALESCE(Cabin, '') FROM main.titanic",
+	}
+	for _, statement := range statements {
+		if _, err := f.conn.ExecContext(ctx, statement); err != nil {
+			return fmt.Errorf("failed to build search index: %v", err)
+		}
+	}
+	return nil
+}
+
+// withExtra scans a row into the destinations of the wrapped call followed
+// by extra, for queries that select additional columns after titanic.*.
+type withExtra struct {
+	rows  *sql.Rows
+	extra []interface{}
+}
+
+func (w withExtra) Scan(dest ...interface{}) error {
+	return w.rows.Scan(append(dest, w.extra...)...)
+}
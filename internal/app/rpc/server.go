@@ -0,0 +1,167 @@
+// internal/app/rpc/server.go
+package rpc
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	titanicv1 "github.com/shindesatish/titanic-service/api/titanic/v1"
+	"github.com/shindesatish/titanic-service/internal/app/query"
+	"github.com/shindesatish/titanic-service/internal/app/repository"
+	"github.com/shindesatish/titanic-service/internal/app/service"
+	"github.com/shindesatish/titanic-service/pkg/model"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// Server implements the titanic.v1.PassengerService gRPC service on top of
+// the same service.PassengerService as the REST API.
+type Server struct {
+	titanicv1.UnimplementedPassengerServiceServer
+	PassengerService *service.PassengerService
+}
+
+func NewServer(passengerService *service.PassengerService) *Server {
+	return &Server{PassengerService: passengerService}
+}
+
+// NewGRPCServer returns a grpc.Server serving the passenger service, with
+// server reflection so that tools like grpcurl can discover it.
+func NewGRPCServer(passengerService *service.PassengerService, opts ...grpc.ServerOption) *grpc.Server {
+	server := grpc.NewServer(opts...)
+	titanicv1.RegisterPassengerServiceServer(server, NewServer(passengerService))
+	reflection.Register(server)
+	return server
+}
+
+func (s *Server) GetPassenger(ctx context.Context, request *titanicv1.GetPassengerRequest) (*titanicv1.Passenger, error) {
+	if request.PassengerId <= 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid passenger ID %d", request.PassengerId)
+	}
+	passenger, err := s.PassengerService.GetPassengerByID(ctx, uint(request.PassengerId))
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return toProto(passenger), nil
+}
+
+func (s *Server) ListPassengers(request *titanicv1.ListPassengersRequest, stream titanicv1.PassengerService_ListPassengersServer) error {
+	var where *query.Expr
+	if strings.TrimSpace(request.Filter) != "" {
+		var err error
+		if where, err = query.Parse(request.Filter); err != nil {
+			return status.Errorf(codes.InvalidArgument, "invalid filter: %v", err)
+		}
+	}
+
+	err := s.PassengerService.StreamFilteredPassengers(stream.Context(), model.PassengerFilter{}, where, func(passenger *model.Passenger) error {
+		return stream.Send(toProto(passenger))
+	})
+	if err != nil {
+		return statusError(err)
+	}
+	return nil
+}
+
+func (s *Server) GetPassengerAttributes(ctx context.Context, request *titanicv1.GetPassengerAttributesRequest) (*titanicv1.GetPassengerAttributesResponse, error) {
+	if request.PassengerId <= 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid passenger ID %d", request.PassengerId)
+	}
+	if len(request.Attributes) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "no attributes specified")
+	}
+	for _, attribute := range request.Attributes {
+		if !isCSVAttribute(attribute) {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid attribute %s, expected one of %s", attribute, strings.Join(model.CSVHeader, ", "))
+		}
+	}
+
+	passenger, err := s.PassengerService.GetPassengerAttributes(ctx, uint(request.PassengerId), request.Attributes)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	response := &titanicv1.GetPassengerAttributesResponse{Attributes: make(map[string]string, len(request.Attributes))}
+	for _, attribute := range request.Attributes {
+		value, err := passenger.Attribute(attribute)
+		if err != nil {
+			return nil, statusError(err)
+		}
+		response.Attributes[attribute] = value
+	}
+	return response, nil
+}
+
+func (s *Server) GetFareHistogram(ctx context.Context, request *titanicv1.GetFareHistogramRequest) (*titanicv1.GetFareHistogramResponse, error) {
+	basis := service.FareRaw
+	switch request.FareBasis {
+	case titanicv1.FareBasis_FARE_BASIS_UNSPECIFIED:
+	case titanicv1.FareBasis_FARE_BASIS_PER_PERSON:
+		basis = service.FarePerPerson
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "unknown fare basis %v", request.FareBasis)
+	}
+
+	histogram, err := s.PassengerService.GetFareHistogramBy(ctx, basis)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	response := &titanicv1.GetFareHistogramResponse{}
+	for _, percentile := range repository.HistogramBuckets() {
+		response.Buckets = append(response.Buckets, &titanicv1.GetFareHistogramResponse_Bucket{
+			Percentile: percentile,
+			Count:      int32(histogram[percentile]),
+		})
+	}
+	return response, nil
+}
+
+// toProto converts a passenger to its protobuf message. An unknown Age
+// leaves the field unset.
+func toProto(p *model.Passenger) *titanicv1.Passenger {
+	message := &titanicv1.Passenger{
+		PassengerId: int32(p.PassengerID),
+		Survived:    int32(p.Survived),
+		Pclass:      int32(p.Pclass),
+		Name:        p.Name,
+		Sex:         p.Sex,
+		SibSp:       int32(p.SibSp),
+		Parch:       int32(p.Parch),
+		Ticket:      p.Ticket,
+		Fare:        p.Fare,
+		Cabin:       p.Cabin,
+		Embarked:    p.Embarked,
+	}
+	if p.Age.Valid {
+		message.Age = proto.Float64(p.Age.Float64)
+	}
+	return message
+}
+
+func isCSVAttribute(attribute string) bool {
+	for _, name := range model.CSVHeader {
+		if strings.EqualFold(name, attribute) {
+			return true
+		}
+	}
+	return false
+}
+
+// statusError maps service errors to gRPC status codes.
+func statusError(err error) error {
+	switch {
+	case errors.Is(err, repository.ErrPassengerNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	return status.Error(codes.Internal, err.Error())
+}
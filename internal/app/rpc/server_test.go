@@ -0,0 +1,223 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	titanicv1 "github.com/shindesatish/titanic-service/api/titanic/v1"
+	"github.com/shindesatish/titanic-service/internal/app/repository"
+	"github.com/shindesatish/titanic-service/internal/app/service"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// backends lists the repository options the tests run against.
+var backends = map[string]repository.Options{
+	"csv":    {CSVPath: "titanic.csv"},
+	"sqlite": {UseSQLite: true, SQLitePath: "titanic.db"},
+}
+
+// newClient serves a gRPC server over an in-memory connection, backed by a
+// temporary copy of the dataset so the tests never touch datastore/. It
+// also returns the service behind the server.
+func newClient(t *testing.T, opts repository.Options) (titanicv1.PassengerServiceClient, *service.PassengerService) {
+	t.Helper()
+	dir := t.TempDir()
+	for _, name := range []string{"titanic.csv", "titanic.db"} {
+		data, err := os.ReadFile(filepath.Join("..", "..", "..", "datastore", name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	opts.CSVPath = filepath.Join(dir, opts.CSVPath)
+	opts.SQLitePath = filepath.Join(dir, opts.SQLitePath)
+	repo, closeRepo, err := repository.Open(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { closeRepo() })
+
+	passengerService := service.NewPassengerService(repo)
+	listener := bufconn.Listen(1 << 20)
+	server := NewGRPCServer(passengerService)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.DialContext(context.Background(), "bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return titanicv1.NewPassengerServiceClient(conn), passengerService
+}
+
+func TestGetPassenger(t *testing.T) {
+	for name, opts := range backends {
+		t.Run(name, func(t *testing.T) {
+			client, _ := newClient(t, opts)
+			ctx := context.Background()
+
+			passenger, err := client.GetPassenger(ctx, &titanicv1.GetPassengerRequest{PassengerId: 1})
+			if err != nil {
+				t.Fatalf("GetPassenger: %v", err)
+			}
+			if passenger.Name != "Braund, Mr. Owen Harris" || passenger.Age == nil || *passenger.Age != 22 || passenger.Ticket != "A/5 21171" {
+				t.Errorf("got passenger %v", passenger)
+			}
+
+			// Passenger 6 has no recorded Age
+			passenger, err = client.GetPassenger(ctx, &titanicv1.GetPassengerRequest{PassengerId: 6})
+			if err != nil {
+				t.Fatalf("GetPassenger: %v", err)
+			}
+			if passenger.Age != nil {
+				t.Errorf("got Age %v for a passenger without one, want unset", *passenger.Age)
+			}
+
+			tests := []struct {
+				id   int32
+				code codes.Code
+			}{
+				{9999, codes.NotFound},
+				{0, codes.InvalidArgument},
+				{-1, codes.InvalidArgument},
+			}
+			for _, tt := range tests {
+				_, err := client.GetPassenger(ctx, &titanicv1.GetPassengerRequest{PassengerId: tt.id})
+				if status.Code(err) != tt.code {
+					t.Errorf("GetPassenger(%d): got %v, want %v", tt.id, err, tt.code)
+				}
+			}
+		})
+	}
+}
+
+func TestListPassengers(t *testing.T) {
+	tests := []struct {
+		filter string
+		count  int
+	}{
+		{"", 891},
+		{"Age < 12", 68},
+		{"Pclass = 1 AND Sex = 'female' AND Embarked = 'C'", 43},
+		{"PassengerId IN (1, 2, 3)", 3},
+	}
+	for name, opts := range backends {
+		t.Run(name, func(t *testing.T) {
+			client, _ := newClient(t, opts)
+			for _, tt := range tests {
+				stream, err := client.ListPassengers(context.Background(), &titanicv1.ListPassengersRequest{Filter: tt.filter})
+				if err != nil {
+					t.Fatalf("ListPassengers(%q): %v", tt.filter, err)
+				}
+				count := 0
+				for {
+					_, err := stream.Recv()
+					if errors.Is(err, io.EOF) {
+						break
+					}
+					if err != nil {
+						t.Fatalf("ListPassengers(%q): %v", tt.filter, err)
+					}
+					count++
+				}
+				if count != tt.count {
+					t.Errorf("ListPassengers(%q) streamed %d passengers, want %d", tt.filter, count, tt.count)
+				}
+			}
+
+			stream, err := client.ListPassengers(context.Background(), &titanicv1.ListPassengersRequest{Filter: "Age <"})
+			if err == nil {
+				_, err = stream.Recv()
+			}
+			if status.Code(err) != codes.InvalidArgument {
+				t.Errorf("ListPassengers with a malformed filter: got %v, want InvalidArgument", err)
+			}
+		})
+	}
+}
+
+func TestGetPassengerAttributes(t *testing.T) {
+	for name, opts := range backends {
+		t.Run(name, func(t *testing.T) {
+			client, _ := newClient(t, opts)
+			ctx := context.Background()
+
+			response, err := client.GetPassengerAttributes(ctx, &titanicv1.GetPassengerAttributesRequest{PassengerId: 2, Attributes: []string{"Name", "Age", "Cabin"}})
+			if err != nil {
+				t.Fatalf("GetPassengerAttributes: %v", err)
+			}
+			want := map[string]string{"Name": "Cumings, Mrs. John Bradley (Florence Briggs Thayer)", "Age": "38", "Cabin": "C85"}
+			if len(response.Attributes) != len(want) {
+				t.Errorf("got attributes %v, want %v", response.Attributes, want)
+			}
+			for attribute, value := range want {
+				if response.Attributes[attribute] != value {
+					t.Errorf("got %s %q, want %q", attribute, response.Attributes[attribute], value)
+				}
+			}
+
+			tests := []struct {
+				request *titanicv1.GetPassengerAttributesRequest
+				code    codes.Code
+			}{
+				{&titanicv1.GetPassengerAttributesRequest{PassengerId: 9999, Attributes: []string{"Name"}}, codes.NotFound},
+				{&titanicv1.GetPassengerAttributesRequest{PassengerId: 2}, codes.InvalidArgument},
+				{&titanicv1.GetPassengerAttributesRequest{PassengerId: 2, Attributes: []string{"Shoe"}}, codes.InvalidArgument},
+			}
+			for _, tt := range tests {
+				if _, err := client.GetPassengerAttributes(ctx, tt.request); status.Code(err) != tt.code {
+					t.Errorf("GetPassengerAttributes(%v): got %v, want %v", tt.request, err, tt.code)
+				}
+			}
+		})
+	}
+}
+
+func TestGetFareHistogram(t *testing.T) {
+	for name, opts := range backends {
+		t.Run(name, func(t *testing.T) {
+			client, passengerService := newClient(t, opts)
+			bases := map[titanicv1.FareBasis]service.FareBasis{
+				titanicv1.FareBasis_FARE_BASIS_UNSPECIFIED: service.FareRaw,
+				titanicv1.FareBasis_FARE_BASIS_PER_PERSON:  service.FarePerPerson,
+			}
+			for basis, serviceBasis := range bases {
+				response, err := client.GetFareHistogram(context.Background(), &titanicv1.GetFareHistogramRequest{FareBasis: basis})
+				if err != nil {
+					t.Fatalf("GetFareHistogram(%v): %v", basis, err)
+				}
+				want, err := passengerService.GetFareHistogramBy(context.Background(), serviceBasis)
+				if err != nil {
+					t.Fatalf("GetFareHistogramBy(%v): %v", serviceBasis, err)
+				}
+				if len(response.Buckets) != len(repository.HistogramBuckets()) {
+					t.Errorf("GetFareHistogram(%v): got %d buckets, want %d", basis, len(response.Buckets), len(repository.HistogramBuckets()))
+				}
+				for i, bucket := range response.Buckets {
+					if bucket.Percentile != repository.HistogramBuckets()[i] || int(bucket.Count) != want[bucket.Percentile] {
+						t.Errorf("GetFareHistogram(%v): bucket %d is %q with %d passengers, want %q with %d", basis, i, bucket.Percentile, bucket.Count, repository.HistogramBuckets()[i], want[repository.HistogramBuckets()[i]])
+					}
+				}
+			}
+
+			if _, err := client.GetFareHistogram(context.Background(), &titanicv1.GetFareHistogramRequest{FareBasis: 7}); status.Code(err) != codes.InvalidArgument {
+				t.Errorf("GetFareHistogram with an unknown basis: got %v, want InvalidArgument", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,179 @@
+// internal/app/search/index.go
+package search
+
+import (
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/shindesatish/titanic-service/pkg/model"
+)
+
+// Index is an in-memory inverted index over the Name, Ticket and Cabin of
+// passengers.
+type Index struct {
+	passengers []model.Passenger
+	// text holds the lower-cased searchable text of each passenger.
+	text     []string
+	postings map[string][]int
+	soundex  map[string][]string
+}
+
+// NewIndex indexes passengers.
+func NewIndex(passengers []model.Passenger) *Index {
+	index := &Index{
+		passengers: passengers,
+		text:       make([]string, len(passengers)),
+		postings:   make(map[string][]int),
+		soundex:    make(map[string][]string),
+	}
+	for i := range passengers {
+		p := &passengers[i]
+		text := strings.Join([]string{p.Name, p.Ticket, p.Cabin}, " ")
+		index.text[i] = strings.ToLower(text)
+
+		seen := make(map[string]bool)
+		for _, token := range Tokenize(text) {
+			if seen[token] {
+				continue
+			}
+			seen[token] = true
+			if _, ok := index.postings[token]; !ok {
+				if code := Soundex(token); code != "" {
+					index.soundex[code] = append(index.soundex[code], token)
+				}
+			}
+			index.postings[token] = append(index.postings[token], i)
+		}
+	}
+	return index
+}
+
+// Search returns up to limit passengers matching query in the given mode,
+// best first. Ties are broken by PassengerId.
+//
+//   - substring matches the query anywhere in the text, ranking whole-word
+//     and word-prefix matches first.
+//   - token requires every query word to be a word of the passenger, or a
+//     prefix of one, weighting rare words higher.
+//   - fuzzy is token with misspellings: words within a small edit distance
+//     or with the same Soundex code also match, scoring less.
+func (x *Index) Search(query, mode string, limit int) []model.SearchHit {
+	scores := make(map[int]float64)
+	matched := make(map[int][]string)
+
+	if mode == model.SearchSubstring {
+		needle := strings.ToLower(strings.TrimSpace(query))
+		if needle == "" {
+			return nil
+		}
+		for i, text := range x.text {
+			if !strings.Contains(text, needle) {
+				continue
+			}
+			score := 1.0
+			for _, token := range Tokenize(text) {
+				if token == needle {
+					score = 3
+					break
+				}
+				if strings.HasPrefix(token, needle) {
+					score = 2
+				}
+			}
+			scores[i] = score
+		}
+		return x.rank(scores, matched, limit)
+	}
+
+	tokens := Tokenize(query)
+	if len(tokens) == 0 {
+		return nil
+	}
+	for n, token := range tokens {
+		termScores := make(map[int]float64)
+		termMatches := make(map[int]string)
+		for term, weight := range x.candidates(token, mode) {
+			idf := x.idf(term)
+			for _, i := range x.postings[term] {
+				// Equal scores go to the first term in order, so Matched
+				// does not depend on map iteration
+				s := weight * idf
+				if previous, ok := termScores[i]; !ok || s > previous || (s == previous && term < termMatches[i]) {
+					termScores[i] = s
+					termMatches[i] = term
+				}
+			}
+		}
+		// Every query word must match
+		for i := range scores {
+			if _, ok := termScores[i]; !ok {
+				delete(scores, i)
+			}
+		}
+		for i, s := range termScores {
+			if _, ok := scores[i]; ok || n == 0 {
+				scores[i] += s
+				matched[i] = append(matched[i], termMatches[i])
+			}
+		}
+	}
+	return x.rank(scores, matched, limit)
+}
+
+// candidates returns the indexed terms a query token matches and how well,
+// from 1 for an exact match down.
+func (x *Index) candidates(token, mode string) map[string]float64 {
+	terms := make(map[string]float64)
+	if _, ok := x.postings[token]; ok {
+		terms[token] = 1
+	}
+	for term := range x.postings {
+		if term != token && strings.HasPrefix(term, token) {
+			terms[term] = 0.75
+		}
+	}
+	if mode != model.SearchFuzzy {
+		return terms
+	}
+
+	maxDistance := 1
+	if len([]rune(token)) > 5 {
+		maxDistance = 2
+	}
+	for term := range x.postings {
+		if _, ok := terms[term]; ok {
+			continue
+		}
+		if d := EditDistance(token, term); d <= maxDistance {
+			terms[term] = 0.75 - 0.25*float64(d)/float64(maxDistance+1)
+		}
+	}
+	for _, term := range x.soundex[Soundex(token)] {
+		if _, ok := terms[term]; !ok {
+			terms[term] = 0.4
+		}
+	}
+	return terms
+}
+
+func (x *Index) idf(term string) float64 {
+	return 1 + math.Log(float64(len(x.passengers))/float64(len(x.postings[term])))
+}
+
+func (x *Index) rank(scores map[int]float64, matched map[int][]string, limit int) []model.SearchHit {
+	hits := make([]model.SearchHit, 0, len(scores))
+	for i, score := range scores {
+		hits = append(hits, model.SearchHit{Passenger: x.passengers[i], Score: score, Matched: matched[i]})
+	}
+	sort.Slice(hits, func(a, b int) bool {
+		if hits[a].Score != hits[b].Score {
+			return hits[a].Score > hits[b].Score
+		}
+		return hits[a].Passenger.PassengerID < hits[b].Passenger.PassengerID
+	})
+	if limit > 0 && len(hits) > limit {
+		hits = hits[:limit]
+	}
+	return hits
+}
@@ -0,0 +1,185 @@
+package search
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/shindesatish/titanic-service/pkg/model"
+)
+
+func TestTokenize(t *testing.T) {
+	tests := map[string][]string{
+		"Braund, Mr. Owen Harris":            {"braund", "mr", "owen", "harris"},
+		"A/5 21171":                          {"a", "5", "21171"},
+		"  ":                                 {},
+		"O'Brien, Mrs. (Johanna \"Hannah\")": {"o", "brien", "mrs", "johanna", "hannah"},
+		"Åberg, Miss. Hélène":                {"åberg", "miss", "hélène"},
+		"C23 C25 C27":                        {"c23", "c25", "c27"},
+	}
+	for input, want := range tests {
+		if got := Tokenize(input); !reflect.DeepEqual(got, want) {
+			t.Errorf("Tokenize(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"", "abc", 3},
+		{"abc", "", 3},
+		{"andersson", "andersson", 0},
+		{"andersson", "anderson", 1},
+		{"kitten", "sitting", 3},
+		{"flaw", "lawn", 2},
+		{"johanson", "johansson", 1},
+		{"héléne", "helene", 2},
+	}
+	for _, tt := range tests {
+		if got := EditDistance(tt.a, tt.b); got != tt.want {
+			t.Errorf("EditDistance(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+		if got := EditDistance(tt.b, tt.a); got != tt.want {
+			t.Errorf("EditDistance(%q, %q) = %d, want %d", tt.b, tt.a, got, tt.want)
+		}
+	}
+}
+
+func TestSoundex(t *testing.T) {
+	tests := map[string]string{
+		"Robert":    "R163",
+		"Rupert":    "R163",
+		"Rubin":     "R150",
+		"Ashcraft":  "A261",
+		"Ashcroft":  "A261",
+		"Tymczak":   "T522",
+		"Pfister":   "P236",
+		"Honeyman":  "H555",
+		"Lee":       "L000",
+		"Andersson": "A536",
+		"Anderson":  "A536",
+		"o'brien":   "O165",
+		"21171":     "",
+		"":          "",
+	}
+	for word, want := range tests {
+		if got := Soundex(word); got != want {
+			t.Errorf("Soundex(%q) = %q, want %q", word, got, want)
+		}
+	}
+}
+
+// testIndex indexes a few passengers with overlapping names.
+func testIndex() *Index {
+	return NewIndex([]model.Passenger{
+		{PassengerID: 1, Name: "Braund, Mr. Owen Harris", Ticket: "A/5 21171"},
+		{PassengerID: 2, Name: "Andersson, Mr. Anders Johan", Ticket: "347082"},
+		{PassengerID: 3, Name: "Andersson, Miss. Ellis Anna Maria", Ticket: "347082"},
+		{PassengerID: 4, Name: "Anderson, Mr. Harry", Ticket: "19952", Cabin: "E12"},
+		{PassengerID: 5, Name: "Andrew, Mr. Edgardo Samuel", Ticket: "231945"},
+		{PassengerID: 6, Name: "Harris, Mr. Henry Birkhardt", Ticket: "36973", Cabin: "C83"},
+	})
+}
+
+func hitIDs(hits []model.SearchHit) []int {
+	ids := []int{}
+	for _, hit := range hits {
+		ids = append(ids, hit.Passenger.PassengerID)
+	}
+	return ids
+}
+
+func TestIndexSearch(t *testing.T) {
+	index := testIndex()
+	tests := []struct {
+		query, mode string
+		limit       int
+		want        []int
+	}{
+		// Exact words outrank prefixes, rarer words outrank common ones
+		{"andersson", model.SearchToken, 0, []int{2, 3}},
+		{"anders", model.SearchToken, 0, []int{2, 4, 3}},
+		{"harris", model.SearchToken, 0, []int{1, 6}},
+		{"andersson mr", model.SearchToken, 0, []int{2}},
+		{"andersson mrs", model.SearchToken, 0, nil},
+		{"ANDERSSON, Miss.", model.SearchToken, 0, []int{3}},
+		{"347082", model.SearchToken, 0, []int{2, 3}},
+		{"e12", model.SearchToken, 0, []int{4}},
+		{"mr", model.SearchToken, 2, []int{1, 2}},
+		{"", model.SearchToken, 0, nil},
+		// Misspellings
+		{"anderson", model.SearchToken, 0, []int{4}},
+		{"anderson", model.SearchFuzzy, 0, []int{4, 2, 3, 5}},
+		{"bruand", model.SearchFuzzy, 0, []int{1}},
+		{"andru", model.SearchFuzzy, 0, []int{2, 4, 5, 3}},
+		{"xyz", model.SearchFuzzy, 0, nil},
+		// Substrings rank whole words, then word prefixes, then the rest
+		{"ander", model.SearchSubstring, 0, []int{2, 3, 4}},
+		{"harris", model.SearchSubstring, 0, []int{1, 6}},
+		{"son, m", model.SearchSubstring, 0, []int{2, 3, 4}},
+		{"  ", model.SearchSubstring, 0, nil},
+	}
+	for _, tt := range tests {
+		hits := index.Search(tt.query, tt.mode, tt.limit)
+		want := tt.want
+		if want == nil {
+			want = []int{}
+		}
+		if got := hitIDs(hits); !reflect.DeepEqual(got, want) {
+			t.Errorf("Search(%q, %s, %d) = %v, want %v", tt.query, tt.mode, tt.limit, got, want)
+		}
+	}
+}
+
+func TestIndexScores(t *testing.T) {
+	index := testIndex()
+
+	// Exact > prefix > edit distance > Soundex, each weighted by the same
+	// rarity of the matched term
+	exact := index.Search("harry", model.SearchFuzzy, 0)
+	prefix := index.Search("harr", model.SearchFuzzy, 0)
+	if len(exact) != 1 || len(prefix) != 3 || prefix[0].Passenger.PassengerID != 4 || exact[0].Score <= prefix[0].Score {
+		t.Errorf("exact %+v, prefix %+v", exact, prefix)
+	}
+
+	// anderson is exact for 4, two edits from anders for 2, one edit from
+	// the more common andersson for 3, and sounds like andrew for 5
+	hits := index.Search("anderson", model.SearchFuzzy, 0)
+	var matched []string
+	for i, hit := range hits {
+		matched = append(matched, hit.Matched...)
+		if i > 0 && hit.Score >= hits[i-1].Score {
+			t.Errorf("hit %d scores %v, not less than %v", i, hit.Score, hits[i-1].Score)
+		}
+	}
+	if strings.Join(matched, " ") != "anderson anders andersson andrew" {
+		t.Errorf("got matched terms %q", matched)
+	}
+
+	// Every word adds to the score
+	one := index.Search("andersson", model.SearchToken, 0)
+	two := index.Search("andersson anna", model.SearchToken, 0)
+	if len(two) != 1 || two[0].Score <= one[0].Score || strings.Join(two[0].Matched, " ") != "andersson anna" {
+		t.Errorf("one word %+v, two words %+v", one, two)
+	}
+
+	// Hits share the passengers they were indexed from
+	if hits[0].Passenger.Name != "Anderson, Mr. Harry" {
+		t.Errorf("got passenger %+v", hits[0].Passenger)
+	}
+}
+
+func TestIndexMatchedTies(t *testing.T) {
+	// anna and annie match ann equally well; the first in order is reported
+	index := NewIndex([]model.Passenger{{PassengerID: 1, Name: "Smith, Mrs. Annie Anna"}})
+	for i := 0; i < 20; i++ {
+		hits := index.Search("ann", model.SearchToken, 0)
+		if len(hits) != 1 || !reflect.DeepEqual(hits[0].Matched, []string{"anna"}) {
+			t.Fatalf("got %+v, want anna matched", hits)
+		}
+	}
+}
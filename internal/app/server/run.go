@@ -0,0 +1,115 @@
+// internal/app/server/run.go
+package server
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/shindesatish/titanic-service/internal/app/prediction"
+	"github.com/shindesatish/titanic-service/internal/app/repository"
+	"github.com/shindesatish/titanic-service/internal/app/rpc"
+	"github.com/shindesatish/titanic-service/internal/app/service"
+	"github.com/shindesatish/titanic-service/internal/app/validation"
+)
+
+// Options configures Run.
+type Options struct {
+	Repository repository.Options
+	// ModelDir is the directory of the stored prediction models
+	ModelDir string
+	// Port and GRPCPort are the ports the HTTP and gRPC APIs listen on
+	Port     string
+	GRPCPort string
+}
+
+// OptionsFromEnv reads Options from the environment:
+//
+//	USE_SQLITE    use the SQLite backend instead of CSV
+//	LENIENT_LOAD  skip invalid rows instead of failing
+//	USE_CACHE     cache responses in process, with CACHE_SIZE entries
+//	              (default 256) that live for CACHE_TTL (default 5m)
+//	MODEL_DIR     directory of the stored models (default ./datastore/models)
+//	PORT          HTTP port (default 8080)
+//	GRPC_PORT     gRPC port (default 9090)
+func OptionsFromEnv() (Options, error) {
+	opts := Options{
+		Repository: repository.Options{
+			UseSQLite:  os.Getenv("USE_SQLITE") == "true",
+			CSVPath:    "./datastore/titanic.csv",
+			SQLitePath: "./datastore/titanic.db",
+			Lenient:    os.Getenv("LENIENT_LOAD") == "true",
+		},
+		ModelDir: Getenv("MODEL_DIR", "./datastore/models"),
+		Port:     Getenv("PORT", "8080"),
+		GRPCPort: Getenv("GRPC_PORT", "9090"),
+	}
+	ttl, err := time.ParseDuration(Getenv("CACHE_TTL", "5m"))
+	if err != nil {
+		return Options{}, fmt.Errorf("invalid CACHE_TTL: %v", err)
+	}
+	opts.Repository.CacheTTL = ttl
+	if os.Getenv("USE_CACHE") == "true" {
+		size, err := strconv.Atoi(Getenv("CACHE_SIZE", "256"))
+		if err != nil {
+			return Options{}, fmt.Errorf("invalid CACHE_SIZE: %v", err)
+		}
+		opts.Repository.CacheSize = size
+	}
+	return opts, nil
+}
+
+// Getenv returns the value of the environment variable key, or fallback if
+// it is unset.
+func Getenv(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// Run opens the repository and serves the HTTP and gRPC APIs from one
+// PassengerService until either server stops, returning its error.
+func Run(opts Options) error {
+	repo, closeRepo, err := repository.Open(opts.Repository)
+	if err != nil {
+		return err
+	}
+	defer closeRepo()
+
+	if opts.Repository.Lenient {
+		skipped, err := repository.SkippedRows(repo)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Lenient load skipped %d invalid rows\n", len(validation.Lines(skipped)))
+	}
+
+	passengerService := service.NewPassengerService(repo)
+	passengerService.Models = prediction.NewStore(opts.ModelDir)
+	router, err := New(DefaultConfig, passengerService)
+	if err != nil {
+		return err
+	}
+
+	listener, err := net.Listen("tcp", ":"+opts.GRPCPort)
+	if err != nil {
+		return fmt.Errorf("failed to listen for gRPC: %v", err)
+	}
+	grpcServer := rpc.NewGRPCServer(passengerService)
+	defer grpcServer.Stop()
+
+	errs := make(chan error, 2)
+	go func() {
+		fmt.Printf("gRPC server listening on :%s\n", opts.GRPCPort)
+		errs <- fmt.Errorf("gRPC server stopped: %v", grpcServer.Serve(listener))
+	}()
+	go func() {
+		fmt.Printf("Server listening on :%s\n", opts.Port)
+		errs <- http.ListenAndServe(":"+opts.Port, router)
+	}()
+	return <-errs
+}
@@ -0,0 +1,66 @@
+// internal/app/server/server.go
+package server
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/shindesatish/titanic-service/internal/app/handler"
+	"github.com/shindesatish/titanic-service/internal/app/openapi"
+	"github.com/shindesatish/titanic-service/internal/app/service"
+
+	swaggerFiles "github.com/swaggo/files"
+	ginSwagger "github.com/swaggo/gin-swagger"
+)
+
+// Config configures the HTTP API.
+type Config struct {
+	// Logger logs every request to gin.DefaultWriter
+	Logger bool
+	// Swagger serves the Swagger UI of /v1/openapi.json under /v1/swagger
+	Swagger bool
+	// Validate rejects requests that do not match the OpenAPI document
+	// before they reach the handlers
+	Validate bool
+}
+
+// DefaultConfig is the configuration of the titanic-service binaries.
+var DefaultConfig = Config{Logger: true, Swagger: true, Validate: true}
+
+// info describes the API in the OpenAPI document.
+var info = openapi.Info{
+	Title:       "Titanic Service API",
+	Version:     "1.0",
+	Description: "API for accessing Titanic passenger data",
+}
+
+// New builds the Gin engine serving the passenger API from passengerService.
+// The engine is not bound to a port, so it can be served with
+// http.ListenAndServe or exercised with httptest. It fails if the GraphQL
+// schema does not build.
+func New(cfg Config, passengerService *service.PassengerService) (*gin.Engine, error) {
+	router := gin.New()
+	if cfg.Logger {
+		router.Use(gin.Logger())
+	}
+	router.Use(gin.Recovery())
+
+	passengerHandler := handler.NewPassengerHandler(passengerService)
+	v1 := router.Group("/v1")
+	{
+		if cfg.Validate {
+			v1.Use(openapi.Validator("/v1", handler.Operations))
+		}
+		if err := passengerHandler.RegisterRoutes(v1); err != nil {
+			return nil, err
+		}
+
+		// The document lists the routes registered so far, itself included
+		doc := &openapi.Document{}
+		v1.GET("/openapi.json", handler.OpenAPIHandler(doc))
+		*doc = *openapi.Build(info, "/v1", router.Routes(), handler.Operations)
+
+		if cfg.Swagger {
+			v1.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler, ginSwagger.URL("/v1/openapi.json")))
+		}
+	}
+	return router, nil
+}
@@ -0,0 +1,217 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shindesatish/titanic-service/internal/app/handler"
+	"github.com/shindesatish/titanic-service/internal/app/openapi"
+	"github.com/shindesatish/titanic-service/internal/app/prediction"
+	"github.com/shindesatish/titanic-service/internal/app/repository"
+	"github.com/shindesatish/titanic-service/internal/app/service"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// backends lists the repository options the tests run against.
+var backends = map[string]repository.Options{
+	"csv":    {CSVPath: "titanic.csv", CacheSize: 16},
+	"sqlite": {UseSQLite: true, SQLitePath: "titanic.db", CacheSize: 16},
+}
+
+// newRouter builds the router of the titanic-service binaries, without
+// request logging, over a temporary copy of the dataset and models so the
+// tests never touch datastore/.
+func newRouter(t *testing.T, opts repository.Options) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	dir := t.TempDir()
+	datastore := filepath.Join("..", "..", "..", "datastore")
+	for _, name := range []string{"titanic.csv", "titanic.db", filepath.Join("models", "survival.json")} {
+		data, err := os.ReadFile(filepath.Join(datastore, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	opts.CSVPath = filepath.Join(dir, opts.CSVPath)
+	opts.SQLitePath = filepath.Join(dir, opts.SQLitePath)
+	repo, closeRepo, err := repository.Open(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { closeRepo() })
+
+	passengerService := service.NewPassengerService(repo)
+	passengerService.Models = prediction.NewStore(filepath.Join(dir, "models"))
+	cfg := DefaultConfig
+	cfg.Logger = false
+	router, err := New(cfg, passengerService)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return router
+}
+
+// routeTest is a request to one route and the response it should get. The
+// body of a response with a golden file must match testdata/<golden>.json
+// on every backend.
+type routeTest struct {
+	route       string
+	method      string
+	target      string
+	contentType string
+	body        string
+	status      int
+	golden      string
+}
+
+var routeTests = []routeTest{
+	{route: "GET /v1/passengers", method: http.MethodGet, target: "/v1/passengers", status: http.StatusOK, golden: "passengers_all"},
+	{route: "GET /v1/passengers", method: http.MethodGet, target: "/v1/passengers?filter=" + url.QueryEscape("Pclass = 1 AND Sex = 'female' AND Embarked = 'C'"), status: http.StatusOK, golden: "passengers"},
+	{route: "GET /v1/passengers", method: http.MethodGet, target: "/v1/passengers?ids=3,1,9999", status: http.StatusOK},
+	{route: "GET /v1/passengers", method: http.MethodGet, target: "/v1/passengers?filter=" + url.QueryEscape("Age <"), status: http.StatusBadRequest},
+	{route: "GET /v1/passengers/search", method: http.MethodGet, target: "/v1/passengers/search?q=braund", status: http.StatusOK},
+	{route: "GET /v1/passengers/search", method: http.MethodGet, target: "/v1/passengers/search", status: http.StatusBadRequest},
+	{route: "GET /v1/passengers/:id", method: http.MethodGet, target: "/v1/passengers/1", status: http.StatusOK, golden: "passenger_1"},
+	{route: "GET /v1/passengers/:id", method: http.MethodGet, target: "/v1/passengers/6?impute=median", status: http.StatusOK, golden: "passenger_6_imputed"},
+	{route: "GET /v1/passengers/:id", method: http.MethodGet, target: "/v1/passengers/9999", status: http.StatusNotFound},
+	{route: "GET /v1/passengers/:id", method: http.MethodGet, target: "/v1/passengers/abc", status: http.StatusBadRequest},
+	{route: "POST /v1/passengers:action", method: http.MethodPost, target: "/v1/passengers:batchGet", contentType: "application/json", body: `{"ids": [3, 1, 9999]}`, status: http.StatusOK},
+	{route: "POST /v1/passengers:action", method: http.MethodPost, target: "/v1/passengers:delete", contentType: "application/json", body: `{}`, status: http.StatusNotFound},
+	{route: "GET /v1/passengers/:id/group", method: http.MethodGet, target: "/v1/passengers/2/group", status: http.StatusOK},
+	{route: "GET /v1/groups", method: http.MethodGet, target: "/v1/groups?min_size=4", status: http.StatusOK},
+	{route: "GET /v1/groups", method: http.MethodGet, target: "/v1/groups?min_size=x", status: http.StatusBadRequest},
+	{route: "GET /v1/tickets/*ticket", method: http.MethodGet, target: "/v1/tickets/A/5 21171", status: http.StatusOK},
+	{route: "GET /v1/passenger-attributes/:id", method: http.MethodGet, target: "/v1/passenger-attributes/2?attributes=Name&attributes=Age&attributes=Cabin", status: http.StatusOK, golden: "passenger_attributes_2"},
+	{route: "GET /v1/passenger-attributes/:id", method: http.MethodGet, target: "/v1/passenger-attributes/2?attributes=Shoe", status: http.StatusBadRequest},
+	{route: "GET /v1/passenger-attributes/:id", method: http.MethodGet, target: "/v1/passenger-attributes/9999?attributes=Name", status: http.StatusNotFound},
+	{route: "GET /v1/fare-histogram", method: http.MethodGet, target: "/v1/fare-histogram", status: http.StatusOK, golden: "fare_histogram"},
+	{route: "GET /v1/fare-histogram", method: http.MethodGet, target: "/v1/fare-histogram?fare=per_person", status: http.StatusOK, golden: "fare_histogram_per_person"},
+	{route: "GET /v1/fare-histogram", method: http.MethodGet, target: "/v1/fare-histogram?fare=cabin", status: http.StatusBadRequest},
+	{route: "GET /v1/export", method: http.MethodGet, target: "/v1/export?format=csv", status: http.StatusOK},
+	{route: "GET /v1/export", method: http.MethodGet, target: "/v1/export?format=json&impute=median&filter=" + url.QueryEscape("PassengerId <= 6"), status: http.StatusOK, golden: "export_imputed"},
+	{route: "POST /v1/imports", method: http.MethodPost, target: "/v1/imports?dry_run=true", contentType: "application/json", body: `[{"PassengerId": 1000, "Survived": 1, "Pclass": 2, "Name": "Doe, Mr. John", "Sex": "male", "Age": 30, "SibSp": 0, "Parch": 0, "Ticket": "X1", "Fare": 13, "Cabin": "", "Embarked": "S"}]`, status: http.StatusOK},
+	{route: "POST /v1/imports", method: http.MethodPost, target: "/v1/imports", contentType: "application/json", body: `[{"PassengerId": 1000}]`, status: http.StatusUnprocessableEntity},
+	{route: "POST /v1/imports", method: http.MethodPost, target: "/v1/imports", contentType: "text/plain", body: "1000", status: http.StatusUnsupportedMediaType},
+	{route: "GET /v1/stats/summary", method: http.MethodGet, target: "/v1/stats/summary", status: http.StatusOK},
+	{route: "GET /v1/stats/survival", method: http.MethodGet, target: "/v1/stats/survival?by=Sex", status: http.StatusOK},
+	{route: "GET /v1/stats/survival", method: http.MethodGet, target: "/v1/stats/survival?by=Shoe", status: http.StatusBadRequest},
+	{route: "GET /v1/stats/crosstab", method: http.MethodGet, target: "/v1/stats/crosstab?rows=Pclass&cols=Sex", status: http.StatusOK},
+	{route: "GET /v1/stats/tests/chi-square", method: http.MethodGet, target: "/v1/stats/tests/chi-square?a=Pclass&b=Survived", status: http.StatusOK},
+	{route: "GET /v1/stats/tests/compare", method: http.MethodGet, target: "/v1/stats/tests/compare?value=Age&by=Sex", status: http.StatusOK},
+	{route: "GET /v1/stats/correlations", method: http.MethodGet, target: "/v1/stats/correlations", status: http.StatusOK},
+	{route: "GET /v1/validation", method: http.MethodGet, target: "/v1/validation", status: http.StatusOK},
+	{route: "GET /v1/cache-stats", method: http.MethodGet, target: "/v1/cache-stats", status: http.StatusOK},
+	{route: "POST /v1/predict", method: http.MethodPost, target: "/v1/predict", contentType: "application/json", body: `{"Sex": "female", "Pclass": 1, "Age": 30, "Fare": 80, "Embarked": "C"}`, status: http.StatusOK},
+	{route: "POST /v1/predict", method: http.MethodPost, target: "/v1/predict?model=missing", contentType: "application/json", body: `{"Sex": "female", "Pclass": 1}`, status: http.StatusNotFound},
+	{route: "GET /v1/models/:name/evaluation", method: http.MethodGet, target: "/v1/models/survival/evaluation?folds=3", status: http.StatusOK},
+	{route: "GET /v1/models/:name/evaluation", method: http.MethodGet, target: "/v1/models/survival/evaluation?folds=11", status: http.StatusBadRequest},
+	{route: "GET /v1/graphql", method: http.MethodGet, target: "/v1/graphql?query=" + url.QueryEscape("{ passenger(id: 1) { Name Age Cabin } }"), status: http.StatusOK, golden: "graphql_passenger_1"},
+	{route: "POST /v1/graphql", method: http.MethodPost, target: "/v1/graphql", contentType: "application/json", body: `{"query": "{ passenger(id: 2) { Name Age Cabin } }"}`, status: http.StatusOK, golden: "graphql_passenger_2"},
+	{route: "GET /v1/openapi.json", method: http.MethodGet, target: "/v1/openapi.json", status: http.StatusOK},
+	{route: "GET /v1/swagger/*any", method: http.MethodGet, target: "/v1/swagger/index.html", status: http.StatusOK},
+}
+
+func TestRoutes(t *testing.T) {
+	for name, opts := range backends {
+		t.Run(name, func(t *testing.T) {
+			router := newRouter(t, opts)
+
+			tested := map[string]bool{}
+			for _, tt := range routeTests {
+				tested[tt.route] = true
+			}
+			for _, route := range router.Routes() {
+				if !tested[route.Method+" "+route.Path] {
+					t.Errorf("route %s %s is not tested", route.Method, route.Path)
+				}
+			}
+
+			for _, tt := range routeTests {
+				recorder := httptest.NewRecorder()
+				request := httptest.NewRequest(tt.method, strings.ReplaceAll(tt.target, " ", "%20"), strings.NewReader(tt.body))
+				if tt.contentType != "" {
+					request.Header.Set("Content-Type", tt.contentType)
+				}
+				router.ServeHTTP(recorder, request)
+
+				if recorder.Code != tt.status {
+					t.Errorf("%s %s: got status %d, want %d: %s", tt.method, tt.target, recorder.Code, tt.status, recorder.Body)
+					continue
+				}
+				// GraphQL reports errors with a 200, next to partial data
+				if strings.HasSuffix(tt.route, "/graphql") {
+					var result struct{ Errors []interface{} }
+					if err := json.Unmarshal(recorder.Body.Bytes(), &result); err != nil || len(result.Errors) != 0 {
+						t.Errorf("%s %s: got errors %v (%v)", tt.method, tt.target, result.Errors, err)
+					}
+				}
+				if tt.golden != "" {
+					checkGolden(t, tt.golden, recorder.Body.Bytes())
+				}
+			}
+		})
+	}
+}
+
+// checkGolden compares the indented JSON body with testdata/<name>.json, or
+// rewrites the file when the tests run with -update.
+func checkGolden(t *testing.T, name string, body []byte) {
+	t.Helper()
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, body, "", "  "); err != nil {
+		t.Errorf("%s: invalid JSON response: %v", name, err)
+		return
+	}
+	indented.WriteByte('\n')
+
+	path := filepath.Join("testdata", name+".json")
+	if *update {
+		if err := os.WriteFile(path, indented.Bytes(), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(indented.Bytes(), want) {
+		t.Errorf("%s: response does not match %s; rerun with -update if the change is intended", name, path)
+	}
+}
+
+func TestOperationsCoverRoutes(t *testing.T) {
+	router := newRouter(t, backends["csv"])
+	for _, route := range router.Routes() {
+		path := openapi.Path(strings.TrimPrefix(route.Path, "/v1"))
+		if path == "/swagger/{any}" || path == "/passengers:action" {
+			continue
+		}
+		if _, ok := handler.Operations[openapi.Key(route.Method, path)]; !ok {
+			t.Errorf("%s %s has no entry in handler.Operations", route.Method, route.Path)
+		}
+	}
+
+	// The Swagger UI renders the generated document
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/v1/swagger/index.html", nil))
+	if body := recorder.Body.String(); !strings.Contains(body, "openapi.json") || strings.Contains(body, "doc.json") {
+		t.Errorf("the Swagger UI does not load /v1/openapi.json")
+	}
+}
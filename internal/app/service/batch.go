@@ -0,0 +1,48 @@
+// internal/app/service/batch.go
+package service
+
+import (
+	"context"
+
+	"github.com/shindesatish/titanic-service/pkg/model"
+)
+
+// PassengerBatch is the result of a batch lookup: the passengers found, in
+// the order their IDs were requested, and the requested IDs that no
+// passenger has.
+type PassengerBatch struct {
+	Passengers []model.Passenger
+	MissingIDs []uint
+}
+
+// GetPassengersByIDs looks up several passengers with a single repository
+// call. Repeated IDs are looked up once.
+func (s *PassengerService) GetPassengersByIDs(ctx context.Context, passengerIDs []uint) (*PassengerBatch, error) {
+	unique := make([]uint, 0, len(passengerIDs))
+	seen := make(map[uint]bool, len(passengerIDs))
+	for _, id := range passengerIDs {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+
+	found, err := s.Repository.GetPassengersByIDs(unique)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[uint]model.Passenger, len(found))
+	for _, passenger := range found {
+		byID[uint(passenger.PassengerID)] = passenger
+	}
+
+	batch := &PassengerBatch{Passengers: []model.Passenger{}, MissingIDs: []uint{}}
+	for _, id := range unique {
+		if passenger, ok := byID[id]; ok {
+			batch.Passengers = append(batch.Passengers, passenger)
+		} else {
+			batch.MissingIDs = append(batch.MissingIDs, id)
+		}
+	}
+	return batch, nil
+}
@@ -0,0 +1,241 @@
+// internal/app/service/crosstab.go
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/shindesatish/titanic-service/pkg/model"
+)
+
+// Aggregations a cross-tabulation can compute
+const (
+	AggCount  = "count"
+	AggSum    = "sum"
+	AggMean   = "mean"
+	AggMedian = "median"
+)
+
+// Aggregations lists the accepted aggregations.
+var Aggregations = []string{AggCount, AggSum, AggMean, AggMedian}
+
+// Normalizations of count and sum cross-tabulations
+const (
+	NormalizeAll     = "all"
+	NormalizeRows    = "rows"
+	NormalizeColumns = "columns"
+)
+
+// Normalizations lists the accepted normalizations.
+var Normalizations = []string{NormalizeAll, NormalizeRows, NormalizeColumns}
+
+// CrossTabRequest describes a cross-tabulation. Value is required for
+// every aggregation but count, which counts passengers (or, with a Value,
+// the passengers that have one).
+type CrossTabRequest struct {
+	Rows      string
+	Cols      string
+	Value     string
+	Agg       string
+	Normalize string
+	Margins   bool
+}
+
+// Validate checks the attributes and the combination of options.
+func (r CrossTabRequest) Validate() error {
+	for _, attribute := range []string{r.Rows, r.Cols} {
+		if !model.IsAttribute(attribute) {
+			return fmt.Errorf("unknown attribute: %s", attribute)
+		}
+	}
+	if r.Value != "" && !model.IsNumericAttribute(r.Value) {
+		return fmt.Errorf("value must be a numeric attribute, got %s", r.Value)
+	}
+	switch r.Agg {
+	case AggCount:
+	case AggSum, AggMean, AggMedian:
+		if r.Value == "" {
+			return fmt.Errorf("agg %s needs a value attribute", r.Agg)
+		}
+	default:
+		return fmt.Errorf("unknown aggregation: %s", r.Agg)
+	}
+	switch r.Normalize {
+	case "":
+	case NormalizeAll, NormalizeRows, NormalizeColumns:
+		if r.Agg != AggCount && r.Agg != AggSum {
+			return fmt.Errorf("normalize only applies to count and sum, not %s", r.Agg)
+		}
+	default:
+		return fmt.Errorf("unknown normalization: %s", r.Normalize)
+	}
+	return nil
+}
+
+// GetCrossTab computes a contingency table through the repository, which
+// groups the passengers in whatever way suits its backend.
+func (s *PassengerService) GetCrossTab(ctx context.Context, request CrossTabRequest) (*model.CrossTab, error) {
+	if err := request.Validate(); err != nil {
+		return nil, err
+	}
+	cells, err := s.Repository.CrossTabulate(request.Rows, request.Cols, request.Value, request.Agg == AggMedian)
+	if err != nil {
+		return nil, err
+	}
+
+	table := &model.CrossTab{
+		Rows:      request.Rows,
+		Cols:      request.Cols,
+		Value:     request.Value,
+		Agg:       request.Agg,
+		Normalize: request.Normalize,
+	}
+	rowIndex, colIndex := make(map[string]int), make(map[string]int)
+	for _, cell := range cells {
+		rowIndex[cell.Row] = 0
+		colIndex[cell.Col] = 0
+	}
+	table.RowKeys = sortedGroupKeys(rowIndex)
+	table.ColKeys = sortedGroupKeys(colIndex)
+
+	grid := make([][]model.CrossTabCell, len(table.RowKeys))
+	for i := range grid {
+		grid[i] = make([]model.CrossTabCell, len(table.ColKeys))
+	}
+	for _, cell := range cells {
+		grid[rowIndex[cell.Row]][colIndex[cell.Col]] = cell
+	}
+
+	aggregate := func(cells []model.CrossTabCell) *float64 {
+		return aggregateCells(request, cells)
+	}
+	var all []model.CrossTabCell
+	cols := make([][]model.CrossTabCell, len(table.ColKeys))
+	table.Cells = make([][]*float64, len(table.RowKeys))
+	for i := range grid {
+		table.Cells[i] = make([]*float64, len(table.ColKeys))
+		for j, cell := range grid[i] {
+			table.Cells[i][j] = aggregate([]model.CrossTabCell{cell})
+			cols[j] = append(cols[j], cell)
+		}
+		all = append(all, grid[i]...)
+	}
+
+	// Margins aggregate the underlying passengers, so a mean margin is the
+	// mean of the whole row rather than the mean of the cell means
+	rowMargins := make([]*float64, len(grid))
+	for i := range grid {
+		rowMargins[i] = aggregate(grid[i])
+	}
+	colMargins := make([]*float64, len(cols))
+	for j := range cols {
+		colMargins[j] = aggregate(cols[j])
+	}
+	total := aggregate(all)
+
+	switch request.Normalize {
+	case NormalizeAll:
+		for i := range table.Cells {
+			divideAll(table.Cells[i], total)
+		}
+		divideAll(rowMargins, total)
+		divideAll(colMargins, total)
+		total = divide(total, total)
+	case NormalizeRows:
+		for i := range table.Cells {
+			divideAll(table.Cells[i], rowMargins[i])
+		}
+		divideAll(colMargins, total)
+		for i := range rowMargins {
+			rowMargins[i] = divide(rowMargins[i], rowMargins[i])
+		}
+		total = divide(total, total)
+	case NormalizeColumns:
+		for i := range table.Cells {
+			for j := range table.Cells[i] {
+				table.Cells[i][j] = divide(table.Cells[i][j], colMargins[j])
+			}
+		}
+		divideAll(rowMargins, total)
+		for j := range colMargins {
+			colMargins[j] = divide(colMargins[j], colMargins[j])
+		}
+		total = divide(total, total)
+	}
+
+	if request.Margins {
+		table.RowMargins = rowMargins
+		table.ColMargins = colMargins
+		table.Total = total
+	}
+	return table, nil
+}
+
+// aggregateCells combines cells and applies the requested aggregation. It
+// returns nil when there is no value to aggregate.
+func aggregateCells(request CrossTabRequest, cells []model.CrossTabCell) *float64 {
+	var count, valueCount int
+	var sum float64
+	var values []float64
+	for _, cell := range cells {
+		count += cell.Count
+		valueCount += cell.ValueCount
+		sum += cell.Sum
+		values = append(values, cell.Values...)
+	}
+
+	var result float64
+	switch request.Agg {
+	case AggCount:
+		result = float64(count)
+		if request.Value != "" {
+			result = float64(valueCount)
+		}
+	case AggSum:
+		if valueCount == 0 {
+			return nil
+		}
+		result = sum
+	case AggMean:
+		if valueCount == 0 {
+			return nil
+		}
+		result = sum / float64(valueCount)
+	case AggMedian:
+		if len(values) == 0 {
+			return nil
+		}
+		sort.Float64s(values)
+		result = median(values)
+	}
+	return &result
+}
+
+func divide(value, by *float64) *float64 {
+	if value == nil || by == nil || *by == 0 {
+		return nil
+	}
+	result := *value / *by
+	return &result
+}
+
+func divideAll(values []*float64, by *float64) {
+	for i := range values {
+		values[i] = divide(values[i], by)
+	}
+}
+
+// sortedGroupKeys returns the keys of index ordered by lessGroupKey and
+// stores each key's position in index.
+func sortedGroupKeys(index map[string]int) []string {
+	keys := make([]string, 0, len(index))
+	for key := range index {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return lessGroupKey(keys[i], keys[j]) })
+	for i, key := range keys {
+		index[key] = i
+	}
+	return keys
+}
@@ -0,0 +1,48 @@
+// internal/app/service/groups.go
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/shindesatish/titanic-service/internal/app/grouping"
+	"github.com/shindesatish/titanic-service/pkg/model"
+)
+
+// ErrGroupNotFound is returned when no travel group contains the requested
+// passenger.
+var ErrGroupNotFound = errors.New("passenger is not in any travel group")
+
+// GetTravelGroups reconstructs the families and travel parties of the whole
+// dataset, keeping the groups with at least minSize members.
+func (s *PassengerService) GetTravelGroups(ctx context.Context, minSize int) ([]model.TravelGroup, error) {
+	passengers, err := s.GetAllPassengers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	groups := []model.TravelGroup{}
+	for _, group := range grouping.Build(passengers) {
+		if group.Size >= minSize {
+			groups = append(groups, group)
+		}
+	}
+	return groups, nil
+}
+
+// GetPassengerGroup returns the travel group the passenger belongs to.
+// Passengers travelling alone form a group of one.
+func (s *PassengerService) GetPassengerGroup(ctx context.Context, passengerID int) (*model.TravelGroup, error) {
+	groups, err := s.GetTravelGroups(ctx, 1)
+	if err != nil {
+		return nil, err
+	}
+	for i := range groups {
+		for _, member := range groups[i].Members {
+			if member.PassengerID == passengerID {
+				return &groups[i], nil
+			}
+		}
+	}
+	return nil, ErrGroupNotFound
+}
@@ -0,0 +1,210 @@
+// internal/app/service/hypothesis.go
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+
+	"github.com/shindesatish/titanic-service/pkg/model"
+	"github.com/shindesatish/titanic-service/pkg/stats"
+)
+
+// Correlation methods
+const (
+	CorrelationPearson  = "pearson"
+	CorrelationSpearman = "spearman"
+)
+
+// ChiSquareTest is a chi-square test of independence between two
+// categorical attributes, with the contingency table it was computed on.
+type ChiSquareTest struct {
+	A        string      `json:"a"`
+	B        string      `json:"b"`
+	RowKeys  []string    `json:"row_keys"`
+	ColKeys  []string    `json:"col_keys"`
+	Observed [][]float64 `json:"observed"`
+	*stats.ChiSquareResult
+}
+
+// GroupComparison compares a numeric attribute between two groups of
+// passengers with Welch's t-test and the Mann–Whitney U test.
+type GroupComparison struct {
+	Value       string                   `json:"value"`
+	By          string                   `json:"by"`
+	Groups      [2]string                `json:"groups"`
+	Counts      [2]int                   `json:"counts"`
+	TTest       *stats.TTestResult       `json:"t_test"`
+	MannWhitney *stats.MannWhitneyResult `json:"mann_whitney"`
+}
+
+// CorrelationMatrix holds the pairwise correlations of numeric attributes,
+// each pair computed over the passengers that have both values.
+type CorrelationMatrix struct {
+	Method       string                       `json:"method"`
+	Attributes   []string                     `json:"attributes"`
+	Coefficients [][]*float64                 `json:"coefficients"`
+	Pairs        [][]*stats.CorrelationResult `json:"pairs"`
+}
+
+// ChiSquareTest tests whether the categorical attributes a and b are
+// independent, from the counts the repository cross-tabulates.
+func (s *PassengerService) ChiSquareTest(ctx context.Context, a, b string) (*ChiSquareTest, error) {
+	table, err := s.GetCrossTab(ctx, CrossTabRequest{Rows: a, Cols: b, Agg: AggCount})
+	if err != nil {
+		return nil, err
+	}
+
+	test := &ChiSquareTest{A: a, B: b, RowKeys: table.RowKeys, ColKeys: table.ColKeys}
+	test.Observed = make([][]float64, len(table.Cells))
+	for i, row := range table.Cells {
+		test.Observed[i] = make([]float64, len(row))
+		for j, cell := range row {
+			if cell != nil {
+				test.Observed[i][j] = *cell
+			}
+		}
+	}
+	if test.ChiSquareResult, err = stats.ChiSquare(test.Observed); err != nil {
+		return nil, err
+	}
+	return test, nil
+}
+
+// CompareGroups compares the numeric attribute value between the passengers
+// whose attribute by equals groups[0] and those where it equals groups[1].
+// When groups is nil, by must take exactly two values. Passengers without
+// a value are left out.
+func (s *PassengerService) CompareGroups(ctx context.Context, value, by string, groups []string) (*GroupComparison, error) {
+	if !model.IsNumericAttribute(value) {
+		return nil, fmt.Errorf("value must be a numeric attribute, got %s", value)
+	}
+	if !model.IsAttribute(by) {
+		return nil, fmt.Errorf("unknown attribute: %s", by)
+	}
+	if groups != nil && len(groups) != 2 {
+		return nil, fmt.Errorf("expected two groups, got %d", len(groups))
+	}
+
+	samples := make(map[string][]float64)
+	err := s.StreamPassengers(ctx, func(p *model.Passenger) error {
+		group, err := p.Attribute(by)
+		if err != nil {
+			return err
+		}
+		number, ok, err := numericAttribute(p, value)
+		if err != nil || !ok {
+			return err
+		}
+		samples[group] = append(samples[group], number)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if groups == nil {
+		for group := range samples {
+			groups = append(groups, group)
+		}
+		if len(groups) != 2 {
+			return nil, fmt.Errorf("%s has %d values, pick two with groups", by, len(groups))
+		}
+		sort.Slice(groups, func(i, j int) bool { return lessGroupKey(groups[i], groups[j]) })
+	}
+
+	comparison := &GroupComparison{Value: value, By: by, Groups: [2]string{groups[0], groups[1]}}
+	a, b := samples[groups[0]], samples[groups[1]]
+	comparison.Counts = [2]int{len(a), len(b)}
+	if comparison.TTest, err = stats.WelchTTest(a, b); err != nil {
+		return nil, fmt.Errorf("t-test: %v", err)
+	}
+	if comparison.MannWhitney, err = stats.MannWhitneyU(a, b); err != nil {
+		return nil, fmt.Errorf("Mann-Whitney U test: %v", err)
+	}
+	return comparison, nil
+}
+
+// GetCorrelationMatrix correlates every pair of the numeric attributes.
+func (s *PassengerService) GetCorrelationMatrix(ctx context.Context, attributes []string, method string) (*CorrelationMatrix, error) {
+	correlate := stats.Pearson
+	switch method {
+	case CorrelationPearson:
+	case CorrelationSpearman:
+		correlate = stats.Spearman
+	default:
+		return nil, fmt.Errorf("unknown correlation method: %s", method)
+	}
+	if len(attributes) < 2 {
+		return nil, fmt.Errorf("need at least two attributes to correlate")
+	}
+	for _, attribute := range attributes {
+		if !model.IsNumericAttribute(attribute) {
+			return nil, fmt.Errorf("%s is not a numeric attribute", attribute)
+		}
+	}
+
+	// Missing values are NaN so each pair can skip them independently
+	columns := make([][]float64, len(attributes))
+	err := s.StreamPassengers(ctx, func(p *model.Passenger) error {
+		for i, attribute := range attributes {
+			number, ok, err := numericAttribute(p, attribute)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				number = math.NaN()
+			}
+			columns[i] = append(columns[i], number)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	matrix := &CorrelationMatrix{
+		Method:       method,
+		Attributes:   attributes,
+		Coefficients: make([][]*float64, len(attributes)),
+		Pairs:        make([][]*stats.CorrelationResult, len(attributes)),
+	}
+	for i := range attributes {
+		matrix.Coefficients[i] = make([]*float64, len(attributes))
+		matrix.Pairs[i] = make([]*stats.CorrelationResult, len(attributes))
+	}
+	for i := range attributes {
+		for j := i; j < len(attributes); j++ {
+			var x, y []float64
+			for k := range columns[i] {
+				if !math.IsNaN(columns[i][k]) && !math.IsNaN(columns[j][k]) {
+					x = append(x, columns[i][k])
+					y = append(y, columns[j][k])
+				}
+			}
+			result, err := correlate(x, y)
+			if err != nil {
+				continue
+			}
+			matrix.Pairs[i][j], matrix.Pairs[j][i] = result, result
+			matrix.Coefficients[i][j], matrix.Coefficients[j][i] = &result.Coefficient, &result.Coefficient
+		}
+	}
+	return matrix, nil
+}
+
+// numericAttribute parses a numeric attribute of p. ok is false when the
+// passenger has no value for it.
+func numericAttribute(p *model.Passenger, attribute string) (float64, bool, error) {
+	raw, err := p.Attribute(attribute)
+	if err != nil || raw == "" {
+		return 0, false, err
+	}
+	number, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return 0, false, fmt.Errorf("%s is not numeric: %q", attribute, raw)
+	}
+	return number, true, nil
+}
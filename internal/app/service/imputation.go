@@ -0,0 +1,71 @@
+// internal/app/service/imputation.go
+package service
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/shindesatish/titanic-service/internal/app/imputation"
+	"github.com/shindesatish/titanic-service/pkg/model"
+)
+
+// maxCachedImputers bounds the imputers kept for one data version.
+const maxCachedImputers = 32
+
+// imputerKey identifies a fitted imputer: the lowercase strategy name and
+// the grouping attributes in order.
+type imputerKey struct {
+	strategy string
+	by       string
+}
+
+// imputerCache holds the imputers fitted to one data version, dropping them
+// all when the version moves on or the cache is full.
+type imputerCache struct {
+	mu       sync.Mutex
+	version  string
+	imputers map[imputerKey]*imputation.Imputer
+}
+
+// FitImputer fits the named imputation strategy to the whole dataset,
+// grouping passengers by the attributes in by. It returns nil for the
+// "none" strategy or an empty name. Imputers are kept per data version, so
+// only the first call for a strategy and grouping reads the dataset; the
+// Imputer is shared and must not be modified.
+func (s *PassengerService) FitImputer(ctx context.Context, strategy string, by []string) (*imputation.Imputer, error) {
+	if strategy == "" || strings.EqualFold(strategy, imputation.None) {
+		return nil, nil
+	}
+	fitter, err := imputation.NewFitter(strategy, append([]string(nil), by...))
+	if err != nil {
+		return nil, err
+	}
+	version, err := s.GetDataVersion(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &s.imputers
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.imputers == nil || c.version != version || len(c.imputers) >= maxCachedImputers {
+		c.imputers = make(map[imputerKey]*imputation.Imputer)
+		c.version = version
+	}
+	key := imputerKey{strategy: strings.ToLower(strategy), by: strings.Join(by, ",")}
+	if imputer, ok := c.imputers[key]; ok {
+		return imputer, nil
+	}
+
+	err = s.StreamPassengers(ctx, func(p *model.Passenger) error {
+		return fitter.Add(p)
+	})
+	if err != nil {
+		return nil, err
+	}
+	imputer := fitter.Imputer()
+	c.imputers[key] = imputer
+	return imputer, nil
+}
@@ -0,0 +1,51 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/shindesatish/titanic-service/pkg/model"
+)
+
+func TestFitImputerCached(t *testing.T) {
+	repo := &countingRepository{
+		passengers: []model.Passenger{
+			{Pclass: 1, Age: model.NewNullFloat64(40), Embarked: "C"},
+			{Pclass: 3, Age: model.NewNullFloat64(20), Embarked: "S"},
+		},
+		version: "1",
+	}
+	svc := NewPassengerService(repo)
+	ctx := context.Background()
+
+	if imputer, err := svc.FitImputer(ctx, "none", nil); imputer != nil || err != nil {
+		t.Errorf("got %v, %v for none", imputer, err)
+	}
+	if _, err := svc.FitImputer(ctx, "guess", nil); err == nil {
+		t.Error("unknown strategy was accepted")
+	}
+
+	first, err := svc.FitImputer(ctx, "median", []string{"Pclass"})
+	if err != nil {
+		t.Fatalf("FitImputer: %v", err)
+	}
+	second, err := svc.FitImputer(ctx, "Median", []string{"Pclass"})
+	if err != nil {
+		t.Fatalf("FitImputer: %v", err)
+	}
+	if first != second || repo.passes != 1 {
+		t.Errorf("got %d passes and the same imputer %v, want 1 pass and the same imputer", repo.passes, first == second)
+	}
+
+	if _, err := svc.FitImputer(ctx, "median", nil); err != nil {
+		t.Fatalf("FitImputer: %v", err)
+	}
+	repo.version = "2"
+	third, err := svc.FitImputer(ctx, "median", []string{"Pclass"})
+	if err != nil {
+		t.Fatalf("FitImputer: %v", err)
+	}
+	if third == first || repo.passes != 3 {
+		t.Errorf("got %d passes, want a refit for a new grouping and for a new data version", repo.passes)
+	}
+}
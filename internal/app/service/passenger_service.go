@@ -3,19 +3,47 @@ package service
 
 import (
 	"context"
+	"errors"
+	"fmt"
 
+	"github.com/shindesatish/titanic-service/internal/app/prediction"
+	"github.com/shindesatish/titanic-service/internal/app/query"
+	"github.com/shindesatish/titanic-service/internal/app/repository"
+	"github.com/shindesatish/titanic-service/internal/app/validation"
 	"github.com/shindesatish/titanic-service/pkg/model"
 )
 
+// ErrCacheDisabled is returned when cache statistics are requested but the
+// repository is not wrapped in a repository.CachingRepository.
+var ErrCacheDisabled = errors.New("response cache is not enabled")
+
 type Repository interface {
 	GetAllPassengers() ([]model.Passenger, error)
+	ForEachPassenger(fn func(*model.Passenger) error) error
+	ForEachMatchingPassenger(where *query.Expr, fn func(*model.Passenger) error) error
 	GetPassengerByID(passengerID uint) (*model.Passenger, error)
+	GetPassengersByIDs(passengerIDs []uint) ([]model.Passenger, error)
 	GetPassengerAttributes(passengerID uint, attributes []string) (*model.Passenger, error)
 	GetFareHistogram() (map[string]int, error)
+	DataVersion() (string, error)
+	ImportPassengers(passengers []model.Passenger, mode repository.ImportMode, dryRun bool, precondition repository.Precondition) (*repository.ImportResult, error)
+	ValidateDataset() (*validation.Report, error)
+	CrossTabulate(rows, cols, value string, withValues bool) ([]model.CrossTabCell, error)
+	SearchPassengers(query, mode string, limit int) ([]model.SearchHit, error)
 }
 
 type PassengerService struct {
 	Repository Repository
+	// Models stores the trained survival models; prediction is unavailable
+	// when it is nil.
+	Models *prediction.Store
+
+	// partySizes memoizes GetTicketPartySizes per data version.
+	partySizes partySizeCache
+	// evaluations memoizes EvaluateModel per data version.
+	evaluations evaluationCache
+	// imputers memoizes FitImputer per data version.
+	imputers imputerCache
 }
 
 func NewPassengerService(repository Repository) *PassengerService {
@@ -26,6 +54,36 @@ func (s *PassengerService) GetAllPassengers(ctx context.Context) ([]model.Passen
 	return s.Repository.GetAllPassengers()
 }
 
+// StreamPassengers calls fn for every passenger in dataset order without
+// materializing the whole dataset. Returning repository.ErrStopIteration
+// from fn ends the stream early.
+func (s *PassengerService) StreamPassengers(ctx context.Context, fn func(*model.Passenger) error) error {
+	return s.Repository.ForEachPassenger(func(passenger *model.Passenger) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		return fn(passenger)
+	})
+}
+
+// StreamFilteredPassengers is StreamPassengers restricted to the passengers
+// matching both the attribute filter and the where expression, which may
+// be nil.
+func (s *PassengerService) StreamFilteredPassengers(ctx context.Context, filter model.PassengerFilter, where *query.Expr, fn func(*model.Passenger) error) error {
+	if err := filter.Validate(); err != nil {
+		return err
+	}
+	return s.Repository.ForEachMatchingPassenger(where, func(passenger *model.Passenger) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if !filter.Matches(passenger) {
+			return nil
+		}
+		return fn(passenger)
+	})
+}
+
 func (s *PassengerService) GetPassengerByID(ctx context.Context, passengerID uint) (*model.Passenger, error) {
 	return s.Repository.GetPassengerByID(passengerID)
 }
@@ -37,3 +95,52 @@ func (s *PassengerService) GetPassengerAttributes(ctx context.Context, passenger
 func (s *PassengerService) GetFareHistogram(ctx context.Context) (map[string]int, error) {
 	return s.Repository.GetFareHistogram()
 }
+
+// GetDataVersion returns an opaque identifier that changes whenever the
+// underlying passenger dataset changes.
+func (s *PassengerService) GetDataVersion(ctx context.Context) (string, error) {
+	return s.Repository.DataVersion()
+}
+
+// GetCacheStats returns the hit/miss counters of the repository cache.
+func (s *PassengerService) GetCacheStats(ctx context.Context) (*repository.CacheStats, error) {
+	cached, ok := s.Repository.(*repository.CachingRepository)
+	if !ok {
+		return nil, ErrCacheDisabled
+	}
+	stats := cached.CacheStats()
+	return &stats, nil
+}
+
+// ImportPassengers merges already validated passengers into the dataset,
+// provided precondition accepts its version at the time of the write.
+func (s *PassengerService) ImportPassengers(ctx context.Context, passengers []model.Passenger, mode repository.ImportMode, dryRun bool, precondition repository.Precondition) (*repository.ImportResult, error) {
+	if mode != repository.ImportUpsert && mode != repository.ImportReplace {
+		return nil, fmt.Errorf("unknown import mode: %s", mode)
+	}
+	return s.Repository.ImportPassengers(passengers, mode, dryRun, precondition)
+}
+
+// ValidateDataset checks every row of the dataset and reports type errors,
+// out-of-range values, missing required fields and duplicate PassengerIds,
+// along with the lines a lenient repository currently skips.
+func (s *PassengerService) ValidateDataset(ctx context.Context) (*validation.Report, error) {
+	report, err := s.Repository.ValidateDataset()
+	if err != nil {
+		return nil, err
+	}
+	skipped, err := repository.SkippedRows(s.Repository)
+	if err != nil {
+		return nil, err
+	}
+	report.SkippedLines = validation.Lines(skipped)
+	return report, nil
+}
+
+// SearchPassengers finds passengers by Name, Ticket or Cabin.
+func (s *PassengerService) SearchPassengers(ctx context.Context, query, mode string, limit int) ([]model.SearchHit, error) {
+	if mode != model.SearchSubstring && mode != model.SearchToken && mode != model.SearchFuzzy {
+		return nil, fmt.Errorf("unknown search mode: %s", mode)
+	}
+	return s.Repository.SearchPassengers(query, mode, limit)
+}
@@ -0,0 +1,139 @@
+// internal/app/service/prediction.go
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/shindesatish/titanic-service/internal/app/prediction"
+	"github.com/shindesatish/titanic-service/pkg/model"
+)
+
+// DefaultModel is the name of the model used when none is given.
+const DefaultModel = "survival"
+
+// ErrModelsDisabled is returned by the prediction methods when the service
+// has no model store.
+var ErrModelsDisabled = errors.New("model store is not configured")
+
+// SurvivalPrediction is the outcome a model predicts for one passenger.
+type SurvivalPrediction struct {
+	Model               string  `json:"model"`
+	ModelTrainedAt      string  `json:"model_trained_at"`
+	SurvivalProbability float64 `json:"survival_probability"`
+	Survived            int     `json:"survived"`
+}
+
+// TrainModel fits a model to the current dataset and stores it under name,
+// replacing the previous version.
+func (s *PassengerService) TrainModel(ctx context.Context, name string, opts prediction.TrainOptions) (*prediction.Model, error) {
+	if s.Models == nil {
+		return nil, ErrModelsDisabled
+	}
+	passengers, err := s.GetAllPassengers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := prediction.Train(passengers, opts)
+	if err != nil {
+		return nil, err
+	}
+	m.Name = name
+	if version, err := s.GetDataVersion(ctx); err == nil {
+		m.DataVersion = version
+	}
+	if err := s.Models.Save(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// GetModel returns the stored model called name.
+func (s *PassengerService) GetModel(ctx context.Context, name string) (*prediction.Model, error) {
+	if s.Models == nil {
+		return nil, ErrModelsDisabled
+	}
+	return s.Models.Load(name)
+}
+
+// PredictSurvival scores a passenger with the stored model called name.
+// Only Sex, Pclass, Age, Fare, Embarked, SibSp and Parch are used.
+func (s *PassengerService) PredictSurvival(ctx context.Context, name string, passenger *model.Passenger) (*SurvivalPrediction, error) {
+	m, err := s.GetModel(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	probability := m.Predict(passenger)
+	result := &SurvivalPrediction{
+		Model:               m.Name,
+		ModelTrainedAt:      m.TrainedAt.Format(time.RFC3339),
+		SurvivalProbability: probability,
+	}
+	if probability >= 0.5 {
+		result.Survived = 1
+	}
+	return result, nil
+}
+
+// maxCachedEvaluations bounds the evaluations kept for one data version.
+const maxCachedEvaluations = 32
+
+// evaluationKey identifies a cross-validation run. CrossValidate is
+// deterministic, so runs with the same key give the same Evaluation.
+type evaluationKey struct {
+	opts  prediction.TrainOptions
+	folds int
+	seed  int64
+}
+
+// evaluationCache holds the evaluations of one data version, dropping them
+// all when the version moves on or the cache is full.
+type evaluationCache struct {
+	mu          sync.Mutex
+	version     string
+	evaluations map[evaluationKey]prediction.Evaluation
+}
+
+// EvaluateModel cross-validates the training options of the stored model
+// called name over the current dataset. The same seed always gives the same
+// folds, so results are kept per data version, and evaluations run one at a
+// time.
+func (s *PassengerService) EvaluateModel(ctx context.Context, name string, folds int, seed int64) (*prediction.Evaluation, error) {
+	m, err := s.GetModel(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	version, err := s.GetDataVersion(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &s.evaluations
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.evaluations == nil || c.version != version || len(c.evaluations) >= maxCachedEvaluations {
+		c.evaluations = make(map[evaluationKey]prediction.Evaluation)
+		c.version = version
+	}
+	key := evaluationKey{opts: m.Options, folds: folds, seed: seed}
+	evaluation, ok := c.evaluations[key]
+	if !ok {
+		passengers, err := s.GetAllPassengers(ctx)
+		if err != nil {
+			return nil, err
+		}
+		computed, err := prediction.CrossValidate(passengers, m.Options, folds, seed)
+		if err != nil {
+			return nil, err
+		}
+		evaluation = *computed
+		c.evaluations[key] = evaluation
+	}
+	evaluation.Model = m.Name
+	return &evaluation, nil
+}
@@ -0,0 +1,52 @@
+package service
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/shindesatish/titanic-service/internal/app/prediction"
+	"github.com/shindesatish/titanic-service/pkg/model"
+)
+
+func TestEvaluateModelCached(t *testing.T) {
+	passengers := make([]model.Passenger, 40)
+	for i := range passengers {
+		passengers[i] = model.Passenger{PassengerID: i + 1, Pclass: i%3 + 1, Sex: "male", Fare: 10}
+		if i%2 == 0 {
+			passengers[i].Sex = "female"
+			passengers[i].Survived = 1
+		}
+	}
+	repo := &countingRepository{passengers: passengers, version: "1"}
+	svc := NewPassengerService(repo)
+	svc.Models = prediction.NewStore(t.TempDir())
+	ctx := context.Background()
+	if _, err := svc.TrainModel(ctx, "survival", prediction.TrainOptions{LearningRate: 0.1, Epochs: 50}); err != nil {
+		t.Fatalf("TrainModel: %v", err)
+	}
+	repo.passes = 0
+
+	first, err := svc.EvaluateModel(ctx, "survival", 4, 7)
+	if err != nil {
+		t.Fatalf("EvaluateModel: %v", err)
+	}
+	second, err := svc.EvaluateModel(ctx, "survival", 4, 7)
+	if err != nil {
+		t.Fatalf("EvaluateModel: %v", err)
+	}
+	if !reflect.DeepEqual(first, second) || repo.passes != 1 {
+		t.Errorf("got %d passes and equal results %v, want 1 pass and equal results", repo.passes, reflect.DeepEqual(first, second))
+	}
+
+	if _, err := svc.EvaluateModel(ctx, "survival", 5, 7); err != nil {
+		t.Fatalf("EvaluateModel: %v", err)
+	}
+	repo.version = "2"
+	if _, err := svc.EvaluateModel(ctx, "survival", 4, 7); err != nil {
+		t.Fatalf("EvaluateModel: %v", err)
+	}
+	if repo.passes != 3 {
+		t.Errorf("got %d passes, want a recount for new folds and for a new data version", repo.passes)
+	}
+}
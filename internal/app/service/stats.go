@@ -0,0 +1,162 @@
+// internal/app/service/stats.go
+package service
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/shindesatish/titanic-service/internal/app/imputation"
+	"github.com/shindesatish/titanic-service/pkg/model"
+)
+
+// GetSummaryStats computes an overview of the dataset in a single pass, or
+// two when fares are split per person. A non-nil imputer fills in missing
+// Age and Embarked values first.
+func (s *PassengerService) GetSummaryStats(ctx context.Context, basis FareBasis, imputer *imputation.Imputer) (*model.SummaryStats, error) {
+	var sizes map[string]int
+	if basis == FarePerPerson {
+		var err error
+		if sizes, err = s.GetTicketPartySizes(ctx); err != nil {
+			return nil, err
+		}
+	} else {
+		basis = FareRaw
+	}
+
+	stats := &model.SummaryStats{
+		FareBasis: string(basis),
+		Pclass:    make(map[string]int),
+		Sex:       make(map[string]int),
+		Embarked:  make(map[string]int),
+	}
+	var ages, fares, sibSps, parches []float64
+	missingAges := 0
+
+	if imputer != nil {
+		stats.Imputation = imputer.String()
+		stats.Imputed = make(map[string]int)
+	}
+
+	err := s.StreamPassengers(ctx, func(p *model.Passenger) error {
+		if imputer != nil {
+			imputed := *p
+			for _, field := range imputer.Apply(&imputed) {
+				stats.Imputed[field]++
+			}
+			p = &imputed
+		}
+		stats.Passengers++
+		stats.Survivors += p.Survived
+		if p.Age.Valid {
+			ages = append(ages, p.Age.Float64)
+		} else {
+			missingAges++
+		}
+		if sizes != nil {
+			fares = append(fares, model.FarePerPerson(p.Fare, sizes[strings.TrimSpace(p.Ticket)]))
+		} else {
+			fares = append(fares, p.Fare)
+		}
+		sibSps = append(sibSps, float64(p.SibSp))
+		parches = append(parches, float64(p.Parch))
+		stats.Pclass[strconv.Itoa(p.Pclass)]++
+		stats.Sex[p.Sex]++
+		stats.Embarked[p.Embarked]++
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if stats.Passengers > 0 {
+		stats.SurvivalRate = float64(stats.Survivors) / float64(stats.Passengers)
+	}
+	stats.Age = summarize(ages)
+	stats.Age.Missing = missingAges
+	stats.Fare = summarize(fares)
+	stats.SibSp = summarize(sibSps)
+	stats.Parch = summarize(parches)
+	return stats, nil
+}
+
+// GetSurvivalStats breaks survival down by the values of the attribute by.
+func (s *PassengerService) GetSurvivalStats(ctx context.Context, by string) ([]model.SurvivalGroup, error) {
+	groups := make(map[string]*model.SurvivalGroup)
+	err := s.StreamPassengers(ctx, func(p *model.Passenger) error {
+		key, err := p.Attribute(by)
+		if err != nil {
+			return err
+		}
+		group, ok := groups[key]
+		if !ok {
+			group = &model.SurvivalGroup{Group: key}
+			groups[key] = group
+		}
+		group.Passengers++
+		group.Survivors += p.Survived
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]model.SurvivalGroup, 0, len(groups))
+	for _, group := range groups {
+		group.SurvivalRate = float64(group.Survivors) / float64(group.Passengers)
+		result = append(result, *group)
+	}
+	sort.Slice(result, func(i, j int) bool { return lessGroupKey(result[i].Group, result[j].Group) })
+	return result, nil
+}
+
+// summarize computes a NumericSummary of values, which it sorts in place.
+func summarize(values []float64) model.NumericSummary {
+	summary := model.NumericSummary{Count: len(values)}
+	if len(values) == 0 {
+		return summary
+	}
+
+	sort.Float64s(values)
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+	summary.Mean = sum / float64(len(values))
+	if len(values) > 1 {
+		squares := 0.0
+		for _, v := range values {
+			squares += (v - summary.Mean) * (v - summary.Mean)
+		}
+		summary.StdDev = math.Sqrt(squares / float64(len(values)-1))
+	}
+	summary.Min = values[0]
+	summary.Max = values[len(values)-1]
+	summary.Median = median(values)
+	return summary
+}
+
+// median returns the median of sorted values.
+func median(sorted []float64) float64 {
+	n := len(sorted)
+	if n == 0 {
+		return 0
+	}
+	if n%2 == 1 {
+		return sorted[n/2]
+	}
+	return (sorted[n/2-1] + sorted[n/2]) / 2
+}
+
+// lessGroupKey orders group keys numerically when both are numbers and
+// lexically otherwise, so Pclass groups come out as 1, 2, 3.
+func lessGroupKey(a, b string) bool {
+	x, errA := strconv.ParseFloat(a, 64)
+	y, errB := strconv.ParseFloat(b, 64)
+	if errA == nil && errB == nil {
+		return x < y
+	}
+	return a < b
+}
@@ -0,0 +1,134 @@
+// internal/app/service/tickets.go
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+
+	"github.com/shindesatish/titanic-service/internal/app/repository"
+	"github.com/shindesatish/titanic-service/pkg/model"
+)
+
+// FareBasis selects whether fare statistics use the Fare recorded per
+// ticket or that fare split between the passengers sharing the ticket.
+type FareBasis string
+
+const (
+	FareRaw       FareBasis = "raw"
+	FarePerPerson FareBasis = "per_person"
+)
+
+// FareBases lists the accepted FareBasis values.
+var FareBases = []FareBasis{FareRaw, FarePerPerson}
+
+// ErrTicketNotFound is returned when no passenger travelled on the
+// requested ticket.
+var ErrTicketNotFound = errors.New("no passenger travelled on this ticket")
+
+// partySizeCache holds the ticket party sizes together with the data
+// version they were counted from, recounting when the version moves on.
+type partySizeCache struct {
+	mu      sync.Mutex
+	version string
+	sizes   map[string]int
+}
+
+// GetTicketPartySizes counts the passengers travelling on each ticket. The
+// counts are kept until the data version changes, so only the first call
+// per version reads the dataset. The map is shared and must not be
+// modified.
+func (s *PassengerService) GetTicketPartySizes(ctx context.Context) (map[string]int, error) {
+	version, err := s.GetDataVersion(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &s.partySizes
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.sizes == nil || c.version != version {
+		sizes := make(map[string]int)
+		err := s.StreamPassengers(ctx, func(p *model.Passenger) error {
+			sizes[strings.TrimSpace(p.Ticket)]++
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		c.sizes = sizes
+		c.version = version
+	}
+	return c.sizes, nil
+}
+
+// GetTicketGroup returns the passengers travelling on ticket.
+func (s *PassengerService) GetTicketGroup(ctx context.Context, ticket string) (*model.TicketGroup, error) {
+	ticket = strings.TrimSpace(ticket)
+	parsed := model.ParseTicket(ticket)
+	group := &model.TicketGroup{
+		Ticket:       ticket,
+		TicketPrefix: parsed.TicketPrefix,
+		TicketNumber: parsed.TicketNumber,
+		Members:      []model.Passenger{},
+	}
+	err := s.StreamPassengers(ctx, func(p *model.Passenger) error {
+		if strings.TrimSpace(p.Ticket) != ticket {
+			return nil
+		}
+		group.Members = append(group.Members, *p)
+		group.Survivors += p.Survived
+		group.Fare = p.Fare
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(group.Members) == 0 {
+		return nil, ErrTicketNotFound
+	}
+
+	group.Size = len(group.Members)
+	group.FarePerPerson = model.FarePerPerson(group.Fare, group.Size)
+	return group, nil
+}
+
+// GetFareHistogramBy is GetFareHistogram computed over raw or per-person
+// fares.
+func (s *PassengerService) GetFareHistogramBy(ctx context.Context, basis FareBasis) (map[string]int, error) {
+	if basis != FarePerPerson {
+		return s.GetFareHistogram(ctx)
+	}
+	fares, err := s.fares(ctx, basis)
+	if err != nil {
+		return nil, err
+	}
+	return repository.FareHistogram(fares), nil
+}
+
+// fares returns the fare of every passenger on the given basis.
+func (s *PassengerService) fares(ctx context.Context, basis FareBasis) ([]float64, error) {
+	var sizes map[string]int
+	if basis == FarePerPerson {
+		var err error
+		if sizes, err = s.GetTicketPartySizes(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	var fares []float64
+	err := s.StreamPassengers(ctx, func(p *model.Passenger) error {
+		fare := p.Fare
+		if sizes != nil {
+			fare = model.FarePerPerson(p.Fare, sizes[strings.TrimSpace(p.Ticket)])
+		}
+		fares = append(fares, fare)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return fares, nil
+}
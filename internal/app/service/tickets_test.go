@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/shindesatish/titanic-service/pkg/model"
+)
+
+// countingRepository serves a fixed dataset and counts the passes over it.
+type countingRepository struct {
+	Repository
+	passengers []model.Passenger
+	version    string
+	passes     int
+}
+
+func (r *countingRepository) ForEachPassenger(fn func(*model.Passenger) error) error {
+	r.passes++
+	for i := range r.passengers {
+		if err := fn(&r.passengers[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *countingRepository) GetAllPassengers() ([]model.Passenger, error) {
+	r.passes++
+	return append([]model.Passenger(nil), r.passengers...), nil
+}
+
+func (r *countingRepository) DataVersion() (string, error) {
+	return r.version, nil
+}
+
+func TestGetTicketPartySizesMemoized(t *testing.T) {
+	repo := &countingRepository{
+		passengers: []model.Passenger{{Ticket: "A"}, {Ticket: "A "}, {Ticket: "B"}},
+		version:    "1",
+	}
+	svc := NewPassengerService(repo)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		sizes, err := svc.GetTicketPartySizes(ctx)
+		if err != nil {
+			t.Fatalf("GetTicketPartySizes: %v", err)
+		}
+		if want := map[string]int{"A": 2, "B": 1}; !reflect.DeepEqual(sizes, want) {
+			t.Fatalf("got %v, want %v", sizes, want)
+		}
+	}
+	if repo.passes != 1 {
+		t.Errorf("got %d passes for one data version, want 1", repo.passes)
+	}
+
+	repo.passengers = append(repo.passengers, model.Passenger{Ticket: "B"})
+	repo.version = "2"
+	sizes, err := svc.GetTicketPartySizes(ctx)
+	if err != nil {
+		t.Fatalf("GetTicketPartySizes: %v", err)
+	}
+	if sizes["B"] != 2 || repo.passes != 2 {
+		t.Errorf("got B=%d after %d passes, want B=2 after a recount", sizes["B"], repo.passes)
+	}
+}
@@ -0,0 +1,106 @@
+// internal/app/validation/report.go
+package validation
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Report is the data-quality summary of a whole dataset.
+type Report struct {
+	Source        string         `json:"source"`
+	Rows          int            `json:"rows"`
+	ValidRows     int            `json:"valid_rows"`
+	InvalidRows   int            `json:"invalid_rows"`
+	IssuesByCheck map[string]int `json:"issues_by_check"`
+	IssuesByField map[string]int `json:"issues_by_field"`
+	Errors        []RowError     `json:"errors"`
+	// SkippedLines are the lines a lenient load leaves out of the dataset
+	// being served; empty unless the service loads leniently.
+	SkippedLines []int `json:"skipped_lines,omitempty"`
+}
+
+func newReport(source string) *Report {
+	return &Report{
+		Source:        source,
+		IssuesByCheck: make(map[string]int),
+		IssuesByField: make(map[string]int),
+		Errors:        []RowError{},
+	}
+}
+
+// Lines returns the distinct lines of errs in ascending order.
+func Lines(errs []RowError) []int {
+	seen := make(map[int]bool, len(errs))
+	var lines []int
+	for _, err := range errs {
+		if !seen[err.Line] {
+			seen[err.Line] = true
+			lines = append(lines, err.Line)
+		}
+	}
+	sort.Ints(lines)
+	return lines
+}
+
+// Valid reports whether every row passed validation.
+func (r *Report) Valid() bool {
+	return r.InvalidRows == 0
+}
+
+// WriteText writes the report in a human-readable form.
+func (r *Report) WriteText(w io.Writer) error {
+	status := "OK"
+	if !r.Valid() {
+		status = "INVALID"
+	}
+	if _, err := fmt.Fprintf(w, "Data quality report for %s: %s\n", r.Source, status); err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "  rows:         %d\n", r.Rows)
+	fmt.Fprintf(w, "  valid rows:   %d\n", r.ValidRows)
+	fmt.Fprintf(w, "  invalid rows: %d\n", r.InvalidRows)
+	if len(r.SkippedLines) > 0 {
+		lines := make([]string, len(r.SkippedLines))
+		for i, line := range r.SkippedLines {
+			lines[i] = strconv.Itoa(line)
+		}
+		fmt.Fprintf(w, "  skipped rows: %d (lenient load; lines %s)\n", len(r.SkippedLines), strings.Join(lines, ", "))
+	}
+
+	if len(r.IssuesByCheck) > 0 {
+		fmt.Fprintln(w, "\nIssues by check:")
+		for _, key := range sortedKeys(r.IssuesByCheck) {
+			fmt.Fprintf(w, "  %-12s %d\n", key, r.IssuesByCheck[key])
+		}
+	}
+	if len(r.IssuesByField) > 0 {
+		fmt.Fprintln(w, "\nIssues by field:")
+		for _, key := range sortedKeys(r.IssuesByField) {
+			fmt.Fprintf(w, "  %-12s %d\n", key, r.IssuesByField[key])
+		}
+	}
+	if len(r.Errors) > 0 {
+		fmt.Fprintln(w, "\nProblems:")
+		for _, rowErr := range r.Errors {
+			value := ""
+			if rowErr.Value != "" {
+				value = fmt.Sprintf(" (got %q)", rowErr.Value)
+			}
+			fmt.Fprintf(w, "  [%s] %v%s\n", rowErr.Check, rowErr, value)
+		}
+	}
+	return nil
+}
+
+func sortedKeys(m map[string]int) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
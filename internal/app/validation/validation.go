@@ -0,0 +1,386 @@
+// internal/app/validation/validation.go
+package validation
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/shindesatish/titanic-service/pkg/model"
+)
+
+// Kinds of problems a RowError can report
+const (
+	CheckFormat    = "format"
+	CheckType      = "type"
+	CheckRange     = "range"
+	CheckRequired  = "required"
+	CheckDuplicate = "duplicate"
+)
+
+// RowError describes a problem with one field of one input row. Line is the
+// 1-based line of the source file the row starts on, or the row number for
+// sources without lines.
+type RowError struct {
+	Line    int    `json:"line"`
+	Field   string `json:"field,omitempty"`
+	Value   string `json:"value,omitempty"`
+	Check   string `json:"check"`
+	Message string `json:"message"`
+}
+
+func (e RowError) Error() string {
+	if e.Field == "" {
+		return fmt.Sprintf("line %d: %s", e.Line, e.Message)
+	}
+	return fmt.Sprintf("line %d: %s: %s", e.Line, e.Field, e.Message)
+}
+
+// requiredFields are the CSVHeader columns that may not be blank. Age,
+// Cabin and Embarked are legitimately unknown for some passengers.
+var requiredFields = map[string]bool{
+	"PassengerId": true,
+	"Survived":    true,
+	"Pclass":      true,
+	"Name":        true,
+	"Sex":         true,
+	"SibSp":       true,
+	"Parch":       true,
+	"Ticket":      true,
+	"Fare":        true,
+}
+
+// Validator checks rows one at a time while accumulating a Report, so
+// dataset-wide checks such as duplicate PassengerIds work on streams.
+type Validator struct {
+	report *Report
+	seen   map[int]int
+}
+
+func NewValidator(source string) *Validator {
+	return &Validator{
+		report: newReport(source),
+		seen:   make(map[int]int),
+	}
+}
+
+// CheckRecord parses and checks a record in CSVHeader order. The passenger
+// is nil when the record has any problem.
+func (v *Validator) CheckRecord(line int, record []string) (*model.Passenger, []RowError) {
+	if len(record) != len(model.CSVHeader) {
+		return v.add(nil, []RowError{{Line: line, Check: CheckFormat, Message: fmt.Sprintf("expected %d fields, got %d", len(model.CSVHeader), len(record))}})
+	}
+	passenger, errs := parseRecord(line, record)
+	if len(errs) > 0 {
+		return v.add(nil, errs)
+	}
+	return v.CheckPassenger(line, passenger)
+}
+
+// CheckPassenger checks an already typed passenger for out-of-range values
+// and duplicate PassengerIds.
+func (v *Validator) CheckPassenger(line int, passenger *model.Passenger) (*model.Passenger, []RowError) {
+	errs := ValidatePassenger(line, passenger)
+	if first, ok := v.seen[passenger.PassengerID]; ok {
+		errs = append(errs, RowError{Line: line, Field: "PassengerId", Value: strconv.Itoa(passenger.PassengerID), Check: CheckDuplicate, Message: fmt.Sprintf("duplicate of line %d", first)})
+	} else if passenger.PassengerID > 0 {
+		v.seen[passenger.PassengerID] = line
+	}
+	return v.add(passenger, errs)
+}
+
+// AddError records a row that could not be read at all.
+func (v *Validator) AddError(err RowError) {
+	v.add(nil, []RowError{err})
+}
+
+// Report returns the report accumulated so far.
+func (v *Validator) Report() *Report {
+	return v.report
+}
+
+func (v *Validator) add(passenger *model.Passenger, errs []RowError) (*model.Passenger, []RowError) {
+	v.report.Rows++
+	if len(errs) == 0 {
+		v.report.ValidRows++
+		return passenger, nil
+	}
+	v.report.InvalidRows++
+	for _, err := range errs {
+		v.report.Errors = append(v.report.Errors, err)
+		v.report.IssuesByCheck[err.Check]++
+		if err.Field != "" {
+			v.report.IssuesByField[err.Field]++
+		}
+	}
+	return nil, errs
+}
+
+// ValidateCSV checks every row of a titanic.csv style file and reports all
+// problems found. The error is only non-nil when the input as a whole is
+// unreadable.
+func ValidateCSV(source string, r io.Reader) (*Report, error) {
+	validator := NewValidator(source)
+	err := readCSV(r, func(line int, record []string) {
+		validator.CheckRecord(line, record)
+	}, validator.AddError)
+	if err != nil {
+		return nil, err
+	}
+	return validator.Report(), nil
+}
+
+// ParseCSV reads passengers from CSV with a titanic.csv header. Columns may
+// appear in any order but all of them must be present. Rows that fail to
+// parse or validate are reported rather than returned; the error is only
+// non-nil when the input as a whole is unreadable.
+func ParseCSV(r io.Reader) ([]model.Passenger, []RowError, error) {
+	validator := NewValidator("")
+	var passengers []model.Passenger
+	err := readCSV(r, func(line int, record []string) {
+		if passenger, _ := validator.CheckRecord(line, record); passenger != nil {
+			passengers = append(passengers, *passenger)
+		}
+	}, validator.AddError)
+	if err != nil {
+		return nil, nil, err
+	}
+	return passengers, validator.Report().Errors, nil
+}
+
+// readCSV calls fn with every record reordered to CSVHeader order, and
+// onError for records the CSV reader itself rejects.
+func readCSV(r io.Reader, fn func(line int, record []string), onError func(RowError)) error {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return fmt.Errorf("failed to read CSV header: %w", err)
+	}
+	columns := make(map[string]int)
+	for i, name := range header {
+		columns[strings.TrimSpace(name)] = i
+	}
+	var missing []string
+	for _, name := range model.CSVHeader {
+		if _, ok := columns[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("CSV header is missing columns: %s", strings.Join(missing, ", "))
+	}
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			if parseErr, ok := err.(*csv.ParseError); ok {
+				onError(RowError{Line: parseErr.StartLine, Check: CheckFormat, Message: parseErr.Err.Error()})
+				continue
+			}
+			return fmt.Errorf("failed to read CSV records: %w", err)
+		}
+		line, _ := reader.FieldPos(0)
+		if len(record) != len(header) {
+			onError(RowError{Line: line, Check: CheckFormat, Message: fmt.Sprintf("expected %d fields, got %d", len(header), len(record))})
+			continue
+		}
+
+		fields := make([]string, len(model.CSVHeader))
+		for i, name := range model.CSVHeader {
+			fields[i] = record[columns[name]]
+		}
+		fn(line, fields)
+	}
+}
+
+// ParseJSON reads passengers from a JSON array of objects using the same
+// field names as the API responses. Objects that omit a required field are
+// reported with a RowError per missing field rather than read as zero.
+func ParseJSON(r io.Reader) ([]model.Passenger, []RowError, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read JSON body: %w", err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	if token, err := decoder.Token(); err != nil || token != json.Delim('[') {
+		return nil, nil, fmt.Errorf("expected a JSON array of passengers")
+	}
+
+	validator := NewValidator("")
+	var passengers []model.Passenger
+	for decoder.More() {
+		line := lineAt(data, decoder.InputOffset())
+		var raw json.RawMessage
+		if err := decoder.Decode(&raw); err != nil {
+			return nil, nil, fmt.Errorf("line %d: malformed JSON: %v", line, err)
+		}
+
+		var passenger model.Passenger
+		strict := json.NewDecoder(bytes.NewReader(raw))
+		strict.DisallowUnknownFields()
+		if err := strict.Decode(&passenger); err != nil {
+			validator.AddError(RowError{Line: line, Check: CheckType, Message: err.Error()})
+			continue
+		}
+		// Omitted numbers decode as 0, which is valid for Survived, SibSp,
+		// Parch and Fare, so required fields are checked by key
+		if missing := missingFields(line, raw); len(missing) > 0 {
+			validator.add(nil, missing)
+			continue
+		}
+		if checked, _ := validator.CheckPassenger(line, &passenger); checked != nil {
+			passengers = append(passengers, *checked)
+		}
+	}
+	if _, err := decoder.Token(); err != nil {
+		return nil, nil, fmt.Errorf("malformed JSON array: %v", err)
+	}
+
+	return passengers, validator.Report().Errors, nil
+}
+
+// missingFields reports the required fields a JSON passenger object omits or
+// sets to null.
+func missingFields(line int, raw json.RawMessage) []RowError {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return []RowError{{Line: line, Check: CheckType, Message: err.Error()}}
+	}
+	var errs []RowError
+	for _, name := range model.CSVHeader {
+		if !requiredFields[name] {
+			continue
+		}
+		if value, ok := fields[name]; !ok || string(bytes.TrimSpace(value)) == "null" {
+			errs = append(errs, RowError{Line: line, Field: name, Check: CheckRequired, Message: "is required"})
+		}
+	}
+	return errs
+}
+
+// ValidatePassenger checks a parsed passenger for out-of-range values and
+// missing required fields.
+func ValidatePassenger(line int, p *model.Passenger) []RowError {
+	var errs []RowError
+	fail := func(field string, value interface{}, check, message string) {
+		errs = append(errs, RowError{Line: line, Field: field, Value: fmt.Sprint(value), Check: check, Message: message})
+	}
+
+	if p.PassengerID <= 0 {
+		fail("PassengerId", p.PassengerID, CheckRange, "must be a positive integer")
+	}
+	if p.Survived != 0 && p.Survived != 1 {
+		fail("Survived", p.Survived, CheckRange, "must be 0 or 1")
+	}
+	if p.Pclass < 1 || p.Pclass > 3 {
+		fail("Pclass", p.Pclass, CheckRange, "must be 1, 2 or 3")
+	}
+	if strings.TrimSpace(p.Name) == "" {
+		fail("Name", p.Name, CheckRequired, "is required")
+	}
+	if p.Sex == "" {
+		fail("Sex", p.Sex, CheckRequired, "is required")
+	} else if p.Sex != "male" && p.Sex != "female" {
+		fail("Sex", p.Sex, CheckRange, "must be male or female")
+	}
+	if p.Age.Valid && p.Age.Float64 < 0 {
+		fail("Age", p.Age.Float64, CheckRange, "must not be negative")
+	}
+	if p.SibSp < 0 {
+		fail("SibSp", p.SibSp, CheckRange, "must not be negative")
+	}
+	if p.Parch < 0 {
+		fail("Parch", p.Parch, CheckRange, "must not be negative")
+	}
+	if strings.TrimSpace(p.Ticket) == "" {
+		fail("Ticket", p.Ticket, CheckRequired, "is required")
+	}
+	if p.Fare < 0 {
+		fail("Fare", p.Fare, CheckRange, "must not be negative")
+	}
+	if p.Embarked != "" && p.Embarked != "C" && p.Embarked != "Q" && p.Embarked != "S" {
+		fail("Embarked", p.Embarked, CheckRange, "must be C, Q or S")
+	}
+
+	return errs
+}
+
+// parseRecord converts a record in CSVHeader order, reporting every field
+// that is missing or fails to parse rather than stopping at the first.
+func parseRecord(line int, record []string) (*model.Passenger, []RowError) {
+	var errs []RowError
+	present := func(column int) bool {
+		if strings.TrimSpace(record[column]) != "" {
+			return true
+		}
+		if requiredFields[model.CSVHeader[column]] {
+			errs = append(errs, RowError{Line: line, Field: model.CSVHeader[column], Check: CheckRequired, Message: "is required"})
+		}
+		return false
+	}
+	parseInt := func(column int) int {
+		if !present(column) {
+			return 0
+		}
+		value, err := strconv.Atoi(strings.TrimSpace(record[column]))
+		if err != nil {
+			errs = append(errs, RowError{Line: line, Field: model.CSVHeader[column], Value: record[column], Check: CheckType, Message: "must be an integer"})
+		}
+		return value
+	}
+	parseFloat := func(column int) float64 {
+		if !present(column) {
+			return 0
+		}
+		value, err := strconv.ParseFloat(strings.TrimSpace(record[column]), 64)
+		if err != nil {
+			errs = append(errs, RowError{Line: line, Field: model.CSVHeader[column], Value: record[column], Check: CheckType, Message: "must be a number"})
+		}
+		return value
+	}
+	parseNullFloat := func(column int) model.NullFloat64 {
+		if strings.TrimSpace(record[column]) == "" {
+			return model.NullFloat64{}
+		}
+		return model.NewNullFloat64(parseFloat(column))
+	}
+	parseString := func(column int) string {
+		present(column)
+		return record[column]
+	}
+
+	passenger := &model.Passenger{
+		PassengerID: parseInt(0),
+		Survived:    parseInt(1),
+		Pclass:      parseInt(2),
+		Name:        parseString(3),
+		Sex:         parseString(4),
+		Age:         parseNullFloat(5),
+		SibSp:       parseInt(6),
+		Parch:       parseInt(7),
+		Ticket:      parseString(8),
+		Fare:        parseFloat(9),
+		Cabin:       record[10],
+		Embarked:    record[11],
+	}
+	return passenger, errs
+}
+
+// lineAt returns the 1-based line number of offset in data, skipping any
+// whitespace or separator before the next value.
+func lineAt(data []byte, offset int64) int {
+	for offset < int64(len(data)) && strings.ContainsRune(" \t\r\n,", rune(data[offset])) {
+		offset++
+	}
+	return bytes.Count(data[:offset], []byte("\n")) + 1
+}
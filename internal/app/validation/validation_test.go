@@ -0,0 +1,59 @@
+package validation
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseJSONMissingFields(t *testing.T) {
+	input := `[
+  {"PassengerId": 1, "Survived": 0, "Pclass": 3, "Name": "Braund, Mr. Owen Harris", "Sex": "male", "Age": 22, "SibSp": 1, "Parch": 0, "Ticket": "A/5 21171", "Fare": 7.25, "Cabin": "", "Embarked": "S"},
+  {"PassengerId": 2, "Pclass": 1, "Name": "Cumings, Mrs. John Bradley", "Sex": "female", "Age": 38, "Parch": 0, "Ticket": "PC 17599", "Fare": null}
+]`
+
+	passengers, rowErrors, err := ParseJSON(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ParseJSON: %v", err)
+	}
+	if len(passengers) != 1 || passengers[0].PassengerID != 1 {
+		t.Errorf("got passengers %+v, want only PassengerId 1", passengers)
+	}
+
+	want := []RowError{
+		{Line: 3, Field: "Survived", Check: CheckRequired, Message: "is required"},
+		{Line: 3, Field: "SibSp", Check: CheckRequired, Message: "is required"},
+		{Line: 3, Field: "Fare", Check: CheckRequired, Message: "is required"},
+	}
+	if !reflect.DeepEqual(rowErrors, want) {
+		t.Errorf("got row errors %+v, want %+v", rowErrors, want)
+	}
+}
+
+func TestReportSkippedLines(t *testing.T) {
+	errs := []RowError{{Line: 7, Check: CheckFormat}, {Line: 3, Field: "Age"}, {Line: 7, Field: "Fare"}, {Line: 5}}
+	if lines := Lines(errs); !reflect.DeepEqual(lines, []int{3, 5, 7}) {
+		t.Errorf("got lines %v", lines)
+	}
+	if lines := Lines(nil); lines != nil {
+		t.Errorf("got lines %v for no errors", lines)
+	}
+
+	report := newReport("titanic.csv")
+	var text strings.Builder
+	if err := report.WriteText(&text); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(text.String(), "skipped") {
+		t.Errorf("strict report mentions skipped rows:\n%s", text.String())
+	}
+
+	report.SkippedLines = []int{3, 5, 7}
+	text.Reset()
+	if err := report.WriteText(&text); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(text.String(), "skipped rows: 3 (lenient load; lines 3, 5, 7)") {
+		t.Errorf("got report:\n%s", text.String())
+	}
+}
@@ -0,0 +1,160 @@
+// Package client is a typed Go client for the Titanic passenger API.
+//
+//	c, err := client.New("http://localhost:8080/v1")
+//	passengers, err := c.ListPassengers(ctx, client.ListOptions{Filter: "Age < 12"})
+//
+// Every method takes a context that bounds the whole call, retries
+// included. Requests that are safe to repeat are retried with exponential
+// backoff when the server answers 429 or a 5xx status; see RetryPolicy.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Client calls the API under a base URL such as http://localhost:8080/v1.
+// It is safe for concurrent use.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	retry      RetryPolicy
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithHTTPClient sends requests through httpClient instead of
+// http.DefaultClient, e.g. to set timeouts, proxies or TLS configuration.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithRetryPolicy replaces DefaultRetryPolicy.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *Client) {
+		c.retry = policy
+	}
+}
+
+// New returns a client for the API rooted at baseURL.
+func New(baseURL string, opts ...Option) (*Client, error) {
+	baseURL = strings.TrimRight(baseURL, "/")
+	parsed, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base URL: %v", err)
+	}
+	if parsed.Scheme == "" || parsed.Host == "" || parsed.RawQuery != "" {
+		return nil, fmt.Errorf("invalid base URL %q: expected scheme, host and path only", baseURL)
+	}
+
+	c := &Client{baseURL: baseURL, httpClient: http.DefaultClient, retry: DefaultRetryPolicy}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c, nil
+}
+
+// request describes one API call. path is relative to the base URL and
+// already escaped.
+type request struct {
+	method string
+	path   string
+	query  url.Values
+	header http.Header
+	body   []byte
+	// idempotent requests are retried on 5xx responses as well as on 429.
+	idempotent bool
+}
+
+// url joins the base URL, the already escaped path and the query.
+func (c *Client) url(path string, query url.Values) string {
+	target := c.baseURL + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+	return target
+}
+
+// do sends the request, retrying as the policy allows, and returns the
+// response of the last attempt. Non-2xx responses are returned as an
+// *APIError with the body closed.
+func (c *Client) do(ctx context.Context, r request) (*http.Response, error) {
+	target := c.url(r.path, r.query)
+	for attempt := 1; ; attempt++ {
+		var body io.Reader
+		if r.body != nil {
+			body = bytes.NewReader(r.body)
+		}
+		req, err := http.NewRequestWithContext(ctx, r.method, target, body)
+		if err != nil {
+			return nil, err
+		}
+		for key, values := range r.header {
+			req.Header[key] = values
+		}
+
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			// Transport errors are retried for idempotent requests only,
+			// since the server may have acted on the request
+			if ctx.Err() != nil || !r.idempotent || attempt >= c.retry.MaxAttempts {
+				return nil, err
+			}
+			if err := c.retry.wait(ctx, attempt, nil); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+			return resp, nil
+		}
+
+		apiErr := newAPIError(resp)
+		if !c.retry.retryable(resp.StatusCode, r.idempotent) || attempt >= c.retry.MaxAttempts {
+			return nil, apiErr
+		}
+		if err := c.retry.wait(ctx, attempt, resp); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// getJSON sends a GET request and decodes the JSON response into out.
+func (c *Client) getJSON(ctx context.Context, path string, query url.Values, out interface{}) error {
+	return c.doJSON(ctx, request{method: http.MethodGet, path: path, query: query, idempotent: true}, out)
+}
+
+// postJSON sends in as a JSON body and decodes the JSON response into out.
+func (c *Client) postJSON(ctx context.Context, path string, query url.Values, in, out interface{}, idempotent bool) error {
+	body, err := json.Marshal(in)
+	if err != nil {
+		return fmt.Errorf("failed to encode request: %v", err)
+	}
+	header := http.Header{"Content-Type": {"application/json"}}
+	return c.doJSON(ctx, request{method: http.MethodPost, path: path, query: query, header: header, body: body, idempotent: idempotent}, out)
+}
+
+func (c *Client) doJSON(ctx context.Context, r request, out interface{}) error {
+	if r.header == nil {
+		r.header = http.Header{}
+	}
+	r.header.Set("Accept", "application/json")
+	resp, err := c.do(ctx, r)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode %s response: %v", r.path, err)
+	}
+	return nil
+}
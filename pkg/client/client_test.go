@@ -0,0 +1,321 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shindesatish/titanic-service/internal/app/repository"
+	"github.com/shindesatish/titanic-service/internal/app/server"
+	"github.com/shindesatish/titanic-service/internal/app/service"
+	"github.com/shindesatish/titanic-service/pkg/model"
+)
+
+// backends lists the repository options the tests run against.
+var backends = map[string]repository.Options{
+	"csv":    {CSVPath: "titanic.csv"},
+	"sqlite": {UseSQLite: true, SQLitePath: "titanic.db"},
+}
+
+// newTestClient serves the real router from a temporary copy of the
+// dataset, so the tests never touch datastore/, and returns a client for
+// it together with the service behind it.
+func newTestClient(t *testing.T, opts repository.Options) (*Client, *service.PassengerService) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	dir := t.TempDir()
+	for _, name := range []string{"titanic.csv", "titanic.db"} {
+		data, err := os.ReadFile(filepath.Join("..", "..", "datastore", name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	opts.CSVPath = filepath.Join(dir, opts.CSVPath)
+	opts.SQLitePath = filepath.Join(dir, opts.SQLitePath)
+	repo, closeRepo, err := repository.Open(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { closeRepo() })
+
+	passengerService := service.NewPassengerService(repo)
+	router, err := server.New(server.Config{Validate: true}, passengerService)
+	if err != nil {
+		t.Fatal(err)
+	}
+	api := httptest.NewServer(router)
+	t.Cleanup(api.Close)
+
+	c, err := New(api.URL+"/v1", WithRetryPolicy(NoRetries))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c, passengerService
+}
+
+func TestListPassengers(t *testing.T) {
+	tests := []struct {
+		name  string
+		opts  ListOptions
+		count int
+	}{
+		{"all", ListOptions{}, 891},
+		{"filter expression", ListOptions{Filter: "Age < 12"}, 68},
+		{"attribute filter", ListOptions{Attributes: map[string][]string{"Pclass": {"1"}, "Sex": {"female"}, "Embarked": {"C"}}}, 43},
+		{"attribute values", ListOptions{Attributes: map[string][]string{"Pclass": {"1", "2"}}}, 216 + 184},
+	}
+	for name, backend := range backends {
+		t.Run(name, func(t *testing.T) {
+			c, _ := newTestClient(t, backend)
+			for _, tt := range tests {
+				passengers, err := c.ListPassengers(context.Background(), tt.opts)
+				if err != nil {
+					t.Fatalf("%s: %v", tt.name, err)
+				}
+				if len(passengers) != tt.count {
+					t.Errorf("%s: got %d passengers, want %d", tt.name, len(passengers), tt.count)
+				}
+			}
+
+			passengers, err := c.ListPassengers(context.Background(), ListOptions{Filter: "PassengerId = 6", Imputation: Imputation{Strategy: "median"}})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(passengers) != 1 || !passengers[0].Age.Valid || len(passengers[0].Imputed) == 0 {
+				t.Errorf("got %+v, want passenger 6 with an imputed Age", passengers)
+			}
+
+			var apiErr *APIError
+			if _, err := c.ListPassengers(context.Background(), ListOptions{Filter: "Age <"}); !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest {
+				t.Errorf("malformed filter: got %v, want a 400 APIError", err)
+			}
+		})
+	}
+}
+
+func TestGetPassenger(t *testing.T) {
+	for name, backend := range backends {
+		t.Run(name, func(t *testing.T) {
+			c, _ := newTestClient(t, backend)
+			ctx := context.Background()
+
+			passenger, err := c.GetPassenger(ctx, 1, Imputation{})
+			if err != nil {
+				t.Fatalf("GetPassenger: %v", err)
+			}
+			if passenger.Name != "Braund, Mr. Owen Harris" || passenger.Age != model.NewNullFloat64(22) || passenger.Title != "Mr" || passenger.TicketPartySize != 1 {
+				t.Errorf("got %+v", passenger)
+			}
+
+			if _, err := c.GetPassenger(ctx, 9999, Imputation{}); !IsNotFound(err) {
+				t.Errorf("GetPassenger(9999): got %v, want a 404", err)
+			}
+
+			attributes, err := c.GetPassengerAttributes(ctx, 2, "Name", "Cabin")
+			if err != nil {
+				t.Fatalf("GetPassengerAttributes: %v", err)
+			}
+			if attributes.Name != "Cumings, Mrs. John Bradley (Florence Briggs Thayer)" || attributes.Cabin != "C85" || attributes.Ticket != "" {
+				t.Errorf("got attributes %+v", attributes)
+			}
+
+			batch, err := c.GetPassengers(ctx, []int{3, 1, 9999}, Imputation{})
+			if err != nil {
+				t.Fatalf("GetPassengers: %v", err)
+			}
+			if len(batch.Passengers) != 2 || batch.Passengers[0].PassengerID != 3 || batch.Passengers[1].PassengerID != 1 || len(batch.MissingIDs) != 1 || batch.MissingIDs[0] != 9999 {
+				t.Errorf("got batch %+v", batch)
+			}
+		})
+	}
+}
+
+func TestStats(t *testing.T) {
+	for name, backend := range backends {
+		t.Run(name, func(t *testing.T) {
+			c, passengerService := newTestClient(t, backend)
+			ctx := context.Background()
+
+			for _, basis := range []service.FareBasis{service.FareRaw, service.FarePerPerson} {
+				histogram, err := c.GetFareHistogram(ctx, string(basis))
+				if err != nil {
+					t.Fatalf("GetFareHistogram(%s): %v", basis, err)
+				}
+				want, err := passengerService.GetFareHistogramBy(ctx, basis)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(histogram) != len(want) {
+					t.Errorf("GetFareHistogram(%s): got %v, want %v", basis, histogram, want)
+				}
+				for bucket, count := range want {
+					if histogram[bucket] != count {
+						t.Errorf("GetFareHistogram(%s): bucket %s has %d passengers, want %d", basis, bucket, histogram[bucket], count)
+					}
+				}
+			}
+
+			summary, err := c.GetSummaryStats(ctx, SummaryOptions{})
+			if err != nil {
+				t.Fatalf("GetSummaryStats: %v", err)
+			}
+			if summary.Passengers != 891 || summary.Survivors != 342 || summary.Age.Missing != 177 || summary.Sex["female"] != 314 {
+				t.Errorf("got summary %+v", summary)
+			}
+
+			groups, err := c.GetSurvivalStats(ctx, "Sex")
+			if err != nil {
+				t.Fatalf("GetSurvivalStats: %v", err)
+			}
+			survivors := map[string]int{}
+			for _, group := range groups {
+				survivors[group.Group] = group.Survivors
+			}
+			if survivors["female"] != 233 || survivors["male"] != 109 {
+				t.Errorf("got survival groups %+v", groups)
+			}
+		})
+	}
+}
+
+// flakyServer answers each request with the next status in statuses, and
+// 200 with an empty JSON object once they run out.
+type flakyServer struct {
+	statuses   []int
+	retryAfter string
+	calls      int32
+}
+
+func (s *flakyServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	call := int(atomic.AddInt32(&s.calls, 1))
+	if call > len(s.statuses) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{}`))
+		return
+	}
+	if s.retryAfter != "" {
+		w.Header().Set("Retry-After", s.retryAfter)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(s.statuses[call-1])
+	w.Write([]byte(`{"error": "` + http.StatusText(s.statuses[call-1]) + `"}`))
+}
+
+func TestRetries(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: 5 * time.Millisecond}
+	read := func(ctx context.Context, c *Client) error {
+		_, err := c.GetSummaryStats(ctx, SummaryOptions{})
+		return err
+	}
+	importPassengers := func(ctx context.Context, c *Client) error {
+		_, err := c.Import(ctx, []model.Passenger{{PassengerID: 1}}, ImportOptions{})
+		return err
+	}
+
+	tests := []struct {
+		name     string
+		call     func(context.Context, *Client) error
+		statuses []int
+		calls    int32
+		status   int
+	}{
+		{"read retried on 5xx", read, []int{503, 500}, 3, 0},
+		{"read retried on 429", read, []int{429}, 2, 0},
+		{"read gives up after MaxAttempts", read, []int{502, 502, 502, 502}, 3, 502},
+		{"read not retried on 400", read, []int{400}, 1, 400},
+		{"read not retried on 404", read, []int{404}, 1, 404},
+		{"import not retried on 5xx", importPassengers, []int{503}, 1, 503},
+		{"import retried on 429", importPassengers, []int{429}, 2, 0},
+		{"import not retried on 409", importPassengers, []int{409}, 1, 409},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flaky := &flakyServer{statuses: tt.statuses}
+			api := httptest.NewServer(flaky)
+			defer api.Close()
+			c, err := New(api.URL+"/v1", WithRetryPolicy(policy))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = tt.call(context.Background(), c)
+			if tt.status == 0 && err != nil {
+				t.Errorf("got %v, want success", err)
+			}
+			var apiErr *APIError
+			if tt.status != 0 && (!errors.As(err, &apiErr) || apiErr.StatusCode != tt.status) {
+				t.Errorf("got %v, want a %d APIError", err, tt.status)
+			}
+			if flaky.calls != tt.calls {
+				t.Errorf("got %d calls, want %d", flaky.calls, tt.calls)
+			}
+		})
+	}
+}
+
+func TestRetryBackoff(t *testing.T) {
+	// Retry-After is honoured but capped at MaxBackoff
+	flaky := &flakyServer{statuses: []int{503, 503}, retryAfter: "60"}
+	api := httptest.NewServer(flaky)
+	defer api.Close()
+	c, err := New(api.URL+"/v1", WithRetryPolicy(RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: 50 * time.Millisecond}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now()
+	if _, err := c.GetSummaryStats(context.Background(), SummaryOptions{}); err != nil {
+		t.Fatalf("GetSummaryStats: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond || elapsed > 5*time.Second {
+		t.Errorf("two retries took %v, want two waits of 50ms", elapsed)
+	}
+
+	// The context bounds the waits too
+	flaky = &flakyServer{statuses: []int{503, 503}, retryAfter: "60"}
+	api2 := httptest.NewServer(flaky)
+	defer api2.Close()
+	c, err = New(api2.URL+"/v1", WithRetryPolicy(RetryPolicy{MaxAttempts: 3, MaxBackoff: time.Minute}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := c.GetSummaryStats(ctx, SummaryOptions{}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("got %v, want the context deadline", err)
+	}
+	if flaky.calls != 1 {
+		t.Errorf("got %d calls, want 1 before the deadline", flaky.calls)
+	}
+}
+
+func TestBackoffBounds(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 5, InitialBackoff: 10 * time.Millisecond, MaxBackoff: 40 * time.Millisecond}
+	bounds := []time.Duration{10 * time.Millisecond, 20 * time.Millisecond, 40 * time.Millisecond, 40 * time.Millisecond}
+	for i, bound := range bounds {
+		for j := 0; j < 100; j++ {
+			if wait := policy.backoff(i+1, nil); wait < 0 || wait > bound {
+				t.Fatalf("backoff after attempt %d = %v, want at most %v", i+1, wait, bound)
+			}
+		}
+	}
+
+	resp := &http.Response{Header: http.Header{"Retry-After": {"1"}}}
+	if wait := (RetryPolicy{MaxBackoff: 5 * time.Second}).backoff(1, resp); wait != time.Second {
+		t.Errorf("backoff with Retry-After: 1 = %v, want 1s", wait)
+	}
+	if !strings.HasPrefix((&APIError{StatusCode: 503, Message: "down"}).Error(), "titanic API: 503") {
+		t.Errorf("unexpected APIError format")
+	}
+}
@@ -0,0 +1,64 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// maxErrorBody caps how much of an error response is kept.
+const maxErrorBody = 1 << 20
+
+// APIError is a non-2xx response from the API.
+type APIError struct {
+	StatusCode int
+	// Message is the "error" field of the response body, or the status
+	// text when the body has none.
+	Message string
+	// RowErrors lists the invalid rows of a rejected import.
+	RowErrors []RowError
+	// Body is the raw response body, which may carry more detail such as
+	// the allowed values of a parameter.
+	Body []byte
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("titanic API: %d %s", e.StatusCode, e.Message)
+}
+
+// newAPIError reads and closes the body of an error response.
+func newAPIError(resp *http.Response) *APIError {
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+
+	apiErr := &APIError{StatusCode: resp.StatusCode, Body: body}
+	var decoded struct {
+		Error  string     `json:"error"`
+		Errors []RowError `json:"errors"`
+	}
+	if json.Unmarshal(body, &decoded) == nil && decoded.Error != "" {
+		apiErr.Message = decoded.Error
+		apiErr.RowErrors = decoded.Errors
+	} else {
+		apiErr.Message = http.StatusText(resp.StatusCode)
+	}
+	return apiErr
+}
+
+// IsNotFound reports whether err is a 404 response.
+func IsNotFound(err error) bool {
+	return hasStatus(err, http.StatusNotFound)
+}
+
+// IsPreconditionFailed reports whether err is a 412 response, returned
+// when an If-Match precondition no longer holds.
+func IsPreconditionFailed(err error) bool {
+	return hasStatus(err, http.StatusPreconditionFailed)
+}
+
+func hasStatus(err error, status int) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == status
+}
@@ -0,0 +1,234 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/shindesatish/titanic-service/pkg/model"
+)
+
+// ListOptions selects and shapes the passengers of ListPassengers and
+// Export.
+type ListOptions struct {
+	// Attributes keeps the passengers whose attribute equals one of the
+	// listed values, e.g. {"Pclass": {"1", "2"}, "Sex": {"female"}}.
+	Attributes map[string][]string
+	// Filter is a filter expression such as
+	// "Age < 12 OR (Sex = 'female' AND Pclass IN (1, 2))".
+	Filter string
+	Imputation
+}
+
+// Imputation fills in missing Age and Embarked values on the way out.
+type Imputation struct {
+	// Strategy is mean, median, mode or none (the default).
+	Strategy string
+	// By computes the fill value within groups of these attributes.
+	By []string
+}
+
+func (o ListOptions) values() url.Values {
+	query := o.Imputation.values()
+	for attribute, values := range o.Attributes {
+		query.Set(attribute, strings.Join(values, ","))
+	}
+	if o.Filter != "" {
+		query.Set("filter", o.Filter)
+	}
+	return query
+}
+
+func (i Imputation) values() url.Values {
+	query := url.Values{}
+	if i.Strategy != "" {
+		query.Set("impute", i.Strategy)
+	}
+	if len(i.By) > 0 {
+		query.Set("impute_by", strings.Join(i.By, ","))
+	}
+	return query
+}
+
+// ListPassengers returns the passengers matching opts, in dataset order.
+func (c *Client) ListPassengers(ctx context.Context, opts ListOptions) ([]Passenger, error) {
+	var passengers []Passenger
+	if err := c.getJSON(ctx, "/passengers", opts.values(), &passengers); err != nil {
+		return nil, err
+	}
+	return passengers, nil
+}
+
+// GetPassenger looks up a passenger by PassengerId.
+func (c *Client) GetPassenger(ctx context.Context, passengerID int, imputation Imputation) (*Passenger, error) {
+	var passenger Passenger
+	if err := c.getJSON(ctx, "/passengers/"+strconv.Itoa(passengerID), imputation.values(), &passenger); err != nil {
+		return nil, err
+	}
+	return &passenger, nil
+}
+
+// GetPassengerAttributes returns a passenger with only the named titanic.csv
+// attributes set.
+func (c *Client) GetPassengerAttributes(ctx context.Context, passengerID int, attributes ...string) (*model.Passenger, error) {
+	query := url.Values{"attributes": attributes}
+	var passenger model.Passenger
+	if err := c.getJSON(ctx, "/passenger-attributes/"+strconv.Itoa(passengerID), query, &passenger); err != nil {
+		return nil, err
+	}
+	return &passenger, nil
+}
+
+// SearchOptions tune SearchPassengers.
+type SearchOptions struct {
+	// Mode is one of model.SearchModes; the server defaults to token.
+	Mode string
+	// Limit caps the number of results; the server defaults to 20.
+	Limit int
+}
+
+// SearchPassengers finds passengers by Name, Ticket or Cabin, best matches
+// first.
+func (c *Client) SearchPassengers(ctx context.Context, text string, opts SearchOptions) ([]SearchResult, error) {
+	query := url.Values{"q": {text}}
+	if opts.Mode != "" {
+		query.Set("mode", opts.Mode)
+	}
+	if opts.Limit > 0 {
+		query.Set("limit", strconv.Itoa(opts.Limit))
+	}
+	var results []SearchResult
+	if err := c.getJSON(ctx, "/passengers/search", query, &results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// GetPassengerGroup returns the family or travel party of a passenger.
+func (c *Client) GetPassengerGroup(ctx context.Context, passengerID int) (*model.TravelGroup, error) {
+	var group model.TravelGroup
+	if err := c.getJSON(ctx, "/passengers/"+strconv.Itoa(passengerID)+"/group", nil, &group); err != nil {
+		return nil, err
+	}
+	return &group, nil
+}
+
+// ListTravelGroups returns the reconstructed families and travel parties
+// with at least minSize members; zero uses the server default of 2.
+func (c *Client) ListTravelGroups(ctx context.Context, minSize int) ([]model.TravelGroup, error) {
+	query := url.Values{}
+	if minSize > 0 {
+		query.Set("min_size", strconv.Itoa(minSize))
+	}
+	var groups []model.TravelGroup
+	if err := c.getJSON(ctx, "/groups", query, &groups); err != nil {
+		return nil, err
+	}
+	return groups, nil
+}
+
+// GetTicketGroup lists the passengers travelling on a ticket, given as in
+// titanic.csv (e.g. "A/5 21171").
+func (c *Client) GetTicketGroup(ctx context.Context, ticket string) (*model.TicketGroup, error) {
+	segments := strings.Split(ticket, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	var group model.TicketGroup
+	if err := c.getJSON(ctx, "/tickets/"+strings.Join(segments, "/"), nil, &group); err != nil {
+		return nil, err
+	}
+	return &group, nil
+}
+
+// Export streams the passengers matching opts in format (parquet, arrow,
+// csv, json or ndjson). The caller must close the returned body.
+func (c *Client) Export(ctx context.Context, format string, opts ListOptions) (io.ReadCloser, error) {
+	query := opts.values()
+	query.Set("format", format)
+	resp, err := c.do(ctx, request{method: http.MethodGet, path: "/export", query: query, idempotent: true})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// ImportOptions control Import.
+type ImportOptions struct {
+	// Mode is upsert (the default) or replace.
+	Mode string
+	// DryRun reports the changes without applying them.
+	DryRun bool
+	// IfMatch rejects the import with a 412 unless the dataset still has
+	// this ETag.
+	IfMatch string
+}
+
+// Import loads passengers into the dataset. Imports are not retried on
+// server errors, as they may have been applied.
+func (c *Client) Import(ctx context.Context, passengers []model.Passenger, opts ImportOptions) (*ImportResult, error) {
+	body, err := json.Marshal(passengers)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode passengers: %v", err)
+	}
+	return c.importBody(ctx, "application/json", body, opts)
+}
+
+// ImportCSV loads passengers from a CSV file with the titanic.csv header.
+func (c *Client) ImportCSV(ctx context.Context, csv io.Reader, opts ImportOptions) (*ImportResult, error) {
+	var body bytes.Buffer
+	if _, err := body.ReadFrom(csv); err != nil {
+		return nil, fmt.Errorf("failed to read CSV: %v", err)
+	}
+	return c.importBody(ctx, "text/csv", body.Bytes(), opts)
+}
+
+func (c *Client) importBody(ctx context.Context, contentType string, body []byte, opts ImportOptions) (*ImportResult, error) {
+	query := url.Values{}
+	if opts.Mode != "" {
+		query.Set("mode", opts.Mode)
+	}
+	if opts.DryRun {
+		query.Set("dry_run", "true")
+	}
+	header := http.Header{"Content-Type": {contentType}, "Accept": {"application/json"}}
+	if opts.IfMatch != "" {
+		header.Set("If-Match", opts.IfMatch)
+	}
+
+	resp, err := c.do(ctx, request{method: http.MethodPost, path: "/imports", query: query, header: header, body: body})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result ImportResult
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode /imports response: %v", err)
+	}
+	result.ETag = resp.Header.Get("ETag")
+	return &result, nil
+}
+
+// BatchGetResult holds the passengers found by GetPassengers, in the order
+// requested, and the requested IDs without a passenger.
+type BatchGetResult struct {
+	Passengers []Passenger `json:"passengers"`
+	MissingIDs []int       `json:"missing_ids"`
+}
+
+// GetPassengers looks up several passengers in one call, at most 500.
+func (c *Client) GetPassengers(ctx context.Context, passengerIDs []int, imputation Imputation) (*BatchGetResult, error) {
+	var result BatchGetResult
+	in := map[string][]int{"ids": passengerIDs}
+	if err := c.postJSON(ctx, "/passengers:batchGet", imputation.values(), in, &result, true); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
@@ -0,0 +1,83 @@
+package client
+
+import (
+	"context"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy controls how failed requests are retried. 429 Too Many
+// Requests is always retried, since the server turned the request away
+// before acting on it; 5xx responses and transport errors only for
+// requests that are safe to repeat (reads, predictions and GraphQL
+// queries, but not imports).
+type RetryPolicy struct {
+	// MaxAttempts is the number of attempts including the first; 1 or less
+	// disables retries.
+	MaxAttempts int
+	// InitialBackoff is the longest wait before the second attempt. It
+	// doubles for each further attempt up to MaxBackoff, and every wait is
+	// picked at random up to that bound.
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+// DefaultRetryPolicy makes up to three attempts, waiting at most 100ms and
+// then 200ms in between.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:    3,
+	InitialBackoff: 100 * time.Millisecond,
+	MaxBackoff:     2 * time.Second,
+}
+
+// NoRetries makes a single attempt.
+var NoRetries = RetryPolicy{MaxAttempts: 1}
+
+func (p RetryPolicy) retryable(status int, idempotent bool) bool {
+	if status == http.StatusTooManyRequests {
+		return true
+	}
+	return idempotent && status >= 500
+}
+
+// backoff returns the wait after the given failed attempt: a Retry-After
+// header in seconds if the response has one, otherwise full jitter over
+// an exponentially growing bound.
+func (p RetryPolicy) backoff(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds >= 0 {
+			wait := time.Duration(seconds) * time.Second
+			if p.MaxBackoff > 0 && wait > p.MaxBackoff {
+				wait = p.MaxBackoff
+			}
+			return wait
+		}
+	}
+
+	bound := p.InitialBackoff
+	for i := 1; i < attempt && (p.MaxBackoff <= 0 || bound < p.MaxBackoff); i++ {
+		bound *= 2
+	}
+	if p.MaxBackoff > 0 && bound > p.MaxBackoff {
+		bound = p.MaxBackoff
+	}
+	if bound <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(bound) + 1))
+}
+
+// wait sleeps before the next attempt, returning early with the context's
+// error if it is done first.
+func (p RetryPolicy) wait(ctx context.Context, attempt int, resp *http.Response) error {
+	timer := time.NewTimer(p.backoff(attempt, resp))
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,182 @@
+package client
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/shindesatish/titanic-service/pkg/model"
+)
+
+// GetFareHistogram counts passengers per fare percentile bucket. fareBasis
+// is FareRaw or FarePerPerson; empty means FareRaw.
+func (c *Client) GetFareHistogram(ctx context.Context, fareBasis string) (map[string]int, error) {
+	query := url.Values{}
+	if fareBasis != "" {
+		query.Set("fare", fareBasis)
+	}
+	histogram := map[string]int{}
+	if err := c.getJSON(ctx, "/fare-histogram", query, &histogram); err != nil {
+		return nil, err
+	}
+	return histogram, nil
+}
+
+// SummaryOptions tune GetSummaryStats.
+type SummaryOptions struct {
+	// FareBasis is FareRaw (the default) or FarePerPerson.
+	FareBasis string
+	Imputation
+}
+
+// GetSummaryStats returns passenger counts, the survival rate and the
+// distributions of Age, Fare, SibSp and Parch.
+func (c *Client) GetSummaryStats(ctx context.Context, opts SummaryOptions) (*model.SummaryStats, error) {
+	query := opts.Imputation.values()
+	if opts.FareBasis != "" {
+		query.Set("fare", opts.FareBasis)
+	}
+	var stats model.SummaryStats
+	if err := c.getJSON(ctx, "/stats/summary", query, &stats); err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
+
+// GetSurvivalStats returns the survival rate for each value of an
+// attribute, e.g. Sex, Pclass or Title.
+func (c *Client) GetSurvivalStats(ctx context.Context, by string) ([]model.SurvivalGroup, error) {
+	var groups []model.SurvivalGroup
+	if err := c.getJSON(ctx, "/stats/survival", url.Values{"by": {by}}, &groups); err != nil {
+		return nil, err
+	}
+	return groups, nil
+}
+
+// CrossTabOptions describe a contingency table.
+type CrossTabOptions struct {
+	Rows string
+	Cols string
+	// Value is the numeric attribute to aggregate; empty counts passengers.
+	Value string
+	// Agg is count (the default), sum, mean or median.
+	Agg string
+	// Margins adds row and column margins and the grand total.
+	Margins bool
+	// Normalize is all, rows or columns.
+	Normalize string
+}
+
+// GetCrossTab builds a contingency table.
+func (c *Client) GetCrossTab(ctx context.Context, opts CrossTabOptions) (*model.CrossTab, error) {
+	query := url.Values{"rows": {opts.Rows}, "cols": {opts.Cols}}
+	if opts.Value != "" {
+		query.Set("value", opts.Value)
+	}
+	if opts.Agg != "" {
+		query.Set("agg", opts.Agg)
+	}
+	if opts.Margins {
+		query.Set("margins", "true")
+	}
+	if opts.Normalize != "" {
+		query.Set("normalize", opts.Normalize)
+	}
+	var table model.CrossTab
+	if err := c.getJSON(ctx, "/stats/crosstab", query, &table); err != nil {
+		return nil, err
+	}
+	return &table, nil
+}
+
+// ChiSquareTest tests the independence of two categorical attributes.
+func (c *Client) ChiSquareTest(ctx context.Context, a, b string) (*ChiSquareTest, error) {
+	var test ChiSquareTest
+	if err := c.getJSON(ctx, "/stats/tests/chi-square", url.Values{"a": {a}, "b": {b}}, &test); err != nil {
+		return nil, err
+	}
+	return &test, nil
+}
+
+// CompareGroups runs Welch's t-test and the Mann–Whitney U test on value
+// between two groups of by. groups picks the two values of by when it has
+// more than two.
+func (c *Client) CompareGroups(ctx context.Context, value, by string, groups ...string) (*GroupComparison, error) {
+	query := url.Values{"value": {value}, "by": {by}}
+	if len(groups) > 0 {
+		query.Set("groups", strings.Join(groups, ","))
+	}
+	var comparison GroupComparison
+	if err := c.getJSON(ctx, "/stats/tests/compare", query, &comparison); err != nil {
+		return nil, err
+	}
+	return &comparison, nil
+}
+
+// GetCorrelations returns the pearson or spearman correlation matrix of
+// numeric attributes; empty method and attributes use the server defaults.
+func (c *Client) GetCorrelations(ctx context.Context, method string, attributes ...string) (*CorrelationMatrix, error) {
+	query := url.Values{}
+	if method != "" {
+		query.Set("method", method)
+	}
+	if len(attributes) > 0 {
+		query.Set("attributes", strings.Join(attributes, ","))
+	}
+	var matrix CorrelationMatrix
+	if err := c.getJSON(ctx, "/stats/correlations", query, &matrix); err != nil {
+		return nil, err
+	}
+	return &matrix, nil
+}
+
+// ValidateDataset returns the data-quality report of the dataset.
+func (c *Client) ValidateDataset(ctx context.Context) (*ValidationReport, error) {
+	var report ValidationReport
+	if err := c.getJSON(ctx, "/validation", nil, &report); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// GetCacheStats returns the hit/miss counters of the server's response
+// cache; it fails with a 404 when the cache is disabled.
+func (c *Client) GetCacheStats(ctx context.Context) (*CacheStats, error) {
+	var stats CacheStats
+	if err := c.getJSON(ctx, "/cache-stats", nil, &stats); err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
+
+// Predict scores a passenger with a stored model; empty modelName uses the
+// server default.
+func (c *Client) Predict(ctx context.Context, modelName string, passenger PredictRequest) (*Prediction, error) {
+	query := url.Values{}
+	if modelName != "" {
+		query.Set("model", modelName)
+	}
+	var prediction Prediction
+	if err := c.postJSON(ctx, "/predict", query, passenger, &prediction, true); err != nil {
+		return nil, err
+	}
+	return &prediction, nil
+}
+
+// EvaluateModel cross-validates a stored model's training options. Zero
+// folds and seed use the server defaults.
+func (c *Client) EvaluateModel(ctx context.Context, modelName string, folds int, seed int64) (*Evaluation, error) {
+	query := url.Values{}
+	if folds > 0 {
+		query.Set("folds", strconv.Itoa(folds))
+	}
+	if seed != 0 {
+		query.Set("seed", strconv.FormatInt(seed, 10))
+	}
+	var evaluation Evaluation
+	if err := c.getJSON(ctx, "/models/"+url.PathEscape(modelName)+"/evaluation", query, &evaluation); err != nil {
+		return nil, err
+	}
+	return &evaluation, nil
+}
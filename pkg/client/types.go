@@ -0,0 +1,173 @@
+package client
+
+import (
+	"github.com/shindesatish/titanic-service/pkg/model"
+	"github.com/shindesatish/titanic-service/pkg/stats"
+)
+
+// Fare bases accepted by the fare histogram and summary statistics
+const (
+	FareRaw       = "raw"
+	FarePerPerson = "per_person"
+)
+
+// Passenger is a passenger as returned by the passenger endpoints, with
+// the attributes derived from Name, Cabin and Ticket.
+type Passenger struct {
+	model.Passenger
+	model.ParsedName
+	model.ParsedCabin
+	model.ParsedTicket
+	TicketPartySize int     `json:"TicketPartySize,omitempty"`
+	FarePerPerson   float64 `json:"FarePerPerson,omitempty"`
+	// Imputed names the fields filled in by imputation.
+	Imputed []string `json:"Imputed,omitempty"`
+}
+
+// SearchResult is one passenger found by SearchPassengers.
+type SearchResult struct {
+	Passenger Passenger `json:"passenger"`
+	Score     float64   `json:"score"`
+	Matched   []string  `json:"matched,omitempty"`
+}
+
+// ChiSquareTest is the result of a chi-square test of independence.
+type ChiSquareTest struct {
+	A        string      `json:"a"`
+	B        string      `json:"b"`
+	RowKeys  []string    `json:"row_keys"`
+	ColKeys  []string    `json:"col_keys"`
+	Observed [][]float64 `json:"observed"`
+	*stats.ChiSquareResult
+}
+
+// GroupComparison compares a numeric attribute between two groups.
+type GroupComparison struct {
+	Value       string                   `json:"value"`
+	By          string                   `json:"by"`
+	Groups      [2]string                `json:"groups"`
+	Counts      [2]int                   `json:"counts"`
+	TTest       *stats.TTestResult       `json:"t_test"`
+	MannWhitney *stats.MannWhitneyResult `json:"mann_whitney"`
+}
+
+// CorrelationMatrix holds the correlation of every pair of attributes.
+// Coefficients and Pairs are nil where a pair has too few values.
+type CorrelationMatrix struct {
+	Method       string                       `json:"method"`
+	Attributes   []string                     `json:"attributes"`
+	Coefficients [][]*float64                 `json:"coefficients"`
+	Pairs        [][]*stats.CorrelationResult `json:"pairs"`
+}
+
+// PredictRequest describes a passenger to score; Age may be nil when
+// unknown.
+type PredictRequest struct {
+	Sex      string   `json:"Sex"`
+	Pclass   int      `json:"Pclass"`
+	Age      *float64 `json:"Age,omitempty"`
+	Fare     float64  `json:"Fare"`
+	Embarked string   `json:"Embarked,omitempty"`
+	SibSp    int      `json:"SibSp"`
+	Parch    int      `json:"Parch"`
+}
+
+// Prediction is the survival probability of a passenger under a model.
+type Prediction struct {
+	Model               string  `json:"model"`
+	ModelTrainedAt      string  `json:"model_trained_at"`
+	SurvivalProbability float64 `json:"survival_probability"`
+	Survived            int     `json:"survived"`
+}
+
+// TrainOptions are the training parameters of a model.
+type TrainOptions struct {
+	LearningRate float64 `json:"learning_rate"`
+	Epochs       int     `json:"epochs"`
+	L2           float64 `json:"l2"`
+}
+
+// ConfusionMatrix counts out-of-fold predictions at a 0.5 threshold.
+type ConfusionMatrix struct {
+	TruePositives  int `json:"true_positives"`
+	FalsePositives int `json:"false_positives"`
+	TrueNegatives  int `json:"true_negatives"`
+	FalseNegatives int `json:"false_negatives"`
+}
+
+// CalibrationBucket compares predicted and observed survival over a range
+// of predicted probabilities.
+type CalibrationBucket struct {
+	Lower         float64 `json:"lower"`
+	Upper         float64 `json:"upper"`
+	Count         int     `json:"count"`
+	MeanPredicted float64 `json:"mean_predicted"`
+	ObservedRate  float64 `json:"observed_rate"`
+}
+
+// FoldResult is the accuracy on one cross-validation fold.
+type FoldResult struct {
+	Fold     int     `json:"fold"`
+	Rows     int     `json:"rows"`
+	Accuracy float64 `json:"accuracy"`
+}
+
+// Evaluation holds the cross-validation metrics of a model.
+type Evaluation struct {
+	Model       string              `json:"model"`
+	Folds       int                 `json:"folds"`
+	Seed        int64               `json:"seed"`
+	Rows        int                 `json:"rows"`
+	Options     TrainOptions        `json:"options"`
+	Accuracy    float64             `json:"accuracy"`
+	Precision   float64             `json:"precision"`
+	Recall      float64             `json:"recall"`
+	F1          float64             `json:"f1"`
+	ROCAUC      float64             `json:"roc_auc"`
+	Confusion   ConfusionMatrix     `json:"confusion_matrix"`
+	Calibration []CalibrationBucket `json:"calibration"`
+	FoldResults []FoldResult        `json:"fold_results"`
+}
+
+// RowError is a problem with one row of the dataset or of an import.
+type RowError struct {
+	Line    int    `json:"line"`
+	Field   string `json:"field,omitempty"`
+	Value   string `json:"value,omitempty"`
+	Check   string `json:"check"`
+	Message string `json:"message"`
+}
+
+// ValidationReport is the data-quality report of the dataset.
+type ValidationReport struct {
+	Source        string         `json:"source"`
+	Rows          int            `json:"rows"`
+	ValidRows     int            `json:"valid_rows"`
+	InvalidRows   int            `json:"invalid_rows"`
+	IssuesByCheck map[string]int `json:"issues_by_check"`
+	IssuesByField map[string]int `json:"issues_by_field"`
+	Errors        []RowError     `json:"errors"`
+}
+
+// ImportResult summarizes the changes an import made, or would have made
+// for a dry run.
+type ImportResult struct {
+	Mode     string `json:"mode"`
+	DryRun   bool   `json:"dry_run"`
+	Received int    `json:"received"`
+	Inserted int    `json:"inserted"`
+	Updated  int    `json:"updated"`
+	Deleted  int    `json:"deleted"`
+	// ETag is the dataset version after the import, for chaining
+	// conditional imports.
+	ETag string `json:"-"`
+}
+
+// CacheStats reports the effectiveness of the server's response cache.
+type CacheStats struct {
+	Hits     uint64 `json:"hits"`
+	Misses   uint64 `json:"misses"`
+	Entries  int    `json:"entries"`
+	Capacity int    `json:"capacity"`
+	TTL      string `json:"ttl"`
+}
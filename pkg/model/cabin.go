@@ -0,0 +1,60 @@
+package model
+
+import (
+	"strconv"
+	"strings"
+)
+
+// ParsedCabin is a passenger Cabin split into its parts. A Cabin holds one
+// or more space-separated cabins such as "C23 C25 C27", each a deck letter
+// optionally followed by a cabin number. Deck is the first letter
+// mentioned and Decks lists every letter, so "F G73" has Deck F and Decks
+// F and G; the lone F is not taken as the deck of G73.
+type ParsedCabin struct {
+	Deck         string   `json:"Deck"`
+	Decks        []string `json:"Decks,omitempty"`
+	CabinNumbers []int    `json:"CabinNumbers,omitempty"`
+	CabinCount   int      `json:"CabinCount"`
+}
+
+// ParseCabin decodes a titanic.csv Cabin. A blank cabin gives the zero
+// ParsedCabin; a deck without any cabin number ("T", "D") counts as one
+// cabin.
+func ParseCabin(cabin string) ParsedCabin {
+	var parsed ParsedCabin
+	tokens := strings.Fields(strings.ToUpper(cabin))
+	if len(tokens) == 0 {
+		return parsed
+	}
+
+	for _, token := range tokens {
+		letters := strings.TrimRightFunc(token, isDigit)
+		if letters != "" && !containsString(parsed.Decks, letters[:1]) {
+			parsed.Decks = append(parsed.Decks, letters[:1])
+		}
+		if number, err := strconv.Atoi(token[len(letters):]); err == nil {
+			parsed.CabinNumbers = append(parsed.CabinNumbers, number)
+		}
+	}
+	if len(parsed.Decks) > 0 {
+		parsed.Deck = parsed.Decks[0]
+	}
+	parsed.CabinCount = len(parsed.CabinNumbers)
+	if parsed.CabinCount == 0 {
+		parsed.CabinCount = 1
+	}
+	return parsed
+}
+
+func isDigit(r rune) bool {
+	return r >= '0' && r <= '9'
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,24 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseCabin(t *testing.T) {
+	tests := []struct {
+		cabin string
+		want  ParsedCabin
+	}{
+		{"", ParsedCabin{}},
+		{"T", ParsedCabin{Deck: "T", Decks: []string{"T"}, CabinCount: 1}},
+		{"F G73", ParsedCabin{Deck: "F", Decks: []string{"F", "G"}, CabinNumbers: []int{73}, CabinCount: 1}},
+		{"B57 B59 B63 B66", ParsedCabin{Deck: "B", Decks: []string{"B"}, CabinNumbers: []int{57, 59, 63, 66}, CabinCount: 4}},
+		{"c85", ParsedCabin{Deck: "C", Decks: []string{"C"}, CabinNumbers: []int{85}, CabinCount: 1}},
+	}
+	for _, tt := range tests {
+		if got := ParseCabin(tt.cabin); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseCabin(%q) = %+v, want %+v", tt.cabin, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package model
+
+import "strings"
+
+// NumericAttributes are the attributes that can be aggregated in a
+// cross-tabulation.
+var NumericAttributes = []string{
+	"PassengerId",
+	"Survived",
+	"Pclass",
+	"Age",
+	"SibSp",
+	"Parch",
+	"Fare",
+	"CabinCount",
+	"TicketNumber",
+}
+
+// IsNumericAttribute reports whether name is one of NumericAttributes.
+func IsNumericAttribute(name string) bool {
+	for _, attribute := range NumericAttributes {
+		if strings.EqualFold(attribute, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// CrossTabCell holds the passengers sharing one row and one column value
+// of a cross-tabulation. Sum and Values only cover the passengers with a
+// value; ValueCount says how many that is. Values is only filled in when
+// requested, for medians.
+type CrossTabCell struct {
+	Row        string
+	Col        string
+	Count      int
+	ValueCount int
+	Sum        float64
+	Values     []float64
+}
+
+// CrossTab is a contingency table of the Agg of Value (or of passenger
+// counts) for each pair of Rows and Cols attribute values. Cells[i][j]
+// belongs to RowKeys[i] and ColKeys[j]; it is nil when no passenger in the
+// cell has a value to aggregate.
+type CrossTab struct {
+	Rows       string       `json:"rows"`
+	Cols       string       `json:"cols"`
+	Value      string       `json:"value,omitempty"`
+	Agg        string       `json:"agg"`
+	Normalize  string       `json:"normalize,omitempty"`
+	RowKeys    []string     `json:"row_keys"`
+	ColKeys    []string     `json:"col_keys"`
+	Cells      [][]*float64 `json:"cells"`
+	RowMargins []*float64   `json:"row_margins,omitempty"`
+	ColMargins []*float64   `json:"col_margins,omitempty"`
+	Total      *float64     `json:"total,omitempty"`
+}
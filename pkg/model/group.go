@@ -0,0 +1,31 @@
+package model
+
+// Kinds of TravelGroup
+const (
+	GroupSolo   = "solo"
+	GroupFamily = "family"
+	GroupParty  = "party"
+)
+
+// Survival outcomes of a TravelGroup
+const (
+	OutcomeAllSurvived  = "all_survived"
+	OutcomeSomeSurvived = "some_survived"
+	OutcomeNoneSurvived = "none_survived"
+)
+
+// TravelGroup is a family or travel party reconstructed from shared
+// tickets and surnames. ID is the lowest PassengerId among the members.
+type TravelGroup struct {
+	ID           int         `json:"id"`
+	Kind         string      `json:"kind"`
+	Size         int         `json:"size"`
+	Surnames     []string    `json:"surnames"`
+	Tickets      []string    `json:"tickets"`
+	Survivors    int         `json:"survivors"`
+	SurvivalRate float64     `json:"survival_rate"`
+	Outcome      string      `json:"outcome"`
+	Consistent   bool        `json:"consistent"`
+	Issues       []string    `json:"issues,omitempty"`
+	Members      []Passenger `json:"members"`
+}
@@ -0,0 +1,97 @@
+package model
+
+import "strings"
+
+// ParsedName is a passenger Name split into its parts. For
+// "Cumings, Mrs. John Bradley (Florence Briggs Thayer)" the surname is
+// Cumings, the title Mrs, the given names John Bradley and the alternate
+// (maiden) name Florence Briggs Thayer.
+type ParsedName struct {
+	Surname       string `json:"Surname"`
+	Title         string `json:"Title"`
+	TitleGroup    string `json:"TitleGroup"`
+	GivenNames    string `json:"GivenNames"`
+	AlternateName string `json:"AlternateName,omitempty"`
+	Nickname      string `json:"Nickname,omitempty"`
+}
+
+// titleGroups folds the rarer honorifics into the four common ones where
+// they are equivalent. Anything not listed belongs to the "Other" group.
+var titleGroups = map[string]string{
+	"Mr":     "Mr",
+	"Mrs":    "Mrs",
+	"Mme":    "Mrs",
+	"Miss":   "Miss",
+	"Mlle":   "Miss",
+	"Ms":     "Miss",
+	"Master": "Master",
+}
+
+// ParseName splits a titanic.csv Name of the form
+// `Surname, Title. Given Names "Nickname" (Alternate Name)`. Every part
+// except the surname is optional; several parenthesized names are joined
+// with "; ".
+func ParseName(name string) ParsedName {
+	var parsed ParsedName
+	rest := name
+
+	var alternates []string
+	for {
+		open := strings.IndexByte(rest, '(')
+		if open < 0 {
+			break
+		}
+		end := strings.IndexByte(rest[open:], ')')
+		if end < 0 {
+			break
+		}
+		if alternate := cleanName(strings.Trim(rest[open+1:open+end], `" `)); alternate != "" {
+			alternates = append(alternates, alternate)
+		}
+		rest = rest[:open] + " " + rest[open+end+1:]
+	}
+	parsed.AlternateName = strings.Join(alternates, "; ")
+
+	if open := strings.IndexByte(rest, '"'); open >= 0 {
+		if end := strings.IndexByte(rest[open+1:], '"'); end >= 0 {
+			parsed.Nickname = cleanName(rest[open+1 : open+1+end])
+			rest = rest[:open] + " " + rest[open+end+2:]
+		}
+	}
+
+	surname, remainder, found := strings.Cut(rest, ",")
+	if !found {
+		parsed.GivenNames = cleanName(rest)
+		return parsed
+	}
+	parsed.Surname = cleanName(surname)
+
+	if title, given, ok := strings.Cut(remainder, "."); ok {
+		title = cleanName(title)
+		if len(strings.Fields(title)) <= 2 {
+			if strings.HasPrefix(strings.ToLower(title), "the ") {
+				title = title[len("the "):]
+			}
+			parsed.Title = title
+			remainder = given
+		}
+	}
+	parsed.TitleGroup = TitleGroup(parsed.Title)
+	parsed.GivenNames = cleanName(remainder)
+
+	return parsed
+}
+
+// TitleGroup returns Mr, Mrs, Miss or Master for the common honorifics and
+// their equivalents, and Other for the rest (Dr, Rev, Col, Countess...).
+func TitleGroup(title string) string {
+	if group, ok := titleGroups[title]; ok {
+		return group
+	}
+	return "Other"
+}
+
+// cleanName trims and collapses runs of whitespace.
+func cleanName(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
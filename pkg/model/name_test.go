@@ -0,0 +1,33 @@
+package model
+
+import "testing"
+
+func TestParseName(t *testing.T) {
+	tests := []struct {
+		name string
+		want ParsedName
+	}{
+		{"Braund, Mr. Owen Harris", ParsedName{Surname: "Braund", Title: "Mr", TitleGroup: "Mr", GivenNames: "Owen Harris"}},
+		{"Cumings, Mrs. John Bradley (Florence Briggs Thayer)", ParsedName{Surname: "Cumings", Title: "Mrs", TitleGroup: "Mrs", GivenNames: "John Bradley", AlternateName: "Florence Briggs Thayer"}},
+		{"Sagesser, Mlle. Emma", ParsedName{Surname: "Sagesser", Title: "Mlle", TitleGroup: "Miss", GivenNames: "Emma"}},
+		{"Uruchurtu, Don. Manuel E", ParsedName{Surname: "Uruchurtu", Title: "Don", TitleGroup: "Other", GivenNames: "Manuel E"}},
+		{"Rothes, the Countess. of (Lucy Noel Martha Dyer-Edwards)", ParsedName{Surname: "Rothes", Title: "Countess", TitleGroup: "Other", GivenNames: "of", AlternateName: "Lucy Noel Martha Dyer-Edwards"}},
+		{`O'Dwyer, Miss. Ellen "Nellie"`, ParsedName{Surname: "O'Dwyer", Title: "Miss", TitleGroup: "Miss", GivenNames: "Ellen", Nickname: "Nellie"}},
+		{`Moubarek, Master. Halim Gonios ("William George")`, ParsedName{Surname: "Moubarek", Title: "Master", TitleGroup: "Master", GivenNames: "Halim Gonios", AlternateName: "William George"}},
+		{`Nakid, Mrs. Sahid (Waika "Mary" Mowad)`, ParsedName{Surname: "Nakid", Title: "Mrs", TitleGroup: "Mrs", GivenNames: "Sahid", AlternateName: `Waika "Mary" Mowad`}},
+		{`Duff Gordon, Lady. (Lucille Christiana Sutherland) ("Mrs Morgan")`, ParsedName{Surname: "Duff Gordon", Title: "Lady", TitleGroup: "Other", AlternateName: "Lucille Christiana Sutherland; Mrs Morgan"}},
+		{"  Smith,   Mr.  John   Jacob ", ParsedName{Surname: "Smith", Title: "Mr", TitleGroup: "Mr", GivenNames: "John Jacob"}},
+		// A dot after more than two words is not the end of a title
+		{"Doe, John Jacob Jr. Smith", ParsedName{Surname: "Doe", TitleGroup: "Other", GivenNames: "John Jacob Jr. Smith"}},
+		{"Doe, John", ParsedName{Surname: "Doe", TitleGroup: "Other", GivenNames: "John"}},
+		// Without a comma there is no surname or title
+		{"Madonna", ParsedName{GivenNames: "Madonna"}},
+		{`Cher "the Goddess of Pop" (Cherilyn Sarkisian)`, ParsedName{GivenNames: "Cher", Nickname: "the Goddess of Pop", AlternateName: "Cherilyn Sarkisian"}},
+		{"", ParsedName{}},
+	}
+	for _, tt := range tests {
+		if got := ParseName(tt.name); got != tt.want {
+			t.Errorf("ParseName(%q) = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -1,24 +1,234 @@
 // model/passenger.go
 package model
 
-import "database/sql"
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
 
 type Passenger struct {
-	PassengerID int     `json:"PassengerId"`
-	Survived    int     `json:"Survived"`
-	Pclass      int     `json:"Pclass"`
-	Name        string  `json:"Name"`
-	Sex         string  `json:"Sex"`
-	Age         float64 `json:"Age"`
-	SibSp       int     `json:"SibSp"`
-	Parch       int     `json:"Parch"`
-	Ticket      string  `json:"Ticket"`
-	Fare        float64 `json:"Fare"`
-	Cabin       string  `json:"Cabin"`
-	Embarked    string  `json:"Embarked"`
-}
-
-// NullFloat64 represents a float64 that may be null.
+	PassengerID int         `json:"PassengerId"`
+	Survived    int         `json:"Survived"`
+	Pclass      int         `json:"Pclass"`
+	Name        string      `json:"Name"`
+	Sex         string      `json:"Sex"`
+	Age         NullFloat64 `json:"Age"`
+	SibSp       int         `json:"SibSp"`
+	Parch       int         `json:"Parch"`
+	Ticket      string      `json:"Ticket"`
+	Fare        float64     `json:"Fare"`
+	Cabin       string      `json:"Cabin"`
+	Embarked    string      `json:"Embarked"`
+}
+
+// NullFloat64 represents a float64 that may be null. It is null in JSON and
+// NULL in SQL when not Valid, so a real 0 stays distinct from a missing
+// value.
 type NullFloat64 struct {
 	sql.NullFloat64
 }
+
+// NewNullFloat64 returns a valid NullFloat64 holding f.
+func NewNullFloat64(f float64) NullFloat64 {
+	return NullFloat64{sql.NullFloat64{Float64: f, Valid: true}}
+}
+
+func (n NullFloat64) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return []byte("null"), nil
+	}
+	return json.Marshal(n.Float64)
+}
+
+func (n *NullFloat64) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*n = NullFloat64{}
+		return nil
+	}
+	var f float64
+	if err := json.Unmarshal(data, &f); err != nil {
+		return err
+	}
+	*n = NewNullFloat64(f)
+	return nil
+}
+
+// CSVHeader is the column order of the original titanic.csv file.
+var CSVHeader = []string{
+	"PassengerId",
+	"Survived",
+	"Pclass",
+	"Name",
+	"Sex",
+	"Age",
+	"SibSp",
+	"Parch",
+	"Ticket",
+	"Fare",
+	"Cabin",
+	"Embarked",
+}
+
+// CSVRecord formats the passenger as a titanic.csv row in CSVHeader order.
+// A missing Age is written as an empty field, as it is in the source file.
+func (p *Passenger) CSVRecord() []string {
+	age := ""
+	if p.Age.Valid {
+		age = strconv.FormatFloat(p.Age.Float64, 'f', -1, 64)
+	}
+	return []string{
+		strconv.Itoa(p.PassengerID),
+		strconv.Itoa(p.Survived),
+		strconv.Itoa(p.Pclass),
+		p.Name,
+		p.Sex,
+		age,
+		strconv.Itoa(p.SibSp),
+		strconv.Itoa(p.Parch),
+		p.Ticket,
+		strconv.FormatFloat(p.Fare, 'f', -1, 64),
+		p.Cabin,
+		p.Embarked,
+	}
+}
+
+// Project returns a copy of the passenger with only the named attributes
+// set. Attribute names are matched case-insensitively against CSVHeader.
+func (p *Passenger) Project(attributes []string) (*Passenger, error) {
+	projected := &Passenger{}
+	for _, attribute := range attributes {
+		switch strings.ToLower(attribute) {
+		case "passengerid":
+			projected.PassengerID = p.PassengerID
+		case "survived":
+			projected.Survived = p.Survived
+		case "pclass":
+			projected.Pclass = p.Pclass
+		case "name":
+			projected.Name = p.Name
+		case "sex":
+			projected.Sex = p.Sex
+		case "age":
+			projected.Age = p.Age
+		case "sibsp":
+			projected.SibSp = p.SibSp
+		case "parch":
+			projected.Parch = p.Parch
+		case "ticket":
+			projected.Ticket = p.Ticket
+		case "fare":
+			projected.Fare = p.Fare
+		case "cabin":
+			projected.Cabin = p.Cabin
+		case "embarked":
+			projected.Embarked = p.Embarked
+		default:
+			return nil, fmt.Errorf("unknown attribute: %s", attribute)
+		}
+	}
+	return projected, nil
+}
+
+// PassengerFilter selects passengers by attribute equality. Each key is an
+// attribute name accepted by Passenger.Attribute and a passenger matches
+// when, for every key, its attribute value equals one of the listed values.
+// An empty filter matches every passenger.
+type PassengerFilter map[string][]string
+
+// Validate reports the first attribute in the filter that is not a
+// passenger attribute.
+func (f PassengerFilter) Validate() error {
+	for attribute := range f {
+		if !IsAttribute(attribute) {
+			return fmt.Errorf("unknown attribute: %s", attribute)
+		}
+	}
+	return nil
+}
+
+// Matches reports whether the passenger satisfies every filter attribute.
+func (f PassengerFilter) Matches(p *Passenger) bool {
+	for attribute, values := range f {
+		actual, err := p.Attribute(attribute)
+		if err != nil {
+			return false
+		}
+		matched := false
+		for _, value := range values {
+			if actual == value {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// csvColumn returns the CSVHeader index of attribute, or -1.
+func csvColumn(attribute string) int {
+	for i, name := range CSVHeader {
+		if strings.EqualFold(name, attribute) {
+			return i
+		}
+	}
+	return -1
+}
+
+// DerivedAttributes are computed from the titanic.csv attributes. They are
+// accepted wherever an attribute is named for filtering or grouping.
+var DerivedAttributes = []string{
+	"Surname",
+	"Title",
+	"TitleGroup",
+	"Deck",
+	"CabinCount",
+	"TicketPrefix",
+	"TicketNumber",
+}
+
+// AttributeNames lists every attribute accepted by Attribute.
+func AttributeNames() []string {
+	return append(append([]string(nil), CSVHeader...), DerivedAttributes...)
+}
+
+// IsAttribute reports whether name is a titanic.csv or derived attribute.
+func IsAttribute(name string) bool {
+	for _, attribute := range AttributeNames() {
+		if strings.EqualFold(attribute, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// Attribute returns the named attribute formatted as in titanic.csv, or a
+// derived attribute. Names are matched case-insensitively.
+func (p *Passenger) Attribute(name string) (string, error) {
+	if column := csvColumn(name); column >= 0 {
+		return p.CSVRecord()[column], nil
+	}
+
+	switch strings.ToLower(name) {
+	case "surname":
+		return ParseName(p.Name).Surname, nil
+	case "title":
+		return ParseName(p.Name).Title, nil
+	case "titlegroup":
+		return ParseName(p.Name).TitleGroup, nil
+	case "deck":
+		return ParseCabin(p.Cabin).Deck, nil
+	case "cabincount":
+		return strconv.Itoa(ParseCabin(p.Cabin).CabinCount), nil
+	case "ticketprefix":
+		return ParseTicket(p.Ticket).TicketPrefix, nil
+	case "ticketnumber":
+		return strconv.Itoa(ParseTicket(p.Ticket).TicketNumber), nil
+	}
+	return "", fmt.Errorf("unknown attribute: %s", name)
+}
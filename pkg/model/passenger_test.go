@@ -0,0 +1,50 @@
+package model
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestNullFloat64JSON(t *testing.T) {
+	tests := []struct {
+		json  string
+		value NullFloat64
+	}{
+		{"null", NullFloat64{}},
+		{"0", NewNullFloat64(0)},
+		{"22.5", NewNullFloat64(22.5)},
+	}
+	for _, tt := range tests {
+		var value NullFloat64
+		if err := json.Unmarshal([]byte(tt.json), &value); err != nil {
+			t.Fatalf("Unmarshal(%s): %v", tt.json, err)
+		}
+		if value != tt.value {
+			t.Errorf("Unmarshal(%s) = %+v, want %+v", tt.json, value, tt.value)
+		}
+		data, err := json.Marshal(tt.value)
+		if err != nil {
+			t.Fatalf("Marshal(%+v): %v", tt.value, err)
+		}
+		if string(data) != tt.json {
+			t.Errorf("Marshal(%+v) = %s, want %s", tt.value, data, tt.json)
+		}
+	}
+}
+
+func TestCSVRecordAge(t *testing.T) {
+	tests := []struct {
+		age  NullFloat64
+		want string
+	}{
+		{NullFloat64{}, ""},
+		{NewNullFloat64(0), "0"},
+		{NewNullFloat64(0.42), "0.42"},
+	}
+	for _, tt := range tests {
+		p := Passenger{Age: tt.age}
+		if got := p.CSVRecord()[5]; got != tt.want {
+			t.Errorf("CSVRecord Age for %+v = %q, want %q", tt.age, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,19 @@
+package model
+
+// Search modes
+const (
+	SearchSubstring = "substring"
+	SearchToken     = "token"
+	SearchFuzzy     = "fuzzy"
+)
+
+// SearchModes lists the accepted search modes.
+var SearchModes = []string{SearchSubstring, SearchToken, SearchFuzzy}
+
+// SearchHit is a passenger matching a search, with its relevance score
+// (higher is better) and the indexed terms that matched when known.
+type SearchHit struct {
+	Passenger Passenger `json:"passenger"`
+	Score     float64   `json:"score"`
+	Matched   []string  `json:"matched,omitempty"`
+}
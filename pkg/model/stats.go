@@ -0,0 +1,40 @@
+package model
+
+// NumericSummary describes the distribution of a numeric attribute.
+// Missing counts passengers without a value, which are excluded from the
+// other fields.
+type NumericSummary struct {
+	Count   int     `json:"count"`
+	Missing int     `json:"missing"`
+	Mean    float64 `json:"mean"`
+	StdDev  float64 `json:"std_dev"`
+	Min     float64 `json:"min"`
+	Median  float64 `json:"median"`
+	Max     float64 `json:"max"`
+}
+
+// SummaryStats gives an overview of the whole passenger dataset.
+type SummaryStats struct {
+	Passengers   int            `json:"passengers"`
+	Survivors    int            `json:"survivors"`
+	SurvivalRate float64        `json:"survival_rate"`
+	Age          NumericSummary `json:"age"`
+	FareBasis    string         `json:"fare_basis"`
+	Fare         NumericSummary `json:"fare"`
+	SibSp        NumericSummary `json:"sibsp"`
+	Parch        NumericSummary `json:"parch"`
+	Pclass       map[string]int `json:"pclass"`
+	Sex          map[string]int `json:"sex"`
+	Embarked     map[string]int `json:"embarked"`
+	Imputation   string         `json:"imputation,omitempty"`
+	Imputed      map[string]int `json:"imputed,omitempty"`
+}
+
+// SurvivalGroup is the survival outcome of the passengers sharing one value
+// of the grouping attribute.
+type SurvivalGroup struct {
+	Group        string  `json:"group"`
+	Passengers   int     `json:"passengers"`
+	Survivors    int     `json:"survivors"`
+	SurvivalRate float64 `json:"survival_rate"`
+}
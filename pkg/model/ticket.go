@@ -0,0 +1,52 @@
+package model
+
+import (
+	"strconv"
+	"strings"
+)
+
+// ParsedTicket is a passenger Ticket split into its optional letter prefix
+// and its number. The prefix is normalized to upper case without dots or
+// spaces so that "STON/O 2." and "STON/O2." compare equal.
+type ParsedTicket struct {
+	TicketPrefix string `json:"TicketPrefix"`
+	TicketNumber int    `json:"TicketNumber"`
+}
+
+// ParseTicket decodes a titanic.csv Ticket such as "A/5 21171", "113803"
+// or "LINE". TicketNumber is 0 for tickets without a number.
+func ParseTicket(ticket string) ParsedTicket {
+	var parsed ParsedTicket
+	tokens := strings.Fields(ticket)
+	if len(tokens) == 0 {
+		return parsed
+	}
+
+	if number, err := strconv.Atoi(tokens[len(tokens)-1]); err == nil {
+		parsed.TicketNumber = number
+		tokens = tokens[:len(tokens)-1]
+	}
+	parsed.TicketPrefix = strings.ToUpper(strings.ReplaceAll(strings.Join(tokens, ""), ".", ""))
+	return parsed
+}
+
+// FarePerPerson splits a ticket's Fare between the partySize passengers
+// travelling on it.
+func FarePerPerson(fare float64, partySize int) float64 {
+	if partySize <= 1 {
+		return fare
+	}
+	return fare / float64(partySize)
+}
+
+// TicketGroup is the set of passengers travelling on one ticket.
+type TicketGroup struct {
+	Ticket        string      `json:"ticket"`
+	TicketPrefix  string      `json:"ticket_prefix"`
+	TicketNumber  int         `json:"ticket_number"`
+	Fare          float64     `json:"fare"`
+	FarePerPerson float64     `json:"fare_per_person"`
+	Size          int         `json:"size"`
+	Survivors     int         `json:"survivors"`
+	Members       []Passenger `json:"members"`
+}
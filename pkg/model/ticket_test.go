@@ -0,0 +1,26 @@
+package model
+
+import "testing"
+
+func TestParseTicket(t *testing.T) {
+	tests := []struct {
+		ticket string
+		want   ParsedTicket
+	}{
+		{"A/5 21171", ParsedTicket{TicketPrefix: "A/5", TicketNumber: 21171}},
+		{"113803", ParsedTicket{TicketNumber: 113803}},
+		{"LINE", ParsedTicket{TicketPrefix: "LINE"}},
+		{"PC 17599", ParsedTicket{TicketPrefix: "PC", TicketNumber: 17599}},
+		{"STON/O 2. 3101282", ParsedTicket{TicketPrefix: "STON/O2", TicketNumber: 3101282}},
+		{"STON/O2. 3101282", ParsedTicket{TicketPrefix: "STON/O2", TicketNumber: 3101282}},
+		{"W./C. 6608", ParsedTicket{TicketPrefix: "W/C", TicketNumber: 6608}},
+		{"SC/Paris 2123", ParsedTicket{TicketPrefix: "SC/PARIS", TicketNumber: 2123}},
+		{"  C.A.   2343 ", ParsedTicket{TicketPrefix: "CA", TicketNumber: 2343}},
+		{"", ParsedTicket{}},
+	}
+	for _, tt := range tests {
+		if got := ParseTicket(tt.ticket); got != tt.want {
+			t.Errorf("ParseTicket(%q) = %+v, want %+v", tt.ticket, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package stats
+
+import (
+	"math"
+	"testing"
+)
+
+// closeTo reports whether got is within a relative tolerance of want.
+func closeTo(got, want, tolerance float64) bool {
+	if want == 0 {
+		return math.Abs(got) <= tolerance
+	}
+	return math.Abs(got-want) <= tolerance*math.Abs(want)
+}
+
+// chiSquareSFEvenDF is the closed form of the chi-square upper tail for an
+// even number of degrees of freedom: the Poisson CDF at df/2 - 1.
+func chiSquareSFEvenDF(x float64, df int) float64 {
+	var sum, term float64 = 0, 1
+	for k := 0; k < df/2; k++ {
+		if k > 0 {
+			term *= x / 2 / float64(k)
+		}
+		sum += term
+	}
+	return math.Exp(-x/2) * sum
+}
+
+func TestNormalSF(t *testing.T) {
+	tests := []struct {
+		z, want float64
+	}{
+		{0, 0.5},
+		{1.959963984540054, 0.025}, // scipy.stats.norm.ppf(0.975)
+		{-1.959963984540054, 0.975},
+		{3.090232306167813, 0.001}, // scipy.stats.norm.ppf(0.999)
+	}
+	for _, tt := range tests {
+		if got := NormalSF(tt.z); !closeTo(got, tt.want, 1e-9) {
+			t.Errorf("NormalSF(%v) = %v, want %v", tt.z, got, tt.want)
+		}
+	}
+}
+
+func TestChiSquareSF(t *testing.T) {
+	tests := []struct {
+		x, df, want float64
+	}{
+		// Critical values from scipy.stats.chi2.ppf
+		{3.841458820694124, 1, 0.05},
+		{18.307038053275146, 10, 0.05},
+		{23.209251158954356, 10, 0.01},
+		// Closed forms, on both sides of the series/continued fraction split
+		{1, 1, math.Erfc(math.Sqrt(0.5))},
+		{30.070149095754672, 2, math.Exp(-30.070149095754672 / 2)},
+		{2, 10, chiSquareSFEvenDF(2, 10)},
+		{40, 20, chiSquareSFEvenDF(40, 20)},
+		{0, 3, 1},
+	}
+	for _, tt := range tests {
+		if got := ChiSquareSF(tt.x, tt.df); !closeTo(got, tt.want, 1e-9) {
+			t.Errorf("ChiSquareSF(%v, %v) = %v, want %v", tt.x, tt.df, got, tt.want)
+		}
+	}
+}
+
+func TestStudentTSF2(t *testing.T) {
+	tests := []struct {
+		t, df, want float64
+	}{
+		// Critical values from scipy.stats.t.ppf(0.975, df)
+		{2.2281388519649385, 10, 0.05},
+		{2.0422724563012373, 30, 0.05},
+		// Closed forms for 1 and 2 degrees of freedom
+		{1.5, 1, 1 - 2/math.Pi*math.Atan(1.5)},
+		{-12, 1, 1 - 2/math.Pi*math.Atan(12)},
+		{0.8, 2, 1 - 0.8/math.Sqrt(2+0.8*0.8)},
+		{0, 5, 1},
+		{math.Inf(1), 5, 0},
+	}
+	for _, tt := range tests {
+		if got := StudentTSF2(tt.t, tt.df); !closeTo(got, tt.want, 1e-9) {
+			t.Errorf("StudentTSF2(%v, %v) = %v, want %v", tt.t, tt.df, got, tt.want)
+		}
+	}
+}
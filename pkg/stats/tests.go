@@ -0,0 +1,258 @@
+// Package stats implements the hypothesis tests and correlation measures
+// the service reports, using only the standard library.
+package stats
+
+import (
+	"errors"
+	"math"
+	"sort"
+)
+
+// ErrTooFewValues is returned when a sample is too small for a test.
+var ErrTooFewValues = errors.New("not enough values for the test")
+
+// ChiSquareResult is Pearson's chi-square test of independence on a
+// contingency table, without continuity correction.
+type ChiSquareResult struct {
+	Statistic float64     `json:"statistic"`
+	DF        int         `json:"df"`
+	PValue    float64     `json:"p_value"`
+	CramersV  float64     `json:"cramers_v"`
+	Expected  [][]float64 `json:"expected"`
+}
+
+// ChiSquare tests whether the rows and columns of observed are
+// independent. Rows and columns without any observation are ignored.
+func ChiSquare(observed [][]float64) (*ChiSquareResult, error) {
+	var rowTotals, colTotals []float64
+	var total float64
+	for _, row := range observed {
+		var sum float64
+		for j, value := range row {
+			sum += value
+			for len(colTotals) <= j {
+				colTotals = append(colTotals, 0)
+			}
+			colTotals[j] += value
+		}
+		rowTotals = append(rowTotals, sum)
+		total += sum
+	}
+
+	rows, cols := 0, 0
+	for _, t := range rowTotals {
+		if t > 0 {
+			rows++
+		}
+	}
+	for _, t := range colTotals {
+		if t > 0 {
+			cols++
+		}
+	}
+	if rows < 2 || cols < 2 {
+		return nil, errors.New("chi-square needs at least two non-empty rows and columns")
+	}
+
+	result := &ChiSquareResult{DF: (rows - 1) * (cols - 1), Expected: make([][]float64, len(observed))}
+	for i, row := range observed {
+		result.Expected[i] = make([]float64, len(colTotals))
+		for j := range colTotals {
+			expected := rowTotals[i] * colTotals[j] / total
+			result.Expected[i][j] = expected
+			if expected == 0 {
+				continue
+			}
+			var value float64
+			if j < len(row) {
+				value = row[j]
+			}
+			result.Statistic += (value - expected) * (value - expected) / expected
+		}
+	}
+	result.PValue = ChiSquareSF(result.Statistic, float64(result.DF))
+	result.CramersV = math.Sqrt(result.Statistic / (total * float64(min(rows, cols)-1)))
+	return result, nil
+}
+
+// TTestResult is Welch's two-sample t-test, which does not assume equal
+// variances.
+type TTestResult struct {
+	Statistic float64 `json:"statistic"`
+	DF        float64 `json:"df"`
+	PValue    float64 `json:"p_value"`
+	MeanA     float64 `json:"mean_a"`
+	MeanB     float64 `json:"mean_b"`
+}
+
+// WelchTTest tests whether a and b have the same mean (two-sided).
+func WelchTTest(a, b []float64) (*TTestResult, error) {
+	if len(a) < 2 || len(b) < 2 {
+		return nil, ErrTooFewValues
+	}
+	meanA, varA := meanVariance(a)
+	meanB, varB := meanVariance(b)
+	seA, seB := varA/float64(len(a)), varB/float64(len(b))
+	if seA+seB == 0 {
+		return nil, errors.New("both samples are constant")
+	}
+
+	result := &TTestResult{MeanA: meanA, MeanB: meanB}
+	result.Statistic = (meanA - meanB) / math.Sqrt(seA+seB)
+	result.DF = (seA + seB) * (seA + seB) / (seA*seA/float64(len(a)-1) + seB*seB/float64(len(b)-1))
+	result.PValue = StudentTSF2(result.Statistic, result.DF)
+	return result, nil
+}
+
+// MannWhitneyResult is the Mann–Whitney U test using the normal
+// approximation with tie and continuity corrections.
+type MannWhitneyResult struct {
+	U       float64 `json:"u"`
+	Z       float64 `json:"z"`
+	PValue  float64 `json:"p_value"`
+	MedianA float64 `json:"median_a"`
+	MedianB float64 `json:"median_b"`
+}
+
+// MannWhitneyU tests whether values from a tend to be larger or smaller
+// than values from b (two-sided). U is the statistic of a.
+func MannWhitneyU(a, b []float64) (*MannWhitneyResult, error) {
+	if len(a) == 0 || len(b) == 0 {
+		return nil, ErrTooFewValues
+	}
+	combined := append(append([]float64(nil), a...), b...)
+	ranks, tieTerm := rank(combined)
+
+	var rankSumA float64
+	for i := range a {
+		rankSumA += ranks[i]
+	}
+	n1, n2 := float64(len(a)), float64(len(b))
+	n := n1 + n2
+	result := &MannWhitneyResult{
+		U:       rankSumA - n1*(n1+1)/2,
+		MedianA: Median(a),
+		MedianB: Median(b),
+	}
+
+	mean := n1 * n2 / 2
+	variance := n1 * n2 / 12 * ((n + 1) - tieTerm/(n*(n-1)))
+	if variance <= 0 {
+		return nil, errors.New("all values are tied")
+	}
+	diff := math.Abs(result.U-mean) - 0.5
+	if diff < 0 {
+		diff = 0
+	}
+	result.Z = math.Copysign(diff/math.Sqrt(variance), result.U-mean)
+	result.PValue = math.Min(1, 2*NormalSF(math.Abs(result.Z)))
+	return result, nil
+}
+
+// CorrelationResult is a correlation coefficient with the two-sided
+// p-value of the t-test of no correlation.
+type CorrelationResult struct {
+	Coefficient float64 `json:"coefficient"`
+	PValue      float64 `json:"p_value"`
+	N           int     `json:"n"`
+}
+
+// Pearson is the linear correlation of paired samples x and y.
+func Pearson(x, y []float64) (*CorrelationResult, error) {
+	if len(x) != len(y) {
+		return nil, errors.New("samples must have the same length")
+	}
+	if len(x) < 3 {
+		return nil, ErrTooFewValues
+	}
+	meanX, _ := meanVariance(x)
+	meanY, _ := meanVariance(y)
+	var sxy, sxx, syy float64
+	for i := range x {
+		dx, dy := x[i]-meanX, y[i]-meanY
+		sxy += dx * dy
+		sxx += dx * dx
+		syy += dy * dy
+	}
+	if sxx == 0 || syy == 0 {
+		return nil, errors.New("correlation is undefined for a constant sample")
+	}
+
+	r := math.Max(-1, math.Min(1, sxy/math.Sqrt(sxx*syy)))
+	result := &CorrelationResult{Coefficient: r, N: len(x)}
+	df := float64(len(x) - 2)
+	if math.Abs(r) == 1 {
+		result.PValue = 0
+	} else {
+		result.PValue = StudentTSF2(r*math.Sqrt(df/(1-r*r)), df)
+	}
+	return result, nil
+}
+
+// Spearman is the rank correlation of paired samples x and y: Pearson's
+// correlation of their ranks, with ties sharing their average rank.
+func Spearman(x, y []float64) (*CorrelationResult, error) {
+	if len(x) != len(y) {
+		return nil, errors.New("samples must have the same length")
+	}
+	rankX, _ := rank(x)
+	rankY, _ := rank(y)
+	return Pearson(rankX, rankY)
+}
+
+// Median returns the median of values without modifying them.
+func Median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	n := len(sorted)
+	if n%2 == 1 {
+		return sorted[n/2]
+	}
+	return (sorted[n/2-1] + sorted[n/2]) / 2
+}
+
+// rank returns the 1-based ranks of values, ties sharing their average
+// rank, and the tie correction term sum(t^3 - t) over groups of t ties.
+func rank(values []float64) ([]float64, float64) {
+	order := make([]int, len(values))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool { return values[order[i]] < values[order[j]] })
+
+	ranks := make([]float64, len(values))
+	var tieTerm float64
+	for start := 0; start < len(order); {
+		end := start
+		for end < len(order) && values[order[end]] == values[order[start]] {
+			end++
+		}
+		average := float64(start+end+1) / 2
+		for _, i := range order[start:end] {
+			ranks[i] = average
+		}
+		t := float64(end - start)
+		tieTerm += t*t*t - t
+		start = end
+	}
+	return ranks, tieTerm
+}
+
+func meanVariance(values []float64) (float64, float64) {
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	mean := sum / float64(len(values))
+	if len(values) < 2 {
+		return mean, 0
+	}
+	var squares float64
+	for _, v := range values {
+		squares += (v - mean) * (v - mean)
+	}
+	return mean, squares / float64(len(values)-1)
+}
@@ -0,0 +1,139 @@
+package stats
+
+import (
+	"math"
+	"testing"
+)
+
+// The reference values come from the examples in the R and SciPy
+// documentation, or from closed forms. Values R prints to four or five
+// significant digits are compared with a matching tolerance.
+
+func TestChiSquare(t *testing.T) {
+	tests := []struct {
+		name      string
+		observed  [][]float64
+		statistic float64
+		df        int
+		pValue    float64
+		tolerance float64
+	}{
+		{
+			// R: chisq.test(as.table(rbind(c(762, 327, 468), c(484, 239, 477))))
+			name:      "R chisq.test example",
+			observed:  [][]float64{{762, 327, 468}, {484, 239, 477}},
+			statistic: 30.07, df: 2, pValue: 2.954e-07,
+			tolerance: 5e-4,
+		},
+		{
+			// scipy.stats.chi2_contingency(..., correction=False)
+			name:      "Titanic Pclass by Survived",
+			observed:  [][]float64{{136, 80}, {87, 97}, {119, 372}},
+			statistic: 102.88898875696056, df: 2, pValue: 4.549251711298793e-23,
+			tolerance: 1e-9,
+		},
+		{
+			// Closed form for 2x2 tables: n(ad - bc)^2 / (r1 r2 c1 c2), and
+			// P(X > x) = erfc(sqrt(x / 2)) for one degree of freedom
+			name:      "2x2 closed form",
+			observed:  [][]float64{{12, 7}, {5, 7}},
+			statistic: 31 * 49 * 49 / (19.0 * 12 * 17 * 14), df: 1, pValue: math.Erfc(math.Sqrt(31 * 49 * 49 / (19.0 * 12 * 17 * 14) / 2)),
+			tolerance: 1e-9,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ChiSquare(tt.observed)
+			if err != nil {
+				t.Fatalf("ChiSquare: %v", err)
+			}
+			if !closeTo(result.Statistic, tt.statistic, tt.tolerance) || result.DF != tt.df || !closeTo(result.PValue, tt.pValue, tt.tolerance) {
+				t.Errorf("got statistic %v, df %d, p %v; want %v, %d, %v", result.Statistic, result.DF, result.PValue, tt.statistic, tt.df, tt.pValue)
+			}
+		})
+	}
+}
+
+func TestWelchTTest(t *testing.T) {
+	// R: t.test(extra ~ group, data = sleep)
+	group1 := []float64{0.7, -1.6, -0.2, -1.2, -0.1, 3.4, 3.7, 0.8, 0.0, 2.0}
+	group2 := []float64{1.9, 0.8, 1.1, 0.1, -0.1, 4.4, 5.5, 1.6, 4.6, 3.4}
+
+	result, err := WelchTTest(group1, group2)
+	if err != nil {
+		t.Fatalf("WelchTTest: %v", err)
+	}
+	if !closeTo(result.Statistic, -1.8608, 5e-4) || !closeTo(result.DF, 17.776, 5e-4) || !closeTo(result.PValue, 0.07939, 5e-4) {
+		t.Errorf("got t %v, df %v, p %v; want -1.8608, 17.776, 0.07939", result.Statistic, result.DF, result.PValue)
+	}
+	if !closeTo(result.MeanA, 0.75, 1e-12) || !closeTo(result.MeanB, 2.33, 1e-12) {
+		t.Errorf("got means %v and %v, want 0.75 and 2.33", result.MeanA, result.MeanB)
+	}
+}
+
+func TestMannWhitneyU(t *testing.T) {
+	tests := []struct {
+		name      string
+		a, b      []float64
+		u, pValue float64
+		tolerance float64
+	}{
+		{
+			// scipy.stats.mannwhitneyu(males, females, method="asymptotic")
+			name: "SciPy example",
+			a:    []float64{19, 22, 16, 29, 24},
+			b:    []float64{20, 11, 17, 12},
+			u:    17, pValue: 0.11134688653314041,
+			tolerance: 1e-9,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := MannWhitneyU(tt.a, tt.b)
+			if err != nil {
+				t.Fatalf("MannWhitneyU: %v", err)
+			}
+			if result.U != tt.u || !closeTo(result.PValue, tt.pValue, tt.tolerance) {
+				t.Errorf("got U %v, p %v; want %v, %v", result.U, result.PValue, tt.u, tt.pValue)
+			}
+		})
+	}
+}
+
+func TestCorrelation(t *testing.T) {
+	tests := []struct {
+		name        string
+		method      func(x, y []float64) (*CorrelationResult, error)
+		x, y        []float64
+		coefficient float64
+		pValue      float64
+	}{
+		{
+			// scipy.stats.pearsonr
+			name:        "Pearson",
+			method:      Pearson,
+			x:           []float64{1, 2, 3, 4, 5},
+			y:           []float64{10, 9, 2.5, 6, 4},
+			coefficient: -0.7426106572325056, pValue: 0.15055580885344558,
+		},
+		{
+			// scipy.stats.spearmanr, with a tie in y
+			name:        "Spearman",
+			method:      Spearman,
+			x:           []float64{1, 2, 3, 4, 5},
+			y:           []float64{5, 6, 7, 8, 7},
+			coefficient: 0.8207826816681233, pValue: 0.08858700531354381,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tt.method(tt.x, tt.y)
+			if err != nil {
+				t.Fatalf("%s: %v", tt.name, err)
+			}
+			if !closeTo(result.Coefficient, tt.coefficient, 1e-9) || !closeTo(result.PValue, tt.pValue, 1e-9) || result.N != len(tt.x) {
+				t.Errorf("got r %v, p %v, n %d; want %v, %v, %d", result.Coefficient, result.PValue, result.N, tt.coefficient, tt.pValue, len(tt.x))
+			}
+		})
+	}
+}